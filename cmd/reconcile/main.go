@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	_ "github.com/lib/pq"
+
+	"github.com/s21platform/society-service/internal/config"
+	db "github.com/s21platform/society-service/internal/repository/postgres"
+)
+
+// Сверяет денормализованные счётчики участников с society_members.
+// Без -fix только выводит расхождения, с -fix пересчитывает их.
+func main() {
+	fix := flag.Bool("fix", false, "recompute drifted counters instead of only reporting them")
+	flag.Parse()
+
+	cfg := config.MustLoad()
+
+	dbRepo, err := db.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to db.New: %v", err)
+	}
+	defer dbRepo.Close()
+
+	drifts, err := dbRepo.ReconcileMemberCounts(context.Background(), *fix)
+	if err != nil {
+		log.Fatalf("failed to ReconcileMemberCounts: %v", err)
+	}
+
+	for _, drift := range drifts {
+		log.Printf("society %s: member_count = %d, actual = %d", drift.SocietyUUID, drift.Stored, drift.Actual)
+	}
+	if *fix {
+		log.Printf("recomputed counters for %d societies", len(drifts))
+	} else {
+		log.Printf("found %d societies with drifted counters", len(drifts))
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -15,6 +16,7 @@ import (
 	society "github.com/s21platform/society-proto/society-proto"
 
 	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/repository/cache"
 	db "github.com/s21platform/society-service/internal/repository/postgres"
 	"github.com/s21platform/society-service/internal/service"
 )
@@ -23,6 +25,11 @@ func main() {
 	// чтение конфига
 	cfg := config.MustLoad()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(cfg, os.Args[2:])
+		return
+	}
+
 	logger := logger_lib.New(cfg.Logger.Host, cfg.Logger.Port, cfg.Service.Name, cfg.Platform.Env)
 
 	dbRepo, err := db.New(cfg)
@@ -33,7 +40,19 @@ func main() {
 	}
 	defer dbRepo.Close()
 
-	server := service.New(dbRepo)
+	if cfg.Postgres.AutoMigrate {
+		if err := dbRepo.Migrate(context.Background(), db.MigrateUp, os.Stdout); err != nil {
+			logger.Error(fmt.Sprintf("failed to migrate: %v", err))
+			os.Exit(1)
+		}
+	}
+
+	var repo service.DbRepo = dbRepo
+	if cfg.Cache.Enabled {
+		repo = cache.New(dbRepo, cache.NewLRU(cfg.Cache.Size), cfg.Cache)
+	}
+
+	server := service.New(repo)
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			infra.Verifcation,
@@ -42,6 +61,10 @@ func main() {
 	)
 	society.RegisterSocietyServiceServer(s, server)
 
+	if cfg.Service.MetricsPort != "" {
+		go serveMetrics(logger, cfg.Service.MetricsPort)
+	}
+
 	logger.Info(fmt.Sprintf("starting server %v", cfg.Service.Port))
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Service.Port))
 	if err != nil {
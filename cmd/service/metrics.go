@@ -0,0 +1,20 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+
+	logger_lib "github.com/s21platform/logger-lib"
+)
+
+// serveMetrics отдаёт expvar (счётчики кэша society_cache и рантайм) на /debug/vars
+func serveMetrics(logger *logger_lib.Logger, port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	logger.Info(fmt.Sprintf("starting metrics server %v", port))
+	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), mux); err != nil {
+		logger.Error(fmt.Sprintf("failed to start metrics server: %s; Error: %s", port, err))
+	}
+}
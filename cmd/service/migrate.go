@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/s21platform/society-service/internal/config"
+	db "github.com/s21platform/society-service/internal/repository/postgres"
+)
+
+// runMigrate выполняет `service migrate [up|down|status|redo]`, по умолчанию up
+func runMigrate(cfg *config.Config, args []string) {
+	command := db.MigrateUp
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	dbRepo, err := db.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to db.New: %v", err)
+	}
+	defer dbRepo.Close()
+
+	if err := dbRepo.Migrate(context.Background(), command, os.Stdout); err != nil {
+		log.Fatalf("failed to migrate: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/s21platform/society-service/internal/config"
+	db "github.com/s21platform/society-service/internal/repository/postgres"
+)
+
+// Переводит ожидающие заявки старше request_ttl_hours своего сообщества в статус expired
+// и пишет события для уведомлений в society_outbox, а затем отправляет накопившиеся события
+// на SOCIETY_SERVICE_OUTBOX_WEBHOOK_URL. Реплики можно запускать параллельно: пачку заявок
+// обрабатывает та, что взяла advisory-блокировку, а события outbox разбираются через SKIP LOCKED.
+func main() {
+	once := flag.Bool("once", false, "expire all stale requests, publish the outbox once and exit")
+	flag.Parse()
+
+	cfg := config.MustLoad()
+	if cfg.Expiry.Interval <= 0 {
+		log.Fatalf("SOCIETY_SERVICE_EXPIRY_INTERVAL must be positive, got %v", cfg.Expiry.Interval)
+	}
+	if cfg.Expiry.BatchSize == 0 || cfg.Outbox.BatchSize == 0 {
+		log.Fatal("SOCIETY_SERVICE_EXPIRY_BATCH_SIZE and SOCIETY_SERVICE_OUTBOX_BATCH_SIZE must be positive")
+	}
+	if cfg.Outbox.WebhookURL == "" {
+		log.Fatal("SOCIETY_SERVICE_OUTBOX_WEBHOOK_URL is required")
+	}
+	publisher := newWebhookPublisher(cfg.Outbox.WebhookURL, cfg.Outbox.Timeout)
+
+	dbRepo, err := db.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to db.New: %v", err)
+	}
+	defer dbRepo.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		expireAll(ctx, dbRepo, cfg.Expiry.BatchSize)
+		publishAll(ctx, dbRepo, publisher, cfg.Outbox.BatchSize)
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Expiry.Interval)
+	defer ticker.Stop()
+
+	for {
+		expireAll(ctx, dbRepo, cfg.Expiry.BatchSize)
+		publishAll(ctx, dbRepo, publisher, cfg.Outbox.BatchSize)
+
+		select {
+		case <-ctx.Done():
+			log.Println("expire_requests worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expireAll обрабатывает пачки, пока не кончатся просроченные заявки или блокировку не возьмёт другая реплика
+func expireAll(ctx context.Context, dbRepo *db.Repository, batchSize uint64) {
+	total := 0
+	for ctx.Err() == nil {
+		expired, locked, err := dbRepo.ExpirePendingRequests(ctx, batchSize)
+		if err != nil {
+			log.Printf("failed to ExpirePendingRequests: %v", err)
+			return
+		}
+		if !locked {
+			log.Println("another replica holds the expiry lock, skipping")
+			return
+		}
+
+		total += len(expired)
+		if uint64(len(expired)) < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("expired %d pending requests", total)
+	}
+}
+
+// publishAll отправляет пачки outbox, пока они не кончатся или отправка не упадёт;
+// неотправленное остаётся в society_outbox до следующего тика
+func publishAll(ctx context.Context, dbRepo *db.Repository, publisher *webhookPublisher, batchSize uint64) {
+	total := 0
+	for ctx.Err() == nil {
+		published, err := dbRepo.PublishOutbox(ctx, batchSize, publisher.publish)
+		if err != nil {
+			log.Printf("failed to PublishOutbox: %v", err)
+			break
+		}
+		total += published
+		if uint64(published) < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("published %d outbox events", total)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// webhookPublisher отправляет пачку событий outbox одним POST; любой ответ, кроме 2xx, — ошибка,
+// и пачка остаётся неотправленной до следующего тика
+type webhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookPublisher(url string, timeout time.Duration) *webhookPublisher {
+	return &webhookPublisher{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *webhookPublisher) publish(ctx context.Context, events []model.OutboxEvent) error {
+	body, err := json.Marshal(struct {
+		Events []model.OutboxEvent `json:"events"`
+	}{Events: events})
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build outbox request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send outbox events: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("outbox webhook responded %s", resp.Status)
+	}
+	return nil
+}
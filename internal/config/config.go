@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -11,12 +12,17 @@ type Config struct {
 	Postgres Postgres
 	Platform Platform
 	Logger   Logger
+	Cache    Cache
+	Expiry   Expiry
+	Outbox   Outbox
 }
 
 type Service struct {
 	Port string `env:"SOCIETY_SERVICE_PORT"`
 	Host string `env:"SOCIETY_SERVICE_HOST"`
 	Name string `env:"SOCIETY_SERVICE_NAME"`
+	// MetricsPort — порт HTTP с expvar на /debug/vars (в том числе счётчики кэша); пустой — не поднимается
+	MetricsPort string `env:"SOCIETY_SERVICE_METRICS_PORT"`
 }
 
 type Postgres struct {
@@ -25,6 +31,54 @@ type Postgres struct {
 	Database string `env:"SOCIETY_SERVICE_POSTGRES_DB"`
 	Host     string `env:"SOCIETY_SERVICE_POSTGRES_HOST"`
 	Port     string `env:"SOCIETY_SERVICE_POSTGRES_PORT"`
+
+	SSLMode     string `env:"SOCIETY_SERVICE_POSTGRES_SSLMODE" env-default:"disable"`
+	SSLRootCert string `env:"SOCIETY_SERVICE_POSTGRES_SSLROOTCERT"`
+	SSLCert     string `env:"SOCIETY_SERVICE_POSTGRES_SSLCERT"`
+	SSLKey      string `env:"SOCIETY_SERVICE_POSTGRES_SSLKEY"`
+
+	MaxOpenConns     int           `env:"SOCIETY_SERVICE_POSTGRES_MAX_OPEN_CONNS" env-default:"20"`
+	MaxIdleConns     int           `env:"SOCIETY_SERVICE_POSTGRES_MAX_IDLE_CONNS" env-default:"10"`
+	ConnMaxLifetime  time.Duration `env:"SOCIETY_SERVICE_POSTGRES_CONN_MAX_LIFETIME" env-default:"30m"`
+	ConnMaxIdleTime  time.Duration `env:"SOCIETY_SERVICE_POSTGRES_CONN_MAX_IDLE_TIME" env-default:"5m"`
+	StatementTimeout time.Duration `env:"SOCIETY_SERVICE_POSTGRES_STATEMENT_TIMEOUT" env-default:"5s"`
+
+	ConnectAttempts       int           `env:"SOCIETY_SERVICE_POSTGRES_CONNECT_ATTEMPTS" env-default:"8"`
+	ConnectInitialBackoff time.Duration `env:"SOCIETY_SERVICE_POSTGRES_CONNECT_INITIAL_BACKOFF" env-default:"500ms"`
+	ConnectMaxBackoff     time.Duration `env:"SOCIETY_SERVICE_POSTGRES_CONNECT_MAX_BACKOFF" env-default:"10s"`
+
+	TxMaxRetries   int           `env:"SOCIETY_SERVICE_POSTGRES_TX_MAX_RETRIES" env-default:"3"`
+	TxRetryBackoff time.Duration `env:"SOCIETY_SERVICE_POSTGRES_TX_RETRY_BACKOFF" env-default:"20ms"`
+
+	// AutoMigrate накатывает встроенные миграции при старте сервиса
+	AutoMigrate bool `env:"SOCIETY_SERVICE_POSTGRES_AUTO_MIGRATE" env-default:"false"`
+
+	ReplicaDSNs           []string      `env:"SOCIETY_SERVICE_POSTGRES_REPLICA_DSNS" env-separator:";"`
+	ReplicaHealthInterval time.Duration `env:"SOCIETY_SERVICE_POSTGRES_REPLICA_HEALTH_INTERVAL" env-default:"5s"`
+	ReadYourWritesWindow  time.Duration `env:"SOCIETY_SERVICE_POSTGRES_READ_YOUR_WRITES_WINDOW" env-default:"2s"`
+}
+
+type Cache struct {
+	Enabled           bool          `env:"SOCIETY_SERVICE_CACHE_ENABLED" env-default:"false"`
+	Size              int           `env:"SOCIETY_SERVICE_CACHE_SIZE" env-default:"10000"`
+	SocietyInfoTTL    time.Duration `env:"SOCIETY_SERVICE_CACHE_SOCIETY_INFO_TTL" env-default:"5m"`
+	TagsTTL           time.Duration `env:"SOCIETY_SERVICE_CACHE_TAGS_TTL" env-default:"5m"`
+	InfoSocietyTTL    time.Duration `env:"SOCIETY_SERVICE_CACHE_INFO_SOCIETY_TTL" env-default:"5m"`
+	CountSubscribeTTL time.Duration `env:"SOCIETY_SERVICE_CACHE_COUNT_SUBSCRIBE_TTL" env-default:"30s"`
+}
+
+// Expiry настраивает воркер cmd/workers/expire_requests
+type Expiry struct {
+	Interval  time.Duration `env:"SOCIETY_SERVICE_EXPIRY_INTERVAL" env-default:"1m"`
+	BatchSize uint64        `env:"SOCIETY_SERVICE_EXPIRY_BATCH_SIZE" env-default:"500"`
+}
+
+// Outbox настраивает отправку society_outbox из воркера cmd/workers/expire_requests
+type Outbox struct {
+	// WebhookURL принимает POST с JSON {"events": [...]}; ответ 2xx подтверждает всю пачку
+	WebhookURL string        `env:"SOCIETY_SERVICE_OUTBOX_WEBHOOK_URL"`
+	BatchSize  uint64        `env:"SOCIETY_SERVICE_OUTBOX_BATCH_SIZE" env-default:"100"`
+	Timeout    time.Duration `env:"SOCIETY_SERVICE_OUTBOX_TIMEOUT" env-default:"10s"`
 }
 
 type Platform struct {
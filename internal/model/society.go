@@ -1,6 +1,11 @@
 package model
 
-import "database/sql"
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 type SocietyData struct {
 	Name           string
@@ -21,8 +26,41 @@ type SocietyInfo struct {
 	CountSubscribe int64          `db:"-"`
 	TagsID         []int64        `db:"-"`
 	CanEditSociety bool           `db:"-"`
+	PeerRole       int            `db:"-"`
+	// EditorRole роль для прав на сообщество, как у IsOwnerAdminModerator: с модерацией, унаследованной от предков
+	EditorRole int   `db:"-"`
+	IsMember   bool  `db:"-"`
+	IsPending  bool  `db:"-"`
+	MaxMembers int64 `db:"-"` // 0 — без ограничения
+	// WaitlistPosition место пользователя в очереди начиная с 1; 0 — пользователь не в очереди
+	WaitlistPosition int64          `db:"-"`
+	Announcements    []Announcement `db:"-"` // активные закреплённые объявления, новые первыми
 }
 
+// SocietyPeerState — данные GetSocietyInfoForPeer, которые в отличие от сообщества не кэшируются:
+// состояние пользователя и активные объявления, которые истекают по времени
+type SocietyPeerState struct {
+	Role             int            `db:"role"`
+	EditorRole       int            `db:"editor_role"`
+	IsPending        bool           `db:"is_pending"`
+	WaitlistPosition int64          `db:"waitlist_position"`
+	Announcements    []Announcement `db:"-"`
+}
+
+// SetPeerState дополняет сообщество состоянием пользователя
+func (s *SocietyInfo) SetPeerState(state SocietyPeerState) {
+	s.PeerRole = state.Role
+	s.EditorRole = state.EditorRole
+	s.IsMember = state.Role != 0
+	s.IsPending = state.IsPending
+	s.WaitlistPosition = state.WaitlistPosition
+	s.Announcements = state.Announcements
+}
+
+// ErrAlreadyMember — пользователь уже состоит в сообществе; повторное вступление не добавляет
+// вторую строку участника и не ставит его в очередь
+var ErrAlreadyMember = errors.New("user is already a member of the society")
+
 type SocietyWithOffset struct {
 	Society []SocietyWithOffsetData
 	Total   int64
@@ -34,6 +72,41 @@ type SocietyWithOffsetData struct {
 	PhotoURL    string `db:"photo_url"`
 	IsMember    bool   `db:"is_member"`
 	FormatId    int64  `db:"format_id"`
+	MemberID    int64  `db:"member_id"`
+}
+
+type SocietySummary struct {
+	SocietyUUID    string `db:"id"`
+	Name           string `db:"name"`
+	PhotoURL       string `db:"photo_url"`
+	FormatID       int64  `db:"format_id"`
+	CountSubscribe int64  `db:"count_subscribe"`
+	Role           int64  `db:"role"`
+	// ParentUUID и ParentName пустые у сообществ верхнего уровня; группировку отделений под родителем делает вызывающая сторона
+	ParentUUID string `db:"parent_id"`
+	ParentName string `db:"parent_name"`
+}
+
+// SocietyNode — сообщество в иерархии; Depth — расстояние от запрошенного сообщества (1 — непосредственный родитель или ребёнок)
+type SocietyNode struct {
+	SocietyUUID             string `db:"id"`
+	Name                    string `db:"name"`
+	PhotoURL                string `db:"photo_url"`
+	ParentUUID              string `db:"parent_id"`
+	InheritParentModeration bool   `db:"inherit_parent_moderation"`
+	Depth                   int64  `db:"depth"`
+}
+
+// ErrHierarchyTooDeep — у будущего родителя столько предков, что проверка цикла упёрлась
+// в ограничение глубины; такая привязка отклоняется, а не считается безопасной
+var ErrHierarchyTooDeep = errors.New("society hierarchy is too deep")
+
+type UserSocietiesFilter struct {
+	UserUUID   string
+	CallerUUID string
+	Limit      uint64
+	Offset     uint64
+	AfterID    int64 // id записи society_members, на которой закончилась предыдущая страница
 }
 
 type WithOffsetData struct {
@@ -46,3 +119,312 @@ type WithOffsetData struct {
 type Role struct {
 	Role int `db:"role"`
 }
+
+type MemberCounter struct {
+	Role          int64 `db:"role"`
+	PaymentStatus int64 `db:"payment_status"`
+	Count         int64 `db:"count"`
+}
+
+type MemberCountDrift struct {
+	SocietyUUID string `db:"society_id"`
+	Stored      int64  `db:"stored"`
+	Actual      int64  `db:"actual"`
+}
+
+type DailyMembership struct {
+	Day    time.Time `db:"day"`
+	Joins  int64     `db:"joins"`
+	Leaves int64     `db:"leaves"`
+}
+
+type RequestStats struct {
+	Pending            int64   `db:"pending"`
+	Approved           int64   `db:"approved"`
+	Rejected           int64   `db:"rejected"`
+	AvgApprovalSeconds float64 `db:"avg_approval_seconds"`
+}
+
+const (
+	MemberEventJoin          = "join"
+	MemberEventLeave         = "leave"
+	MemberEventKick          = "kick"
+	MemberEventRoleChange    = "role_change"
+	MemberEventPaymentChange = "payment_change"
+)
+
+type MembershipEvent struct {
+	ID                    int64     `db:"id"`
+	SocietyUUID           string    `db:"society_id"`
+	UserUUID              string    `db:"user_uuid"`
+	EventType             string    `db:"event_type"`
+	Role                  int64     `db:"role"`
+	PreviousRole          int64     `db:"previous_role"`
+	PaymentStatus         int64     `db:"payment_status"`
+	PreviousPaymentStatus int64     `db:"previous_payment_status"`
+	ActorUUID             string    `db:"actor_uuid"`
+	CreatedAt             time.Time `db:"created_at"`
+}
+
+// MembershipTimelineFilter задаёт ровно одно из UserUUID и SocietyUUID
+type MembershipTimelineFilter struct {
+	UserUUID    string
+	SocietyUUID string
+	Limit       uint64
+	AfterID     int64 // id события, на котором закончилась предыдущая страница
+}
+
+type SocietyRule struct {
+	Title string `db:"title"`
+	Body  string `db:"body"`
+}
+
+// SocietyRules текущая версия правил; Version 0 — правил у сообщества нет
+type SocietyRules struct {
+	Version int64
+	Items   []SocietyRule
+}
+
+type Announcement struct {
+	ID          int64        `db:"id"`
+	SocietyUUID string       `db:"society_id"`
+	AuthorUUID  string       `db:"author_uuid"`
+	Title       string       `db:"title"`
+	Body        string       `db:"body"`
+	ExpiresAt   sql.NullTime `db:"expires_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+const (
+	QuestionText         = "text"
+	QuestionSingleChoice = "single_choice"
+	QuestionMultiChoice  = "multi_choice"
+)
+
+type SocietyQuestion struct {
+	ID         int64
+	Kind       string
+	Prompt     string
+	Options    []string
+	IsRequired bool
+}
+
+// RequestAnswer хранит текст вопроса на момент подачи заявки
+type RequestAnswer struct {
+	QuestionID int64
+	Prompt     string
+	Values     []string
+}
+
+type MembersRequest struct {
+	ID                   int64
+	UserUUID             string
+	SocietyUUID          string
+	AcceptedRulesVersion int64
+	Answers              []RequestAnswer
+	CreatedAt            time.Time
+}
+
+type PendingRequestsFilter struct {
+	SocietyUUID string
+	Limit       uint64
+	AfterID     int64 // id заявки, на которой закончилась предыдущая страница
+}
+
+const (
+	AutoApproveMemberOf   = "member_of"
+	AutoApproveDailyLimit = "daily_limit"
+	AutoApproveInviteCode = "invite_code"
+)
+
+// AutoApprovalRule заполняет только поле своего вида: MemberOfSocietyUUID, DailyLimit или InviteCode
+type AutoApprovalRule struct {
+	ID                  int64  `db:"id"`
+	Kind                string `db:"kind"`
+	MemberOfSocietyUUID string `db:"member_of_society"`
+	DailyLimit          int64  `db:"daily_limit"`
+	InviteCode          string `db:"invite_code"`
+}
+
+const OutboxRequestExpired = "request_expired"
+
+type ExpiredRequest struct {
+	ID          int64  `db:"id"`
+	SocietyUUID string `db:"society_id"`
+	UserUUID    string `db:"user_uuid"`
+}
+
+// OutboxEvent — событие из society_outbox; ID растёт и годится получателю для дедупликации
+type OutboxEvent struct {
+	ID          int64           `db:"id" json:"id"`
+	EventType   string          `db:"event_type" json:"event_type"`
+	SocietyUUID string          `db:"society_id" json:"society_uuid"`
+	UserUUID    string          `db:"user_uuid" json:"user_uuid"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Channel — подпространство сообщества; MemberCount и IsMember (для запрашивающего) только на чтение
+type Channel struct {
+	ID             string    `db:"id"`
+	SocietyUUID    string    `db:"society_id"`
+	Name           string    `db:"name"`
+	Description    string    `db:"description"`
+	FormatID       int64     `db:"format_id"`
+	PostPermission int64     `db:"post_permission_id"`
+	MemberCount    int64     `db:"member_count"`
+	IsMember       bool      `db:"is_member"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+var (
+	// ErrChannelNameTaken — в сообществе уже есть канал с таким именем
+	ErrChannelNameTaken = errors.New("channel name is taken")
+	// ErrTooManyChannels — у сообщества уже столько каналов, сколько разрешено
+	ErrTooManyChannels = errors.New("society has too many channels")
+)
+
+// SocietyEvent — встреча или хакатон сообщества. Capacity 0 — без ограничения;
+// SocietyName, GoingCount и IsGoing (для запрашивающего) только на чтение
+type SocietyEvent struct {
+	ID          string       `db:"id"`
+	SocietyUUID string       `db:"society_id"`
+	SocietyName string       `db:"society_name"`
+	AuthorUUID  string       `db:"author_uuid"`
+	Title       string       `db:"title"`
+	Description string       `db:"description"`
+	StartsAt    time.Time    `db:"starts_at"`
+	EndsAt      time.Time    `db:"ends_at"`
+	Location    string       `db:"location"`
+	Link        string       `db:"link"`
+	Capacity    int64        `db:"capacity"`
+	CancelledAt sql.NullTime `db:"cancelled_at"`
+	GoingCount  int64        `db:"going_count"`
+	IsGoing     bool         `db:"is_going"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at"`
+}
+
+// SocietyEventsFilter — события, которые ещё не закончились к From и начинаются до To (нулевой To — без границы)
+type SocietyEventsFilter struct {
+	SocietyUUID string
+	PeerUUID    string
+	From        time.Time
+	To          time.Time
+	Limit       uint64
+}
+
+const (
+	PollResultsLive       = "live"
+	PollResultsAfterClose = "after_close"
+)
+
+// Poll — опрос сообщества. VoterCount и HasVoted (для запрашивающего) только на чтение
+type Poll struct {
+	ID                string       `db:"id"`
+	SocietyUUID       string       `db:"society_id"`
+	AuthorUUID        string       `db:"author_uuid"`
+	Question          string       `db:"question"`
+	IsMultiple        bool         `db:"is_multiple"`
+	IsAnonymous       bool         `db:"is_anonymous"`
+	ResultsVisibility string       `db:"results_visibility"`
+	ClosesAt          sql.NullTime `db:"closes_at"`
+	CreatedAt         time.Time    `db:"created_at"`
+	VoterCount        int64        `db:"voter_count"`
+	HasVoted          bool         `db:"has_voted"`
+	Options           []PollOption `db:"-"`
+}
+
+func (p Poll) IsClosed(now time.Time) bool {
+	return p.ClosesAt.Valid && !p.ClosesAt.Time.After(now)
+}
+
+// PollOption — вариант ответа; VoterUUIDs пуст в анонимных опросах
+type PollOption struct {
+	ID         int64    `db:"id"`
+	PollUUID   string   `db:"poll_id"`
+	Position   int64    `db:"position"`
+	Text       string   `db:"text"`
+	VoteCount  int64    `db:"vote_count"`
+	VoterUUIDs []string `db:"-"`
+}
+
+type SocietyPollsFilter struct {
+	SocietyUUID string
+	PeerUUID    string
+	Limit       uint64
+}
+
+// виды активности сообщества, о которых участник выбирает получать уведомления
+const (
+	NotifyPosts         = "posts"
+	NotifyAnnouncements = "announcements"
+	NotifyEvents        = "events"
+	NotifyJoinRequests  = "join_requests" // только владельцу, админам и модераторам
+)
+
+const (
+	NotifyDeliveryInstant = "instant"
+	NotifyDeliveryDigest  = "digest"
+)
+
+// NotificationSettings — настройки уведомлений участника в сообществе; Muted без MutedUntil — бессрочно
+type NotificationSettings struct {
+	SocietyUUID   string       `db:"society_id"`
+	UserUUID      string       `db:"user_uuid"`
+	Posts         bool         `db:"notify_posts"`
+	Announcements bool         `db:"notify_announcements"`
+	Events        bool         `db:"notify_events"`
+	JoinRequests  bool         `db:"notify_join_requests"`
+	Delivery      string       `db:"notify_delivery"`
+	Muted         bool         `db:"notify_muted"`
+	MutedUntil    sql.NullTime `db:"notify_muted_until"`
+}
+
+// DefaultNotificationSettings — настройки нового участника: всё включено и приходит сразу
+func DefaultNotificationSettings(societyUUID, userUUID string) NotificationSettings {
+	return NotificationSettings{
+		SocietyUUID:   societyUUID,
+		UserUUID:      userUUID,
+		Posts:         true,
+		Announcements: true,
+		Events:        true,
+		JoinRequests:  true,
+		Delivery:      NotifyDeliveryInstant,
+	}
+}
+
+func (n NotificationSettings) IsMuted(now time.Time) bool {
+	return n.Muted && (!n.MutedUntil.Valid || n.MutedUntil.Time.After(now))
+}
+
+// Wants — хочет ли участник уведомления об activity; роль не учитывается
+func (n NotificationSettings) Wants(activity string) bool {
+	switch activity {
+	case NotifyPosts:
+		return n.Posts
+	case NotifyAnnouncements:
+		return n.Announcements
+	case NotifyEvents:
+		return n.Events
+	case NotifyJoinRequests:
+		return n.JoinRequests
+	}
+	return false
+}
+
+type NotificationRecipient struct {
+	MemberID int64  `db:"id"`
+	UserUUID string `db:"user_uuid"`
+	Role     int64  `db:"role"`
+	Delivery string `db:"notify_delivery"`
+}
+
+type NotificationRecipientsFilter struct {
+	SocietyUUID string
+	Activity    string
+	ExcludeUUID string  // автор активности, которому уведомление не нужно
+	Roles       []int64 // пусто — любые роли
+	Limit       uint64
+	AfterID     int64 // id записи society_members, на которой закончилась предыдущая страница
+}
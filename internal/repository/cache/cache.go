@@ -0,0 +1,325 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"time"
+
+	society "github.com/s21platform/society-proto/society-proto"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+const (
+	kindSocietyInfo    = "society_info"
+	kindTags           = "tags"
+	kindInfoSociety    = "info_society"
+	kindCountSubscribe = "count_subscribe"
+)
+
+// Metrics счётчики попаданий и промахов по видам данных, публикуются в expvar как society_cache
+// и отдаются сервисом на /debug/vars
+var Metrics = expvar.NewMap("society_cache")
+
+// Repository read-through кэш поверх service.DbRepo.
+// Методы, которые не кэшируются и ничего не инвалидируют, проксируются во вложенный репозиторий.
+type Repository struct {
+	service.DbRepo
+	backend Backend
+	cfg     config.Cache
+
+	// внутри WithTx чтения идут мимо кэша, а инвалидация откладывается до коммита
+	inTx    bool
+	pending *[]string
+}
+
+func New(repo service.DbRepo, backend Backend, cfg config.Cache) *Repository {
+	return &Repository{
+		DbRepo:  repo,
+		backend: backend,
+		cfg:     cfg,
+	}
+}
+
+func key(kind, societyUUID string) string {
+	return kind + ":" + societyUUID
+}
+
+func societyKeys(societyUUID string) []string {
+	return []string{
+		key(kindSocietyInfo, societyUUID),
+		key(kindTags, societyUUID),
+		key(kindInfoSociety, societyUUID),
+		key(kindCountSubscribe, societyUUID),
+	}
+}
+
+func (r *Repository) load(kind, societyUUID string, dst any) bool {
+	if r.inTx {
+		return false
+	}
+
+	raw, ok := r.backend.Get(key(kind, societyUUID))
+	if ok && json.Unmarshal(raw, dst) == nil {
+		Metrics.Add(kind+"_hits", 1)
+		return true
+	}
+
+	Metrics.Add(kind+"_misses", 1)
+	return false
+}
+
+func (r *Repository) store(kind, societyUUID string, value any, ttl time.Duration) {
+	if r.inTx {
+		return
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	r.backend.Set(key(kind, societyUUID), raw, ttl)
+}
+
+func (r *Repository) invalidate(keys ...string) {
+	if r.inTx {
+		*r.pending = append(*r.pending, keys...)
+		return
+	}
+
+	r.backend.Delete(keys...)
+}
+
+func (r *Repository) WithTx(ctx context.Context, fn func(repo service.DbRepo) error) error {
+	if r.inTx {
+		return r.DbRepo.WithTx(ctx, func(service.DbRepo) error {
+			return fn(r)
+		})
+	}
+
+	var pending []string
+	err := r.DbRepo.WithTx(ctx, func(repo service.DbRepo) error {
+		return fn(&Repository{
+			DbRepo:  repo,
+			backend: r.backend,
+			cfg:     r.cfg,
+			inTx:    true,
+			pending: &pending,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	r.backend.Delete(pending...)
+	return nil
+}
+
+// GetSocietyInfo кэширует сообщество вместе с активными тегами
+func (r *Repository) GetSocietyInfo(ctx context.Context, societyUUID string) (*model.SocietyInfo, error) {
+	var cached model.SocietyInfo
+	if r.load(kindSocietyInfo, societyUUID, &cached) {
+		return &cached, nil
+	}
+
+	societyInfo, err := r.DbRepo.GetSocietyInfo(ctx, societyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(kindSocietyInfo, societyUUID, societyInfo, r.cfg.SocietyInfoTTL)
+	return societyInfo, nil
+}
+
+// GetSocietyInfoForPeer берёт сообщество и число участников из кэша, а состояние пользователя
+// и активные объявления каждый раз запрашивает заново одним запросом
+func (r *Repository) GetSocietyInfoForPeer(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyInfo, error) {
+	if r.inTx {
+		return r.DbRepo.GetSocietyInfoForPeer(ctx, societyUUID, peerUUID)
+	}
+
+	societyInfo, err := r.GetSocietyInfo(ctx, societyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := r.CountSubscribe(ctx, societyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := r.DbRepo.GetSocietyPeerState(ctx, societyUUID, peerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	societyInfo.CountSubscribe = count
+	societyInfo.SetPeerState(*state)
+	return societyInfo, nil
+}
+
+func (r *Repository) GetTags(ctx context.Context, societyUUID string) ([]int64, error) {
+	var cached []int64
+	if r.load(kindTags, societyUUID, &cached) {
+		return cached, nil
+	}
+
+	tags, err := r.DbRepo.GetTags(ctx, societyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(kindTags, societyUUID, tags, r.cfg.TagsTTL)
+	return tags, nil
+}
+
+func (r *Repository) CountSubscribe(ctx context.Context, societyUUID string) (int64, error) {
+	var cached int64
+	if r.load(kindCountSubscribe, societyUUID, &cached) {
+		return cached, nil
+	}
+
+	count, err := r.DbRepo.CountSubscribe(ctx, societyUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.store(kindCountSubscribe, societyUUID, count, r.cfg.CountSubscribeTTL)
+	return count, nil
+}
+
+// GetInfoSociety кэширует каждое сообщество отдельно и дозапрашивает только промахи
+func (r *Repository) GetInfoSociety(ctx context.Context, groups []string) ([]model.SocietyWithOffsetData, error) {
+	found := make(map[string]model.SocietyWithOffsetData, len(groups))
+	var misses []string
+	for _, societyUUID := range groups {
+		var cached model.SocietyWithOffsetData
+		if r.load(kindInfoSociety, societyUUID, &cached) {
+			found[societyUUID] = cached
+			continue
+		}
+		misses = append(misses, societyUUID)
+	}
+
+	if len(misses) > 0 {
+		fetched, err := r.DbRepo.GetInfoSociety(ctx, misses)
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range fetched {
+			found[data.SocietyUUID] = data
+			r.store(kindInfoSociety, data.SocietyUUID, data, r.cfg.InfoSocietyTTL)
+		}
+	}
+
+	result := make([]model.SocietyWithOffsetData, 0, len(found))
+	for _, societyUUID := range groups {
+		if data, ok := found[societyUUID]; ok {
+			result = append(result, data)
+			delete(found, societyUUID)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) UpdateSociety(ctx context.Context, societyData *society.UpdateSocietyIn) error {
+	if err := r.DbRepo.UpdateSociety(ctx, societyData); err != nil {
+		return err
+	}
+
+	r.invalidate(societyKeys(societyData.SocietyUUID)...)
+	return nil
+}
+
+func (r *Repository) RemoveSociety(ctx context.Context, societyUUID string) error {
+	if err := r.DbRepo.RemoveSociety(ctx, societyUUID); err != nil {
+		return err
+	}
+
+	r.invalidate(societyKeys(societyUUID)...)
+	return nil
+}
+
+func (r *Repository) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string) error {
+	if err := r.DbRepo.RemoveSocietyHasTagsEntry(ctx, societyUUID); err != nil {
+		return err
+	}
+
+	r.invalidate(key(kindSocietyInfo, societyUUID), key(kindTags, societyUUID))
+	return nil
+}
+
+func (r *Repository) RemoveSocietyMembersEntry(ctx context.Context, societyUUID string) error {
+	if err := r.DbRepo.RemoveSocietyMembersEntry(ctx, societyUUID); err != nil {
+		return err
+	}
+
+	r.invalidate(key(kindCountSubscribe, societyUUID))
+	return nil
+}
+
+func (r *Repository) AddSocietyMembers(ctx context.Context, uuid string, societyUUID string) (bool, error) {
+	waitlisted, err := r.DbRepo.AddSocietyMembers(ctx, uuid, societyUUID)
+	if err != nil {
+		return false, err
+	}
+
+	r.invalidate(key(kindCountSubscribe, societyUUID))
+	return waitlisted, nil
+}
+
+func (r *Repository) UnSubscribeToSociety(ctx context.Context, uuid string, societyUUID string) error {
+	if err := r.DbRepo.UnSubscribeToSociety(ctx, uuid, societyUUID); err != nil {
+		return err
+	}
+
+	r.invalidate(key(kindCountSubscribe, societyUUID))
+	return nil
+}
+
+func (r *Repository) RemoveSocietyMember(ctx context.Context, uuid string, societyUUID string) error {
+	if err := r.DbRepo.RemoveSocietyMember(ctx, uuid, societyUUID); err != nil {
+		return err
+	}
+
+	r.invalidate(key(kindCountSubscribe, societyUUID))
+	return nil
+}
+
+// SetMaxMembers меняет лимит и может перевести в участники пользователей из очереди
+func (r *Repository) SetMaxMembers(ctx context.Context, societyUUID string, maxMembers int64) error {
+	if err := r.DbRepo.SetMaxMembers(ctx, societyUUID, maxMembers); err != nil {
+		return err
+	}
+
+	r.invalidate(key(kindSocietyInfo, societyUUID), key(kindCountSubscribe, societyUUID))
+	return nil
+}
+
+// CreateSociety сбрасывает ключи нового сообщества на случай, если их успели заполнить
+func (r *Repository) CreateSociety(ctx context.Context, socData *model.SocietyData) (string, error) {
+	societyUUID, err := r.DbRepo.CreateSociety(ctx, socData)
+	if err != nil {
+		return "", err
+	}
+
+	r.invalidate(societyKeys(societyUUID)...)
+	return societyUUID, nil
+}
+
+// AutoApproveMembersRequest добавляет участника, если правило сработало
+func (r *Repository) AutoApproveMembersRequest(ctx context.Context, request *model.MembersRequest, rule *model.AutoApprovalRule) (bool, error) {
+	approved, err := r.DbRepo.AutoApproveMembersRequest(ctx, request, rule)
+	if err != nil {
+		return false, err
+	}
+
+	if approved {
+		r.invalidate(key(kindCountSubscribe, request.SocietyUUID))
+	}
+	return approved, nil
+}
@@ -0,0 +1,398 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	society "github.com/s21platform/society-proto/society-proto"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/repository/memory"
+	"github.com/s21platform/society-service/internal/repository/repotest"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+// fakeRepo считает обращения к базе; неиспользуемые методы DbRepo не реализованы
+type fakeRepo struct {
+	service.DbRepo
+	calls map[string]int
+	tags  []int64
+	count int64
+	txErr error
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{calls: map[string]int{}, tags: []int64{1, 2}, count: 3}
+}
+
+func (f *fakeRepo) WithTx(_ context.Context, fn func(repo service.DbRepo) error) error {
+	if err := fn(f); err != nil {
+		return err
+	}
+	return f.txErr
+}
+
+func (f *fakeRepo) GetSocietyInfo(_ context.Context, societyUUID string) (*model.SocietyInfo, error) {
+	f.calls["GetSocietyInfo"]++
+	return &model.SocietyInfo{Name: "society " + societyUUID, TagsID: f.tags}, nil
+}
+
+func (f *fakeRepo) GetTags(_ context.Context, _ string) ([]int64, error) {
+	f.calls["GetTags"]++
+	return f.tags, nil
+}
+
+func (f *fakeRepo) CountSubscribe(_ context.Context, _ string) (int64, error) {
+	f.calls["CountSubscribe"]++
+	return f.count, nil
+}
+
+func (f *fakeRepo) GetInfoSociety(_ context.Context, groups []string) ([]model.SocietyWithOffsetData, error) {
+	f.calls["GetInfoSociety"] += len(groups)
+	data := make([]model.SocietyWithOffsetData, 0, len(groups))
+	for _, societyUUID := range groups {
+		if societyUUID == "missing" {
+			continue
+		}
+		data = append(data, model.SocietyWithOffsetData{SocietyUUID: societyUUID, Name: "name " + societyUUID})
+	}
+	return data, nil
+}
+
+func (f *fakeRepo) GetSocietyPeerState(_ context.Context, _, peerUUID string) (*model.SocietyPeerState, error) {
+	f.calls["GetSocietyPeerState"]++
+	if peerUUID == "owner" {
+		return &model.SocietyPeerState{Role: 1}, nil
+	}
+	return &model.SocietyPeerState{IsPending: true}, nil
+}
+
+func (f *fakeRepo) UpdateSociety(_ context.Context, _ *society.UpdateSocietyIn) error {
+	return nil
+}
+
+func (f *fakeRepo) RemoveSocietyHasTagsEntry(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeRepo) AddSocietyMembers(_ context.Context, _ string, _ string) (bool, error) {
+	f.count++
+	return false, nil
+}
+
+func (f *fakeRepo) AutoApproveMembersRequest(_ context.Context, _ *model.MembersRequest, _ *model.AutoApprovalRule) (bool, error) {
+	f.count++
+	return true, nil
+}
+
+func testConfig() config.Cache {
+	return config.Cache{
+		Enabled:           true,
+		Size:              100,
+		SocietyInfoTTL:    time.Minute,
+		TagsTTL:           time.Minute,
+		InfoSocietyTTL:    time.Minute,
+		CountSubscribeTTL: time.Minute,
+	}
+}
+
+func metric(name string) int64 {
+	if v, ok := Metrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestRepository_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	inner := newFakeRepo()
+	repo := New(inner, NewLRU(100), testConfig())
+
+	hitsBefore := metric("society_info_hits")
+	missesBefore := metric("society_info_misses")
+
+	first, err := repo.GetSocietyInfo(ctx, "soc-1")
+	require.NoError(t, err)
+	second, err := repo.GetSocietyInfo(ctx, "soc-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, inner.calls["GetSocietyInfo"])
+	assert.Equal(t, hitsBefore+1, metric("society_info_hits"))
+	assert.Equal(t, missesBefore+1, metric("society_info_misses"))
+}
+
+func TestRepository_GetInfoSocietyFetchesOnlyMisses(t *testing.T) {
+	ctx := context.Background()
+	inner := newFakeRepo()
+	repo := New(inner, NewLRU(100), testConfig())
+
+	_, err := repo.GetInfoSociety(ctx, []string{"soc-1"})
+	require.NoError(t, err)
+
+	data, err := repo.GetInfoSociety(ctx, []string{"soc-2", "soc-1", "missing"})
+	require.NoError(t, err)
+
+	require.Len(t, data, 2)
+	assert.Equal(t, "soc-2", data[0].SocietyUUID)
+	assert.Equal(t, "soc-1", data[1].SocietyUUID)
+	assert.Equal(t, 3, inner.calls["GetInfoSociety"])
+}
+
+func TestRepository_GetSocietyInfoForPeer(t *testing.T) {
+	ctx := context.Background()
+	inner := newFakeRepo()
+	repo := New(inner, NewLRU(100), testConfig())
+
+	owner, err := repo.GetSocietyInfoForPeer(ctx, "soc-1", "owner")
+	require.NoError(t, err)
+	applicant, err := repo.GetSocietyInfoForPeer(ctx, "soc-1", "applicant")
+	require.NoError(t, err)
+
+	assert.Equal(t, "society soc-1", owner.Name)
+	assert.Equal(t, []int64{1, 2}, applicant.TagsID)
+	assert.Equal(t, int64(3), applicant.CountSubscribe)
+	assert.True(t, owner.IsMember)
+	assert.Equal(t, 1, owner.PeerRole)
+	assert.False(t, applicant.IsMember)
+	assert.True(t, applicant.IsPending)
+
+	assert.Equal(t, 1, inner.calls["GetSocietyInfo"])
+	assert.Equal(t, 1, inner.calls["CountSubscribe"])
+	assert.Equal(t, 2, inner.calls["GetSocietyPeerState"], "peer state is never cached")
+}
+
+func TestRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("UpdateSociety drops society info", func(t *testing.T) {
+		inner := newFakeRepo()
+		repo := New(inner, NewLRU(100), testConfig())
+
+		_, _ = repo.GetSocietyInfo(ctx, "soc-1")
+		_, _ = repo.GetSocietyInfo(ctx, "soc-2")
+		require.NoError(t, repo.UpdateSociety(ctx, &society.UpdateSocietyIn{SocietyUUID: "soc-1"}))
+		_, _ = repo.GetSocietyInfo(ctx, "soc-1")
+		_, _ = repo.GetSocietyInfo(ctx, "soc-2")
+
+		assert.Equal(t, 3, inner.calls["GetSocietyInfo"])
+	})
+
+	t.Run("membership change drops only the count", func(t *testing.T) {
+		inner := newFakeRepo()
+		repo := New(inner, NewLRU(100), testConfig())
+
+		_, _ = repo.GetTags(ctx, "soc-1")
+		count, _ := repo.CountSubscribe(ctx, "soc-1")
+		_, err := repo.AddSocietyMembers(ctx, "user", "soc-1")
+		require.NoError(t, err)
+		newCount, _ := repo.CountSubscribe(ctx, "soc-1")
+		_, _ = repo.GetTags(ctx, "soc-1")
+
+		assert.Equal(t, count+1, newCount)
+		assert.Equal(t, 1, inner.calls["GetTags"])
+	})
+
+	t.Run("auto-approved request drops the count", func(t *testing.T) {
+		inner := newFakeRepo()
+		repo := New(inner, NewLRU(100), testConfig())
+
+		count, _ := repo.CountSubscribe(ctx, "soc-1")
+		approved, err := repo.AutoApproveMembersRequest(ctx, &model.MembersRequest{SocietyUUID: "soc-1"}, &model.AutoApprovalRule{})
+		require.NoError(t, err)
+		require.True(t, approved)
+		newCount, _ := repo.CountSubscribe(ctx, "soc-1")
+
+		assert.Equal(t, count+1, newCount)
+	})
+
+	t.Run("transaction invalidates after commit and bypasses cache inside", func(t *testing.T) {
+		inner := newFakeRepo()
+		repo := New(inner, NewLRU(100), testConfig())
+
+		_, _ = repo.GetTags(ctx, "soc-1")
+		err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+			if err := txRepo.RemoveSocietyHasTagsEntry(ctx, "soc-1"); err != nil {
+				return err
+			}
+			_, err := txRepo.GetTags(ctx, "soc-1")
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, inner.calls["GetTags"])
+
+		_, _ = repo.GetTags(ctx, "soc-1")
+		assert.Equal(t, 3, inner.calls["GetTags"])
+	})
+
+	t.Run("failed transaction keeps the cache", func(t *testing.T) {
+		inner := newFakeRepo()
+		inner.txErr = errors.New("commit failed")
+		repo := New(inner, NewLRU(100), testConfig())
+
+		_, _ = repo.GetTags(ctx, "soc-1")
+		err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+			return txRepo.RemoveSocietyHasTagsEntry(ctx, "soc-1")
+		})
+		require.Error(t, err)
+
+		_, _ = repo.GetTags(ctx, "soc-1")
+		assert.Equal(t, 1, inner.calls["GetTags"])
+	})
+}
+
+// passThrough — методы DbRepo, которые не читают закэшированное и не меняют society,
+// society_has_tags и состав society_members, поэтому декоратор их не переопределяет
+var passThrough = map[string]bool{
+	"GetSocietyPeerState":       true,
+	"IsOwnerAdminModerator":     true,
+	"GetMemberCounters":         true,
+	"GetMembershipGrowth":       true,
+	"GetRequestStats":           true,
+	"GetMembershipTimeline":     true,
+	"RemoveMembersRequestEntry": true,
+	"GetOwner":                  true,
+	"GetFormatSociety":          true,
+	"AddMembersRequests":        true,
+	"SetRequestTTL":             true,
+	"GetPendingRequests":        true,
+	"GetRoleSocietyMembers":     true,
+	"LeaveWaitlist":             true,
+	"GetUserSocieties":          true,
+	"CountUserSocieties":        true,
+	"GetSocietiesByIDs":         true,
+	"SetSocietyParent":          true,
+	"GetSocietyChildren":        true,
+	"GetSocietyAncestors":       true,
+	"GetSocietyRules":           true,
+	"SetSocietyRules":           true,
+	"GetSocietyQuestions":       true,
+	"SetSocietyQuestions":       true,
+	"GetAutoApprovalRules":      true,
+	"SetAutoApprovalRules":      true,
+	"CreateChannel":             true,
+	"GetChannel":                true,
+	"GetSocietyChannels":        true,
+	"UpdateChannel":             true,
+	"RemoveChannel":             true,
+	"AddChannelMember":          true,
+	"RemoveChannelMember":       true,
+	"CreateEvent":               true,
+	"GetEvent":                  true,
+	"GetSocietyEvents":          true,
+	"GetUpcomingEvents":         true,
+	"UpdateEvent":               true,
+	"CancelEvent":               true,
+	"SetEventRSVP":              true,
+	"CreatePoll":                true,
+	"GetPoll":                   true,
+	"GetSocietyPolls":           true,
+	"VotePoll":                  true,
+	"ClosePoll":                 true,
+	"GetNotificationSettings":   true,
+	"SetNotificationSettings":   true,
+	"GetNotificationRecipients": true,
+	"CreateAnnouncement":        true,
+	"RemoveAnnouncement":        true,
+	"GetActiveAnnouncements":    true,
+	"IsFormatExists":            true,
+	"IsPostPermissionExists":    true,
+}
+
+// новый метод DbRepo должен либо сбрасывать кэш в cache.go, либо явно попасть в passThrough
+func TestRepository_CoversDbRepo(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "cache.go", nil, 0)
+	require.NoError(t, err)
+
+	overridden := make(map[string]bool)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		if star, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
+			if ident, ok := star.X.(*ast.Ident); ok && ident.Name == "Repository" {
+				overridden[fn.Name.Name] = true
+			}
+		}
+	}
+
+	dbRepo := reflect.TypeOf((*service.DbRepo)(nil)).Elem()
+	methods := make(map[string]bool, dbRepo.NumMethod())
+	for i := 0; i < dbRepo.NumMethod(); i++ {
+		name := dbRepo.Method(i).Name
+		methods[name] = true
+		if !overridden[name] && !passThrough[name] {
+			t.Errorf("DbRepo.%s не переопределён в cache.go и не указан в passThrough", name)
+		}
+		if overridden[name] && passThrough[name] {
+			t.Errorf("DbRepo.%s переопределён в cache.go, уберите его из passThrough", name)
+		}
+	}
+	for name := range passThrough {
+		if !methods[name] {
+			t.Errorf("passThrough содержит %s, которого нет в DbRepo", name)
+		}
+	}
+}
+
+func TestLRU(t *testing.T) {
+	t.Run("evicts least recently used", func(t *testing.T) {
+		lru := NewLRU(2)
+		lru.Set("a", []byte("1"), time.Minute)
+		lru.Set("b", []byte("2"), time.Minute)
+		_, _ = lru.Get("a")
+		lru.Set("c", []byte("3"), time.Minute)
+
+		_, okA := lru.Get("a")
+		_, okB := lru.Get("b")
+		_, okC := lru.Get("c")
+		assert.True(t, okA)
+		assert.False(t, okB)
+		assert.True(t, okC)
+		assert.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("expires entries", func(t *testing.T) {
+		now := time.Now()
+		lru := NewLRU(2)
+		lru.now = func() time.Time { return now }
+		lru.Set("a", []byte("1"), time.Second)
+
+		now = now.Add(2 * time.Second)
+		_, ok := lru.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, lru.Len())
+	})
+}
+
+func TestRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Cache{
+		SocietyInfoTTL:    time.Minute,
+		TagsTTL:           time.Minute,
+		InfoSocietyTTL:    time.Minute,
+		CountSubscribeTTL: time.Minute,
+	}
+	repotest.Run(t, func(t *testing.T) repotest.Fixture {
+		backing := memory.New()
+		return repotest.Fixture{
+			Repo: New(backing, NewLRU(100), cfg),
+			AddTag: func(t *testing.T, societyUUID string, tagID int64, isActive bool) {
+				require.NoError(t, backing.AddSocietyTag(context.Background(), societyUUID, tagID, isActive))
+			},
+		}
+	})
+}
@@ -0,0 +1,2414 @@
+// Package memory реализует service.DbRepo в памяти процесса.
+// Ограничения повторяют схему из /migrations, поэтому репозиторий можно подставлять
+// вместо Postgres в end-to-end тестах сервиса.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/distribution/uuid"
+
+	society "github.com/s21platform/society-proto/society-proto"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+const (
+	defaultPhotoURL = "https://storage.yandexcloud.net/space21/avatars/default/logo-discord.jpeg"
+	maxNameLength   = 255 // society.name VARCHAR(255)
+
+	formatsCount         = 3 // format_society
+	postPermissionsCount = 4 // post_permission
+
+	maxHierarchyDepth = 32 // как в postgres: дальше рекурсия не поднимается
+
+	roleOwner     = 1
+	roleModerator = 3
+	roleMember    = 4
+
+	paymentFree    = 1
+	paymentExpired = 3
+
+	requestPending  = 1
+	requestApproved = 2
+	requestRejected = 3
+)
+
+var (
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrValueTooLong        = errors.New("value too long")
+	ErrUniqueViolation     = errors.New("unique violation")
+	ErrCheckViolation      = errors.New("check violation")
+)
+
+type societyRow struct {
+	id               string
+	name             string
+	description      sql.NullString
+	ownerUUID        string
+	photoURL         string
+	formatID         int64
+	postPermissionID int64
+	isSearch         bool
+	requestTTLHours  int64
+	maxMembers       int64
+	// parentID пустой у сообществ верхнего уровня
+	parentID                string
+	inheritParentModeration bool
+}
+
+type memberRow struct {
+	id            int64
+	societyID     string
+	userUUID      string
+	role          int
+	paymentStatus int
+	notify        model.NotificationSettings
+}
+
+type requestRow struct {
+	id                   int64
+	societyID            string
+	userUUID             string
+	statusID             int
+	acceptedRulesVersion int64
+	answers              []model.RequestAnswer
+	autoRuleID           int64
+	autoRuleKind         string
+	createdAt            time.Time
+	updatedAt            time.Time
+}
+
+type eventRow struct {
+	id            int64
+	actorUUID     string
+	societyID     string
+	userUUID      string
+	eventType     string
+	role          int
+	paymentStatus int
+	createdAt     time.Time
+}
+
+type rulesRow struct {
+	societyID  string
+	version    int64
+	authorUUID string
+	items      []model.SocietyRule
+}
+
+type questionRow struct {
+	societyID string
+	model.SocietyQuestion
+}
+
+type autoRuleRow struct {
+	societyID string
+	model.AutoApprovalRule
+}
+
+type waitlistRow struct {
+	id        int64
+	societyID string
+	userUUID  string
+}
+
+type channelMemberRow struct {
+	channelID string
+	societyID string
+	userUUID  string
+}
+
+type rsvpRow struct {
+	eventID  string
+	userUUID string
+}
+
+type ballotRow struct {
+	pollID   string
+	userUUID string
+}
+
+type voteRow struct {
+	pollID    string
+	optionID  int64
+	voterUUID string // пустой в анонимных опросах
+}
+
+type tagRow struct {
+	societyID string
+	tagID     int64
+	isActive  bool
+}
+
+type store struct {
+	societies          map[string]societyRow
+	members            []memberRow
+	requests           []requestRow
+	tags               []tagRow
+	events             []eventRow
+	rules              []rulesRow
+	announcements      []model.Announcement
+	questions          []questionRow
+	autoRules          []autoRuleRow
+	waitlist           []waitlistRow
+	channels           []model.Channel
+	channelMembers     []channelMemberRow
+	societyEvents      []model.SocietyEvent
+	rsvps              []rsvpRow
+	polls              []model.Poll
+	pollOptions        []model.PollOption
+	ballots            []ballotRow
+	votes              []voteRow
+	nextMemberID       int64
+	nextRequestID      int64
+	nextEventID        int64
+	nextAnnouncementID int64
+	nextQuestionID     int64
+	nextAutoRuleID     int64
+	nextWaitlistID     int64
+	nextPollOptionID   int64
+	now                func() time.Time
+}
+
+func (s *store) clone() *store {
+	societies := make(map[string]societyRow, len(s.societies))
+	for id, row := range s.societies {
+		societies[id] = row
+	}
+
+	return &store{
+		societies:          societies,
+		members:            append([]memberRow(nil), s.members...),
+		requests:           append([]requestRow(nil), s.requests...),
+		tags:               append([]tagRow(nil), s.tags...),
+		events:             append([]eventRow(nil), s.events...),
+		rules:              append([]rulesRow(nil), s.rules...),
+		announcements:      append([]model.Announcement(nil), s.announcements...),
+		questions:          append([]questionRow(nil), s.questions...),
+		autoRules:          append([]autoRuleRow(nil), s.autoRules...),
+		waitlist:           append([]waitlistRow(nil), s.waitlist...),
+		channels:           append([]model.Channel(nil), s.channels...),
+		channelMembers:     append([]channelMemberRow(nil), s.channelMembers...),
+		societyEvents:      append([]model.SocietyEvent(nil), s.societyEvents...),
+		rsvps:              append([]rsvpRow(nil), s.rsvps...),
+		polls:              append([]model.Poll(nil), s.polls...),
+		pollOptions:        append([]model.PollOption(nil), s.pollOptions...),
+		ballots:            append([]ballotRow(nil), s.ballots...),
+		votes:              append([]voteRow(nil), s.votes...),
+		nextMemberID:       s.nextMemberID,
+		nextRequestID:      s.nextRequestID,
+		nextEventID:        s.nextEventID,
+		nextAnnouncementID: s.nextAnnouncementID,
+		nextQuestionID:     s.nextQuestionID,
+		nextAutoRuleID:     s.nextAutoRuleID,
+		nextWaitlistID:     s.nextWaitlistID,
+		nextPollOptionID:   s.nextPollOptionID,
+		now:                s.now,
+	}
+}
+
+func (s *store) society(societyUUID string) (societyRow, error) {
+	row, ok := s.societies[societyUUID]
+	if !ok {
+		return societyRow{}, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (s *store) checkSociety(societyUUID string) error {
+	if _, ok := s.societies[societyUUID]; !ok {
+		return fmt.Errorf("%w: society %s does not exist", ErrForeignKeyViolation, societyUUID)
+	}
+	return nil
+}
+
+func checkSettings(formatID, postPermissionID int64) error {
+	if formatID < 1 || formatID > formatsCount {
+		return fmt.Errorf("%w: format %d does not exist", ErrForeignKeyViolation, formatID)
+	}
+	if postPermissionID < 1 || postPermissionID > postPermissionsCount {
+		return fmt.Errorf("%w: post permission %d does not exist", ErrForeignKeyViolation, postPermissionID)
+	}
+	return nil
+}
+
+func checkName(name string) error {
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrValueTooLong, maxNameLength)
+	}
+	return nil
+}
+
+// member первая запись участника, как у GetContext без ORDER BY
+func (s *store) member(societyUUID, userUUID string) (memberRow, bool) {
+	for _, m := range s.members {
+		if m.societyID == societyUUID && m.userUUID == userUUID {
+			return m, true
+		}
+	}
+	return memberRow{}, false
+}
+
+// memberCount повторяет триггер society_members_count: истёкшая оплата не учитывается
+func (s *store) memberCount(societyUUID string) int64 {
+	var count int64
+	for _, m := range s.members {
+		if m.societyID == societyUUID && m.paymentStatus != paymentExpired {
+			count++
+		}
+	}
+	return count
+}
+
+// addMember и removeMembers пишут в журнал как триггер society_members_history
+func (s *store) addMember(societyUUID, userUUID string, role, paymentStatus int, actorUUID string) {
+	s.nextMemberID++
+	m := memberRow{
+		id:            s.nextMemberID,
+		societyID:     societyUUID,
+		userUUID:      userUUID,
+		role:          role,
+		paymentStatus: paymentStatus,
+		notify:        model.DefaultNotificationSettings(societyUUID, userUUID),
+	}
+	s.members = append(s.members, m)
+	s.logEvent(m, model.MemberEventJoin, actorUUID)
+}
+
+func (s *store) removeMembers(eventType, actorUUID string, match func(m memberRow) bool) {
+	members := s.members[:0]
+	var removed []memberRow
+	for _, m := range s.members {
+		if match(m) {
+			s.logEvent(m, eventType, actorUUID)
+			removed = append(removed, m)
+			continue
+		}
+		members = append(members, m)
+	}
+	s.members = members
+
+	// как триггер society_channel_members_cleanup: бывший участник покидает каналы сообщества
+	for _, m := range removed {
+		if _, ok := s.member(m.societyID, m.userUUID); ok {
+			continue
+		}
+		channelMembers := s.channelMembers[:0]
+		for _, cm := range s.channelMembers {
+			if cm.societyID != m.societyID || cm.userUUID != m.userUUID {
+				channelMembers = append(channelMembers, cm)
+			}
+		}
+		s.channelMembers = channelMembers
+
+		// как триггер society_event_rsvps_cleanup: записи на не начавшиеся события снимаются
+		now := s.now()
+		rsvps := s.rsvps[:0]
+		for _, rsvp := range s.rsvps {
+			if i, ok := s.societyEvent(rsvp.eventID); ok && rsvp.userUUID == m.userUUID &&
+				s.societyEvents[i].SocietyUUID == m.societyID && s.societyEvents[i].StartsAt.After(now) {
+				continue
+			}
+			rsvps = append(rsvps, rsvp)
+		}
+		s.rsvps = rsvps
+	}
+}
+
+func (s *store) isFull(societyUUID string) bool {
+	maxMembers := s.societies[societyUUID].maxMembers
+	return maxMembers > 0 && s.memberCount(societyUUID) >= maxMembers
+}
+
+// promoteWaitlisted повторяет promoteWaitlistedQuery: свободные места получают первые в очереди
+func (s *store) promoteWaitlisted(societyUUID, actorUUID string) {
+	waitlist := s.waitlist[:0]
+	for _, w := range s.waitlist {
+		if w.societyID == societyUUID && !s.isFull(societyUUID) {
+			if _, ok := s.member(societyUUID, w.userUUID); !ok {
+				s.addMember(societyUUID, w.userUUID, roleMember, paymentFree, actorUUID)
+			}
+			continue
+		}
+		waitlist = append(waitlist, w)
+	}
+	s.waitlist = waitlist
+}
+
+// joinOrWaitlist повторяет postgres.joinOrWaitlist; true — пользователь встал в очередь
+func (s *store) joinOrWaitlist(societyUUID, userUUID, actorUUID string) (bool, error) {
+	if _, ok := s.member(societyUUID, userUUID); ok {
+		return false, model.ErrAlreadyMember
+	}
+
+	s.promoteWaitlisted(societyUUID, actorUUID)
+	if !s.isFull(societyUUID) {
+		s.addMember(societyUUID, userUUID, roleMember, paymentFree, actorUUID)
+		return false, nil
+	}
+
+	if s.waitlistPosition(societyUUID, userUUID) == 0 {
+		s.nextWaitlistID++
+		s.waitlist = append(s.waitlist, waitlistRow{id: s.nextWaitlistID, societyID: societyUUID, userUUID: userUUID})
+	}
+	return true, nil
+}
+
+func (s *store) waitlistPosition(societyUUID, userUUID string) int64 {
+	var position int64
+	for _, w := range s.waitlist {
+		if w.societyID != societyUUID {
+			continue
+		}
+		position++
+		if w.userUUID == userUUID {
+			return position
+		}
+	}
+	return 0
+}
+
+func (s *store) logEvent(m memberRow, eventType, actorUUID string) {
+	s.nextEventID++
+	s.events = append(s.events, eventRow{
+		id:            s.nextEventID,
+		actorUUID:     actorUUID,
+		societyID:     m.societyID,
+		userUUID:      m.userUUID,
+		eventType:     eventType,
+		role:          m.role,
+		paymentStatus: m.paymentStatus,
+		createdAt:     s.now(),
+	})
+}
+
+func actor(ctx context.Context) string {
+	actorUUID, _ := ctx.Value(config.KeyUUID).(string)
+	return actorUUID
+}
+
+var _ service.DbRepo = (*Repository)(nil)
+
+// Repository потокобезопасен. WithTx держит блокировку на всё время транзакции
+// и применяет изменения только при успешном завершении fn.
+type Repository struct {
+	mu   *sync.RWMutex
+	data *store
+	inTx bool
+}
+
+func New() *Repository {
+	return &Repository{
+		mu:   &sync.RWMutex{},
+		data: &store{societies: make(map[string]societyRow), now: time.Now},
+	}
+}
+
+func (r *Repository) read(fn func(s *store) error) error {
+	if !r.inTx {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	return fn(r.data)
+}
+
+// write выполняет fn атомарно: fn должна проверить ограничения до первого изменения
+func (r *Repository) write(fn func(s *store) error) error {
+	if !r.inTx {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	return fn(r.data)
+}
+
+func (r *Repository) WithTx(ctx context.Context, fn func(repo service.DbRepo) error) error {
+	if r.inTx {
+		return fn(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := r.data.clone()
+	if err := fn(&Repository{mu: r.mu, data: snapshot, inTx: true}); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	*r.data = *snapshot
+	return nil
+}
+
+func (r *Repository) CreateSociety(ctx context.Context, socData *model.SocietyData) (string, error) {
+	var societyUUID string
+
+	err := r.write(func(s *store) error {
+		if err := checkName(socData.Name); err != nil {
+			return err
+		}
+		if err := checkSettings(socData.FormatID, socData.PostPermission); err != nil {
+			return err
+		}
+
+		societyUUID = uuid.Generate().String()
+		s.societies[societyUUID] = societyRow{
+			id:               societyUUID,
+			name:             socData.Name,
+			ownerUUID:        socData.OwnerUUID,
+			photoURL:         defaultPhotoURL,
+			formatID:         socData.FormatID,
+			postPermissionID: socData.PostPermission,
+			isSearch:         socData.IsSearch,
+		}
+		s.addMember(societyUUID, socData.OwnerUUID, roleOwner, paymentFree, actor(ctx))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to insert society: %w", err)
+	}
+
+	return societyUUID, nil
+}
+
+func (row societyRow) info() *model.SocietyInfo {
+	return &model.SocietyInfo{
+		Name:           row.name,
+		Description:    row.description,
+		OwnerUUID:      row.ownerUUID,
+		PhotoURL:       row.photoURL,
+		FormatID:       row.formatID,
+		PostPermission: row.postPermissionID,
+		IsSearch:       row.isSearch,
+	}
+}
+
+func (r *Repository) GetSocietyInfo(_ context.Context, societyUUID string) (*model.SocietyInfo, error) {
+	var societyInfo *model.SocietyInfo
+	err := r.read(func(s *store) error {
+		row, err := s.society(societyUUID)
+		if err != nil {
+			return err
+		}
+
+		societyInfo = row.info()
+		societyInfo.TagsID = activeTags(s, societyUUID)
+		if societyInfo.TagsID == nil {
+			societyInfo.TagsID = []int64{}
+		}
+		societyInfo.MaxMembers = row.maxMembers
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get society info: %w", err)
+	}
+
+	return societyInfo, nil
+}
+
+func (r *Repository) GetSocietyInfoForPeer(_ context.Context, societyUUID, peerUUID string) (*model.SocietyInfo, error) {
+	var societyInfo *model.SocietyInfo
+	err := r.read(func(s *store) error {
+		row, err := s.society(societyUUID)
+		if err != nil {
+			return err
+		}
+
+		societyInfo = row.info()
+		societyInfo.CountSubscribe = s.memberCount(societyUUID)
+		societyInfo.TagsID = activeTags(s, societyUUID)
+		if societyInfo.TagsID == nil {
+			societyInfo.TagsID = []int64{}
+		}
+		societyInfo.MaxMembers = row.maxMembers
+		societyInfo.SetPeerState(s.peerState(societyUUID, peerUUID))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyInfoForPeer: %w", err)
+	}
+
+	return societyInfo, nil
+}
+
+func (r *Repository) GetSocietyPeerState(_ context.Context, societyUUID, peerUUID string) (*model.SocietyPeerState, error) {
+	var state model.SocietyPeerState
+	_ = r.read(func(s *store) error {
+		state = s.peerState(societyUUID, peerUUID)
+		return nil
+	})
+
+	return &state, nil
+}
+
+// peerState повторяет peerStateColumns из postgres
+func (s *store) peerState(societyUUID, peerUUID string) model.SocietyPeerState {
+	var state model.SocietyPeerState
+	if peer, ok := s.member(societyUUID, peerUUID); ok {
+		state.Role = peer.role
+	}
+	state.EditorRole = s.editorRole(peerUUID, societyUUID)
+	state.IsPending = s.hasPendingRequest(societyUUID, peerUUID)
+	state.WaitlistPosition = s.waitlistPosition(societyUUID, peerUUID)
+	state.Announcements = s.activeAnnouncements(societyUUID)
+	return state
+}
+
+func (s *store) hasPendingRequest(societyUUID, userUUID string) bool {
+	for _, req := range s.requests {
+		if req.societyID == societyUUID && req.userUUID == userUUID && req.statusID == requestPending {
+			return true
+		}
+	}
+	return false
+}
+
+func activeTags(s *store, societyUUID string) []int64 {
+	var tags []int64
+	for _, tag := range s.tags {
+		if tag.societyID == societyUUID && tag.isActive {
+			tags = append(tags, tag.tagID)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	return tags
+}
+
+func (r *Repository) UpdateSociety(_ context.Context, societyData *society.UpdateSocietyIn) error {
+	err := r.write(func(s *store) error {
+		row, ok := s.societies[societyData.SocietyUUID]
+		if !ok {
+			return nil
+		}
+		if err := checkName(societyData.Name); err != nil {
+			return err
+		}
+		if err := checkSettings(societyData.FormatID, societyData.PostPermission); err != nil {
+			return err
+		}
+
+		row.name = societyData.Name
+		row.description = sql.NullString{String: societyData.Description, Valid: true}
+		row.formatID = societyData.FormatID
+		row.postPermissionID = societyData.PostPermission
+		row.isSearch = societyData.IsSearch
+		s.societies[row.id] = row
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update society: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) IsOwnerAdminModerator(_ context.Context, peerUUID, societyUUID string) (int, error) {
+	var role int
+	_ = r.read(func(s *store) error {
+		role = s.editorRole(peerUUID, societyUUID)
+		return nil
+	})
+
+	return role, nil
+}
+
+func (s *store) editorRole(peerUUID, societyUUID string) int {
+	var role int
+	if m, ok := s.member(societyUUID, peerUUID); ok {
+		role = m.role
+	}
+	if (role == 0 || role == roleMember) && s.hasInheritedModeration(peerUUID, societyUUID) {
+		role = roleModerator
+	}
+	return role
+}
+
+func (r *Repository) GetTags(_ context.Context, societyUUID string) ([]int64, error) {
+	var tags []int64
+	_ = r.read(func(s *store) error {
+		tags = activeTags(s, societyUUID)
+		return nil
+	})
+
+	return tags, nil
+}
+
+func (r *Repository) CountSubscribe(_ context.Context, societyUUID string) (int64, error) {
+	var count int64
+	err := r.read(func(s *store) error {
+		if _, err := s.society(societyUUID); err != nil {
+			return err
+		}
+		count = s.memberCount(societyUUID)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query CountSubscribe: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) GetMemberCounters(_ context.Context, societyUUID string) ([]model.MemberCounter, error) {
+	var counters []model.MemberCounter
+	_ = r.read(func(s *store) error {
+		byKey := make(map[[2]int]int64)
+		for _, m := range s.members {
+			if m.societyID == societyUUID {
+				byKey[[2]int{m.role, m.paymentStatus}]++
+			}
+		}
+		for k, count := range byKey {
+			counters = append(counters, model.MemberCounter{Role: int64(k[0]), PaymentStatus: int64(k[1]), Count: count})
+		}
+		return nil
+	})
+
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].Role != counters[j].Role {
+			return counters[i].Role < counters[j].Role
+		}
+		return counters[i].PaymentStatus < counters[j].PaymentStatus
+	})
+	return counters, nil
+}
+
+func (r *Repository) GetMembershipGrowth(_ context.Context, societyUUID string, since time.Time) ([]model.DailyMembership, error) {
+	var growth []model.DailyMembership
+	_ = r.read(func(s *store) error {
+		first := truncateDay(since)
+		last := truncateDay(s.now())
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			growth = append(growth, model.DailyMembership{Day: day})
+		}
+		for _, e := range s.events {
+			if e.societyID != societyUUID {
+				continue
+			}
+			i := int(truncateDay(e.createdAt).Sub(first).Hours() / 24)
+			if i < 0 || i >= len(growth) {
+				continue
+			}
+			switch e.eventType {
+			case model.MemberEventJoin:
+				growth[i].Joins++
+			case model.MemberEventLeave, model.MemberEventKick:
+				growth[i].Leaves++
+			}
+		}
+		return nil
+	})
+
+	return growth, nil
+}
+
+func (r *Repository) GetMembershipTimeline(_ context.Context, filter *model.MembershipTimelineFilter) ([]model.MembershipEvent, error) {
+	var events []model.MembershipEvent
+	_ = r.read(func(s *store) error {
+		for i := len(s.events) - 1; i >= 0 && uint64(len(events)) < filter.Limit; i-- {
+			e := s.events[i]
+			if filter.UserUUID != "" && e.userUUID != filter.UserUUID {
+				continue
+			}
+			if filter.SocietyUUID != "" && e.societyID != filter.SocietyUUID {
+				continue
+			}
+			if filter.AfterID > 0 && e.id >= filter.AfterID {
+				continue
+			}
+			events = append(events, model.MembershipEvent{
+				ID:            e.id,
+				SocietyUUID:   e.societyID,
+				UserUUID:      e.userUUID,
+				EventType:     e.eventType,
+				Role:          int64(e.role),
+				PaymentStatus: int64(e.paymentStatus),
+				ActorUUID:     e.actorUUID,
+				CreatedAt:     e.createdAt,
+			})
+		}
+		return nil
+	})
+
+	return events, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r *Repository) GetRequestStats(_ context.Context, societyUUID string) (*model.RequestStats, error) {
+	stats := &model.RequestStats{}
+	_ = r.read(func(s *store) error {
+		var latency time.Duration
+		for _, req := range s.requests {
+			if req.societyID != societyUUID {
+				continue
+			}
+			switch req.statusID {
+			case requestPending:
+				stats.Pending++
+			case requestApproved:
+				stats.Approved++
+				latency += req.updatedAt.Sub(req.createdAt)
+			case requestRejected:
+				stats.Rejected++
+			}
+		}
+		if stats.Approved > 0 {
+			stats.AvgApprovalSeconds = latency.Seconds() / float64(stats.Approved)
+		}
+		return nil
+	})
+
+	return stats, nil
+}
+
+func (r *Repository) RemoveSocietyHasTagsEntry(_ context.Context, societyUUID string) error {
+	return r.write(func(s *store) error {
+		for i := range s.tags {
+			if s.tags[i].societyID == societyUUID {
+				s.tags[i].isActive = false
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Repository) RemoveMembersRequestEntry(_ context.Context, societyUUID string) error {
+	return r.write(func(s *store) error {
+		requests := s.requests[:0]
+		for _, req := range s.requests {
+			if req.societyID != societyUUID {
+				requests = append(requests, req)
+			}
+		}
+		s.requests = requests
+		return nil
+	})
+}
+
+func (r *Repository) RemoveSocietyMembersEntry(ctx context.Context, societyUUID string) error {
+	return r.write(func(s *store) error {
+		s.removeMembers(model.MemberEventKick, actor(ctx), func(m memberRow) bool { return m.societyID == societyUUID })
+		return nil
+	})
+}
+
+// RemoveSociety падает, пока на сообщество ссылаются участники или заявки;
+// теги, правила, объявления, анкета, правила автоодобрения, очередь, каналы, события и опросы удаляются каскадно,
+// а дочерние сообщества отвязываются
+func (r *Repository) RemoveSociety(_ context.Context, societyUUID string) error {
+	err := r.write(func(s *store) error {
+		for _, m := range s.members {
+			if m.societyID == societyUUID {
+				return fmt.Errorf("%w: society %s is still referenced from society_members", ErrForeignKeyViolation, societyUUID)
+			}
+		}
+		for _, req := range s.requests {
+			if req.societyID == societyUUID {
+				return fmt.Errorf("%w: society %s is still referenced from members_requests", ErrForeignKeyViolation, societyUUID)
+			}
+		}
+
+		tags := s.tags[:0]
+		for _, tag := range s.tags {
+			if tag.societyID != societyUUID {
+				tags = append(tags, tag)
+			}
+		}
+		s.tags = tags
+
+		rules := s.rules[:0]
+		for _, row := range s.rules {
+			if row.societyID != societyUUID {
+				rules = append(rules, row)
+			}
+		}
+		s.rules = rules
+
+		announcements := s.announcements[:0]
+		for _, a := range s.announcements {
+			if a.SocietyUUID != societyUUID {
+				announcements = append(announcements, a)
+			}
+		}
+		s.announcements = announcements
+
+		questions := s.questions[:0]
+		for _, q := range s.questions {
+			if q.societyID != societyUUID {
+				questions = append(questions, q)
+			}
+		}
+		s.questions = questions
+
+		autoRules := s.autoRules[:0]
+		for _, rule := range s.autoRules {
+			if rule.societyID != societyUUID {
+				autoRules = append(autoRules, rule)
+			}
+		}
+		s.autoRules = autoRules
+
+		waitlist := s.waitlist[:0]
+		for _, w := range s.waitlist {
+			if w.societyID != societyUUID {
+				waitlist = append(waitlist, w)
+			}
+		}
+		s.waitlist = waitlist
+
+		channels := s.channels[:0]
+		for _, channel := range s.channels {
+			if channel.SocietyUUID != societyUUID {
+				channels = append(channels, channel)
+			}
+		}
+		s.channels = channels
+
+		channelMembers := s.channelMembers[:0]
+		for _, cm := range s.channelMembers {
+			if cm.societyID != societyUUID {
+				channelMembers = append(channelMembers, cm)
+			}
+		}
+		s.channelMembers = channelMembers
+
+		rsvps := s.rsvps[:0]
+		for _, rsvp := range s.rsvps {
+			if i, ok := s.societyEvent(rsvp.eventID); !ok || s.societyEvents[i].SocietyUUID != societyUUID {
+				rsvps = append(rsvps, rsvp)
+			}
+		}
+		s.rsvps = rsvps
+
+		societyEvents := s.societyEvents[:0]
+		for _, event := range s.societyEvents {
+			if event.SocietyUUID != societyUUID {
+				societyEvents = append(societyEvents, event)
+			}
+		}
+		s.societyEvents = societyEvents
+
+		removed := make(map[string]struct{})
+		polls := s.polls[:0]
+		for _, poll := range s.polls {
+			if poll.SocietyUUID == societyUUID {
+				removed[poll.ID] = struct{}{}
+				continue
+			}
+			polls = append(polls, poll)
+		}
+		s.polls = polls
+		s.removePollRows(removed)
+
+		for id, row := range s.societies {
+			if row.parentID == societyUUID {
+				row.parentID = ""
+				s.societies[id] = row
+			}
+		}
+		delete(s.societies, societyUUID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query RemoveSociety: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetOwner(_ context.Context, societyId string) (string, error) {
+	var owner string
+	err := r.read(func(s *store) error {
+		row, err := s.society(societyId)
+		owner = row.ownerUUID
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query GetOwner: %w", err)
+	}
+
+	return owner, nil
+}
+
+func (r *Repository) GetFormatSociety(_ context.Context, societyUUID string) (int, error) {
+	var format int
+	err := r.read(func(s *store) error {
+		row, err := s.society(societyUUID)
+		format = int(row.formatID)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query GetFormatSociety: %w", err)
+	}
+
+	return format, nil
+}
+
+func (r *Repository) AddMembersRequests(_ context.Context, request *model.MembersRequest) error {
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(request.SocietyUUID); err != nil {
+			return err
+		}
+		if _, ok := s.member(request.SocietyUUID, request.UserUUID); ok {
+			return model.ErrAlreadyMember
+		}
+
+		s.addRequest(request, requestPending, nil)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert add_members_requests: %w", err)
+	}
+
+	return nil
+}
+
+func (s *store) addRequest(request *model.MembersRequest, statusID int, rule *model.AutoApprovalRule) {
+	s.nextRequestID++
+	row := requestRow{
+		id:                   s.nextRequestID,
+		societyID:            request.SocietyUUID,
+		userUUID:             request.UserUUID,
+		statusID:             statusID,
+		acceptedRulesVersion: request.AcceptedRulesVersion,
+		answers:              cloneAnswers(request.Answers),
+		createdAt:            s.now(),
+		updatedAt:            s.now(),
+	}
+	if rule != nil {
+		row.autoRuleID = rule.ID
+		row.autoRuleKind = rule.Kind
+	}
+	s.requests = append(s.requests, row)
+}
+
+func cloneAnswers(answers []model.RequestAnswer) []model.RequestAnswer {
+	if len(answers) == 0 {
+		return nil
+	}
+
+	cloned := make([]model.RequestAnswer, 0, len(answers))
+	for _, answer := range answers {
+		answer.Values = append([]string(nil), answer.Values...)
+		cloned = append(cloned, answer)
+	}
+	return cloned
+}
+
+func (r *Repository) AddSocietyMembers(ctx context.Context, uuid string, societyUUID string) (bool, error) {
+	var waitlisted bool
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(societyUUID); err != nil {
+			return err
+		}
+
+		var err error
+		waitlisted, err = s.joinOrWaitlist(societyUUID, uuid, actor(ctx))
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to insert add_society_members: %w", err)
+	}
+
+	return waitlisted, nil
+}
+
+// AddSocietyTag привязывает тег к сообществу. Через service.DbRepo теги не создаются,
+// метод нужен тестам.
+func (r *Repository) AddSocietyTag(_ context.Context, societyUUID string, tagID int64, isActive bool) error {
+	return r.write(func(s *store) error {
+		if err := s.checkSociety(societyUUID); err != nil {
+			return err
+		}
+
+		s.tags = append(s.tags, tagRow{societyID: societyUUID, tagID: tagID, isActive: isActive})
+		return nil
+	})
+}
+
+// ApproveMembersRequest одобряет заявку и добавляет пользователя в участники.
+// В API одобрения пока нет, метод нужен сценарным тестам закрытых сообществ.
+func (r *Repository) ApproveMembersRequest(ctx context.Context, uuid string, societyUUID string) error {
+	return r.write(func(s *store) error {
+		for i, req := range s.requests {
+			if req.societyID == societyUUID && req.userUUID == uuid && req.statusID == requestPending {
+				if _, err := s.joinOrWaitlist(societyUUID, uuid, actor(ctx)); err != nil {
+					return err
+				}
+				s.requests[i].statusID = requestApproved
+				s.requests[i].updatedAt = s.now()
+				return nil
+			}
+		}
+		return fmt.Errorf("failed to approve members request: %w", sql.ErrNoRows)
+	})
+}
+
+func (r *Repository) GetRoleSocietyMembers(_ context.Context, uuid string, societyUUID string) (int, error) {
+	var role int
+	err := r.read(func(s *store) error {
+		m, ok := s.member(societyUUID, uuid)
+		if !ok {
+			return sql.ErrNoRows
+		}
+		role = m.role
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query GetRoleSocietyMembers: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *Repository) UnSubscribeToSociety(ctx context.Context, uuid string, societyUUID string) error {
+	return r.write(func(s *store) error {
+		s.removeMembers(model.MemberEventLeave, actor(ctx), func(m memberRow) bool { return m.societyID == societyUUID && m.userUUID == uuid })
+		s.promoteWaitlisted(societyUUID, actor(ctx))
+		return nil
+	})
+}
+
+func (r *Repository) RemoveSocietyMember(ctx context.Context, uuid string, societyUUID string) error {
+	err := r.write(func(s *store) error {
+		if _, ok := s.member(societyUUID, uuid); !ok {
+			return sql.ErrNoRows
+		}
+
+		s.removeMembers(model.MemberEventKick, actor(ctx), func(m memberRow) bool { return m.societyID == societyUUID && m.userUUID == uuid })
+		s.promoteWaitlisted(societyUUID, actor(ctx))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query RemoveSocietyMember: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) LeaveWaitlist(_ context.Context, uuid string, societyUUID string) error {
+	err := r.write(func(s *store) error {
+		for i, w := range s.waitlist {
+			if w.societyID == societyUUID && w.userUUID == uuid {
+				s.waitlist = append(s.waitlist[:i], s.waitlist[i+1:]...)
+				return nil
+			}
+		}
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query LeaveWaitlist: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetMaxMembers(ctx context.Context, societyUUID string, maxMembers int64) error {
+	err := r.write(func(s *store) error {
+		row, err := s.society(societyUUID)
+		if err != nil {
+			return err
+		}
+
+		row.maxMembers = maxMembers
+		s.societies[societyUUID] = row
+		s.promoteWaitlisted(societyUUID, actor(ctx))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query SetMaxMembers: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetUserSocieties(_ context.Context, filter *model.UserSocietiesFilter) ([]model.SocietyWithOffsetData, error) {
+	var data []model.SocietyWithOffsetData
+	_ = r.read(func(s *store) error {
+		var memberships []memberRow
+		for _, m := range s.members {
+			if m.userUUID != filter.UserUUID {
+				continue
+			}
+			if filter.AfterID > 0 && m.id >= filter.AfterID {
+				continue
+			}
+			if _, ok := s.societies[m.societyID]; ok {
+				memberships = append(memberships, m)
+			}
+		}
+		sort.Slice(memberships, func(i, j int) bool { return memberships[i].id > memberships[j].id })
+
+		if filter.AfterID == 0 {
+			memberships = memberships[min(filter.Offset, uint64(len(memberships))):]
+		}
+		memberships = memberships[:min(filter.Limit, uint64(len(memberships)))]
+
+		for _, m := range memberships {
+			row := s.societies[m.societyID]
+			_, isMember := s.member(m.societyID, filter.CallerUUID)
+			data = append(data, model.SocietyWithOffsetData{
+				SocietyUUID: row.id,
+				Name:        row.name,
+				PhotoURL:    row.photoURL,
+				IsMember:    isMember,
+				FormatId:    row.formatID,
+				MemberID:    m.id,
+			})
+		}
+		return nil
+	})
+
+	return data, nil
+}
+
+func (r *Repository) CountUserSocieties(_ context.Context, userUUID string) (int64, error) {
+	var count int64
+	_ = r.read(func(s *store) error {
+		for _, m := range s.members {
+			if _, ok := s.societies[m.societyID]; ok && m.userUUID == userUUID {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, nil
+}
+
+func (r *Repository) GetInfoSociety(_ context.Context, groups []string) ([]model.SocietyWithOffsetData, error) {
+	var data []model.SocietyWithOffsetData
+	_ = r.read(func(s *store) error {
+		seen := make(map[string]struct{}, len(groups))
+		for _, societyUUID := range groups {
+			row, ok := s.societies[societyUUID]
+			if _, dup := seen[societyUUID]; !ok || dup {
+				continue
+			}
+			seen[societyUUID] = struct{}{}
+			data = append(data, model.SocietyWithOffsetData{
+				SocietyUUID: row.id,
+				Name:        row.name,
+				PhotoURL:    row.photoURL,
+				FormatId:    row.formatID,
+			})
+		}
+		return nil
+	})
+
+	return data, nil
+}
+
+func (r *Repository) GetSocietiesByIDs(_ context.Context, societyUUIDs []string, callerUUID string) ([]model.SocietySummary, error) {
+	var data []model.SocietySummary
+	_ = r.read(func(s *store) error {
+		seen := make(map[string]struct{}, len(societyUUIDs))
+		for _, societyUUID := range societyUUIDs {
+			row, ok := s.societies[societyUUID]
+			if _, dup := seen[societyUUID]; !ok || dup {
+				continue
+			}
+			seen[societyUUID] = struct{}{}
+
+			summary := model.SocietySummary{
+				SocietyUUID:    row.id,
+				Name:           row.name,
+				PhotoURL:       row.photoURL,
+				FormatID:       row.formatID,
+				CountSubscribe: s.memberCount(row.id),
+			}
+			if m, ok := s.member(row.id, callerUUID); ok {
+				summary.Role = int64(m.role)
+			}
+			if parent, ok := s.societies[row.parentID]; ok {
+				summary.ParentUUID = parent.id
+				summary.ParentName = parent.name
+			}
+			data = append(data, summary)
+		}
+		return nil
+	})
+
+	return data, nil
+}
+
+// SetSocietyParent возвращает false, если привязка замкнула бы цикл, и model.ErrHierarchyTooDeep,
+// если цепочка предков родителя длиннее maxHierarchyDepth
+func (r *Repository) SetSocietyParent(_ context.Context, societyUUID, parentUUID string, inheritModeration bool) (bool, error) {
+	acyclic := true
+	err := r.write(func(s *store) error {
+		row, err := s.society(societyUUID)
+		if err != nil {
+			return err
+		}
+
+		if parentUUID != "" {
+			if err := s.checkSociety(parentUUID); err != nil {
+				return err
+			}
+			// как в postgres: проверяются родитель и его предки, всего не больше maxHierarchyDepth
+			chain := append([]string{parentUUID}, s.ancestors(parentUUID)...)
+			for _, ancestor := range chain[:min(len(chain), maxHierarchyDepth)] {
+				if ancestor == societyUUID {
+					acyclic = false
+					return nil
+				}
+			}
+			if len(chain) > maxHierarchyDepth {
+				return model.ErrHierarchyTooDeep
+			}
+		}
+
+		row.parentID = parentUUID
+		row.inheritParentModeration = inheritModeration && parentUUID != ""
+		s.societies[societyUUID] = row
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query SetSocietyParent: %w", err)
+	}
+
+	return acyclic, nil
+}
+
+func (r *Repository) GetSocietyChildren(_ context.Context, societyUUID string) ([]model.SocietyNode, error) {
+	var children []model.SocietyNode
+	_ = r.read(func(s *store) error {
+		for _, row := range s.societies {
+			if row.parentID == societyUUID {
+				children = append(children, societyNode(row, 1))
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].Name != children[j].Name {
+			return children[i].Name < children[j].Name
+		}
+		return children[i].SocietyUUID < children[j].SocietyUUID
+	})
+	return children, nil
+}
+
+func (r *Repository) GetSocietyAncestors(_ context.Context, societyUUID string) ([]model.SocietyNode, error) {
+	var ancestors []model.SocietyNode
+	_ = r.read(func(s *store) error {
+		for i, id := range s.ancestors(societyUUID) {
+			ancestors = append(ancestors, societyNode(s.societies[id], int64(i+1)))
+		}
+		return nil
+	})
+
+	return ancestors, nil
+}
+
+func societyNode(row societyRow, depth int64) model.SocietyNode {
+	return model.SocietyNode{
+		SocietyUUID:             row.id,
+		Name:                    row.name,
+		PhotoURL:                row.photoURL,
+		ParentUUID:              row.parentID,
+		InheritParentModeration: row.inheritParentModeration,
+		Depth:                   depth,
+	}
+}
+
+// ancestors возвращает предков от непосредственного родителя до корня
+func (s *store) ancestors(societyUUID string) []string {
+	var ids []string
+	row, ok := s.societies[societyUUID]
+	for ok && row.parentID != "" && len(ids) < maxHierarchyDepth {
+		ids = append(ids, row.parentID)
+		row, ok = s.societies[row.parentID]
+	}
+	return ids
+}
+
+// hasInheritedModeration — владелец или администратор предка, от которого сообщество наследует модерацию
+func (s *store) hasInheritedModeration(peerUUID, societyUUID string) bool {
+	row, ok := s.societies[societyUUID]
+	for depth := 0; ok && row.inheritParentModeration && row.parentID != "" && depth < maxHierarchyDepth; depth++ {
+		if m, isMember := s.member(row.parentID, peerUUID); isMember && m.role < roleModerator {
+			return true
+		}
+		row, ok = s.societies[row.parentID]
+	}
+	return false
+}
+
+func (r *Repository) IsFormatExists(_ context.Context, formatID int64) (bool, error) {
+	return formatID >= 1 && formatID <= formatsCount, nil
+}
+
+func (r *Repository) IsPostPermissionExists(_ context.Context, postPermissionID int64) (bool, error) {
+	return postPermissionID >= 1 && postPermissionID <= postPermissionsCount, nil
+}
+
+func (r *Repository) GetSocietyRules(_ context.Context, societyUUID string) (*model.SocietyRules, error) {
+	rules := &model.SocietyRules{Items: []model.SocietyRule{}}
+	err := r.read(func(s *store) error {
+		for _, row := range s.rules {
+			if row.societyID == societyUUID && row.version > rules.Version {
+				rules.Version = row.version
+				rules.Items = append([]model.SocietyRule{}, row.items...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) SetSocietyRules(_ context.Context, societyUUID, authorUUID string, rules []model.SocietyRule) (int64, error) {
+	var version int64
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(societyUUID); err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if utf8.RuneCountInString(rule.Title) > maxNameLength {
+				return fmt.Errorf("%w: society_rules.title is limited to %d characters", ErrValueTooLong, maxNameLength)
+			}
+		}
+
+		for _, row := range s.rules {
+			if row.societyID == societyUUID && row.version > version {
+				version = row.version
+			}
+		}
+		version++
+		s.rules = append(s.rules, rulesRow{
+			societyID:  societyUUID,
+			version:    version,
+			authorUUID: authorUUID,
+			items:      append([]model.SocietyRule{}, rules...),
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert society_rules: %w", err)
+	}
+
+	return version, nil
+}
+
+func (r *Repository) CreateAnnouncement(_ context.Context, announcement *model.Announcement) (int64, error) {
+	var id int64
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(announcement.SocietyUUID); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(announcement.Title) > maxNameLength {
+			return fmt.Errorf("%w: society_announcements.title is limited to %d characters", ErrValueTooLong, maxNameLength)
+		}
+
+		s.nextAnnouncementID++
+		id = s.nextAnnouncementID
+		row := *announcement
+		row.ID = id
+		row.CreatedAt = s.now()
+		s.announcements = append(s.announcements, row)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert society_announcements: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) RemoveAnnouncement(_ context.Context, societyUUID string, announcementID int64) error {
+	err := r.write(func(s *store) error {
+		for i, a := range s.announcements {
+			if a.ID == announcementID && a.SocietyUUID == societyUUID {
+				s.announcements = append(s.announcements[:i], s.announcements[i+1:]...)
+				return nil
+			}
+		}
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query RemoveAnnouncement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetActiveAnnouncements(_ context.Context, societyUUID string) ([]model.Announcement, error) {
+	var announcements []model.Announcement
+	err := r.read(func(s *store) error {
+		announcements = s.activeAnnouncements(societyUUID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetActiveAnnouncements: %w", err)
+	}
+
+	return announcements, nil
+}
+
+func (s *store) activeAnnouncements(societyUUID string) []model.Announcement {
+	var announcements []model.Announcement
+	now := s.now()
+	for _, a := range s.announcements {
+		if a.SocietyUUID == societyUUID && (!a.ExpiresAt.Valid || a.ExpiresAt.Time.After(now)) {
+			announcements = append(announcements, a)
+		}
+	}
+
+	// новые первыми, как в Postgres
+	sort.Slice(announcements, func(i, j int) bool {
+		return announcements[i].ID > announcements[j].ID
+	})
+	return announcements
+}
+
+func (r *Repository) GetSocietyQuestions(_ context.Context, societyUUID string) ([]model.SocietyQuestion, error) {
+	questions := []model.SocietyQuestion{}
+	err := r.read(func(s *store) error {
+		for _, q := range s.questions {
+			if q.societyID == societyUUID {
+				question := q.SocietyQuestion
+				question.Options = append([]string{}, question.Options...)
+				questions = append(questions, question)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyQuestions: %w", err)
+	}
+
+	return questions, nil
+}
+
+func (r *Repository) SetSocietyQuestions(_ context.Context, societyUUID string, questions []model.SocietyQuestion) error {
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(societyUUID); err != nil {
+			return err
+		}
+
+		kept := s.questions[:0]
+		for _, q := range s.questions {
+			if q.societyID != societyUUID {
+				kept = append(kept, q)
+			}
+		}
+		s.questions = kept
+
+		for _, question := range questions {
+			if utf8.RuneCountInString(question.Prompt) > maxNameLength {
+				return fmt.Errorf("%w: society_questions.prompt is limited to %d characters", ErrValueTooLong, maxNameLength)
+			}
+
+			s.nextQuestionID++
+			question.ID = s.nextQuestionID
+			question.Options = append([]string{}, question.Options...)
+			s.questions = append(s.questions, questionRow{societyID: societyUUID, SocietyQuestion: question})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert society_questions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetPendingRequests(_ context.Context, filter *model.PendingRequestsFilter) ([]model.MembersRequest, error) {
+	var requests []model.MembersRequest
+	err := r.read(func(s *store) error {
+		for _, req := range s.requests {
+			if uint64(len(requests)) == filter.Limit {
+				break
+			}
+			if req.societyID != filter.SocietyUUID || req.statusID != requestPending || req.id <= filter.AfterID {
+				continue
+			}
+
+			requests = append(requests, model.MembersRequest{
+				ID:                   req.id,
+				UserUUID:             req.userUUID,
+				SocietyUUID:          req.societyID,
+				AcceptedRulesVersion: req.acceptedRulesVersion,
+				Answers:              cloneAnswers(req.answers),
+				CreatedAt:            req.createdAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetPendingRequests: %w", err)
+	}
+
+	return requests, nil
+}
+
+func (r *Repository) GetAutoApprovalRules(_ context.Context, societyUUID string) ([]model.AutoApprovalRule, error) {
+	rules := []model.AutoApprovalRule{}
+	err := r.read(func(s *store) error {
+		for _, rule := range s.autoRules {
+			if rule.societyID == societyUUID {
+				rules = append(rules, rule.AutoApprovalRule)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetAutoApprovalRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) SetAutoApprovalRules(_ context.Context, societyUUID string, rules []model.AutoApprovalRule) error {
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(societyUUID); err != nil {
+			return err
+		}
+
+		kept := s.autoRules[:0]
+		for _, rule := range s.autoRules {
+			if rule.societyID != societyUUID {
+				kept = append(kept, rule)
+			}
+		}
+		s.autoRules = kept
+
+		for _, rule := range rules {
+			s.nextAutoRuleID++
+			rule.ID = s.nextAutoRuleID
+			s.autoRules = append(s.autoRules, autoRuleRow{societyID: societyUUID, AutoApprovalRule: rule})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert society_auto_approval_rules: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) AutoApproveMembersRequest(ctx context.Context, request *model.MembersRequest, rule *model.AutoApprovalRule) (bool, error) {
+	approved := false
+	err := r.write(func(s *store) error {
+		var current *model.AutoApprovalRule
+		for i := range s.autoRules {
+			if s.autoRules[i].ID == rule.ID && s.autoRules[i].societyID == request.SocietyUUID {
+				current = &s.autoRules[i].AutoApprovalRule
+				break
+			}
+		}
+		if current == nil {
+			return nil
+		}
+
+		if rule.Kind == model.AutoApproveDailyLimit {
+			today := truncateDay(s.now())
+			var approvedToday int64
+			for _, req := range s.requests {
+				if req.societyID == request.SocietyUUID && req.autoRuleKind == model.AutoApproveDailyLimit && !req.updatedAt.Before(today) {
+					approvedToday++
+				}
+			}
+			if approvedToday >= current.DailyLimit {
+				return nil
+			}
+		}
+
+		if _, err := s.joinOrWaitlist(request.SocietyUUID, request.UserUUID, actor(ctx)); err != nil {
+			return err
+		}
+		s.addRequest(request, requestApproved, rule)
+		approved = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to auto approve members request: %w", err)
+	}
+
+	return approved, nil
+}
+
+func (r *Repository) SetRequestTTL(_ context.Context, societyUUID string, ttlHours int64) error {
+	err := r.write(func(s *store) error {
+		row, err := s.society(societyUUID)
+		if err != nil {
+			return err
+		}
+
+		row.requestTTLHours = ttlHours
+		s.societies[societyUUID] = row
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query SetRequestTTL: %w", err)
+	}
+
+	return nil
+}
+
+func (s *store) channel(channelUUID string) (int, bool) {
+	for i, channel := range s.channels {
+		if channel.ID == channelUUID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// channelView дополняет канал полями, которые в Postgres считает selectChannels
+func (s *store) channelView(channel model.Channel, peerUUID string) model.Channel {
+	for _, cm := range s.channelMembers {
+		if cm.channelID != channel.ID {
+			continue
+		}
+		channel.MemberCount++
+		if cm.userUUID == peerUUID {
+			channel.IsMember = true
+		}
+	}
+	return channel
+}
+
+func (s *store) checkChannelName(societyUUID, name, exceptID string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	for _, channel := range s.channels {
+		if channel.SocietyUUID == societyUUID && channel.Name == name && channel.ID != exceptID {
+			return fmt.Errorf("%w: %w", ErrUniqueViolation, model.ErrChannelNameTaken)
+		}
+	}
+	return nil
+}
+
+// addChannelMember повторяет postgres.insertChannelMember
+func (s *store) addChannelMember(channelUUID, userUUID string) error {
+	i, ok := s.channel(channelUUID)
+	if !ok {
+		return sql.ErrNoRows
+	}
+	societyUUID := s.channels[i].SocietyUUID
+	if _, ok := s.member(societyUUID, userUUID); !ok {
+		return sql.ErrNoRows
+	}
+
+	for _, cm := range s.channelMembers {
+		if cm.channelID == channelUUID && cm.userUUID == userUUID {
+			return nil
+		}
+	}
+	s.channelMembers = append(s.channelMembers, channelMemberRow{channelID: channelUUID, societyID: societyUUID, userUUID: userUUID})
+	return nil
+}
+
+func (s *store) removeChannel(channelUUID string) {
+	channels := s.channels[:0]
+	for _, channel := range s.channels {
+		if channel.ID != channelUUID {
+			channels = append(channels, channel)
+		}
+	}
+	s.channels = channels
+
+	channelMembers := s.channelMembers[:0]
+	for _, cm := range s.channelMembers {
+		if cm.channelID != channelUUID {
+			channelMembers = append(channelMembers, cm)
+		}
+	}
+	s.channelMembers = channelMembers
+}
+
+func (r *Repository) CreateChannel(_ context.Context, channel *model.Channel, creatorUUID string, maxChannels int) (string, error) {
+	var channelUUID string
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(channel.SocietyUUID); err != nil {
+			return err
+		}
+		count := 0
+		for _, row := range s.channels {
+			if row.SocietyUUID == channel.SocietyUUID {
+				count++
+			}
+		}
+		if count >= maxChannels {
+			return model.ErrTooManyChannels
+		}
+		if err := checkSettings(channel.FormatID, channel.PostPermission); err != nil {
+			return err
+		}
+		if err := s.checkChannelName(channel.SocietyUUID, channel.Name, ""); err != nil {
+			return err
+		}
+		if _, ok := s.member(channel.SocietyUUID, creatorUUID); !ok {
+			return sql.ErrNoRows
+		}
+
+		row := *channel
+		row.ID = uuid.Generate().String()
+		row.MemberCount = 0
+		row.IsMember = false
+		row.CreatedAt = s.now()
+		s.channels = append(s.channels, row)
+		channelUUID = row.ID
+		return s.addChannelMember(row.ID, creatorUUID)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to insert society_channels: %w", err)
+	}
+
+	return channelUUID, nil
+}
+
+func (r *Repository) GetChannel(_ context.Context, channelUUID, peerUUID string) (*model.Channel, error) {
+	var channel model.Channel
+	err := r.read(func(s *store) error {
+		i, ok := s.channel(channelUUID)
+		if !ok {
+			return sql.ErrNoRows
+		}
+		channel = s.channelView(s.channels[i], peerUUID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetChannel: %w", err)
+	}
+
+	return &channel, nil
+}
+
+func (r *Repository) GetSocietyChannels(_ context.Context, societyUUID, peerUUID string) ([]model.Channel, error) {
+	var channels []model.Channel
+	_ = r.read(func(s *store) error {
+		for _, channel := range s.channels {
+			if channel.SocietyUUID == societyUUID {
+				channels = append(channels, s.channelView(channel, peerUUID))
+			}
+		}
+		return nil
+	})
+
+	sort.SliceStable(channels, func(i, j int) bool {
+		if !channels[i].CreatedAt.Equal(channels[j].CreatedAt) {
+			return channels[i].CreatedAt.Before(channels[j].CreatedAt)
+		}
+		return channels[i].Name < channels[j].Name
+	})
+	return channels, nil
+}
+
+func (r *Repository) UpdateChannel(_ context.Context, channel *model.Channel) error {
+	err := r.write(func(s *store) error {
+		i, ok := s.channel(channel.ID)
+		if !ok {
+			return sql.ErrNoRows
+		}
+		if err := checkSettings(channel.FormatID, channel.PostPermission); err != nil {
+			return err
+		}
+		if err := s.checkChannelName(s.channels[i].SocietyUUID, channel.Name, channel.ID); err != nil {
+			return err
+		}
+
+		s.channels[i].Name = channel.Name
+		s.channels[i].Description = channel.Description
+		s.channels[i].FormatID = channel.FormatID
+		s.channels[i].PostPermission = channel.PostPermission
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query UpdateChannel: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RemoveChannel(_ context.Context, channelUUID string) error {
+	err := r.write(func(s *store) error {
+		if _, ok := s.channel(channelUUID); !ok {
+			return sql.ErrNoRows
+		}
+		s.removeChannel(channelUUID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query RemoveChannel: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) AddChannelMember(_ context.Context, channelUUID, userUUID string) error {
+	err := r.write(func(s *store) error {
+		return s.addChannelMember(channelUUID, userUUID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lock society member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RemoveChannelMember(_ context.Context, channelUUID, userUUID string) error {
+	err := r.write(func(s *store) error {
+		for i, cm := range s.channelMembers {
+			if cm.channelID == channelUUID && cm.userUUID == userUUID {
+				s.channelMembers = append(s.channelMembers[:i], s.channelMembers[i+1:]...)
+				return nil
+			}
+		}
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query RemoveChannelMember: %w", err)
+	}
+
+	return nil
+}
+
+func (s *store) societyEvent(eventUUID string) (int, bool) {
+	for i, event := range s.societyEvents {
+		if event.ID == eventUUID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// eventView дополняет событие полями, которые в Postgres считает selectEvents
+func (s *store) eventView(event model.SocietyEvent, peerUUID string) model.SocietyEvent {
+	event.SocietyName = s.societies[event.SocietyUUID].name
+	for _, rsvp := range s.rsvps {
+		if rsvp.eventID != event.ID {
+			continue
+		}
+		event.GoingCount++
+		if rsvp.userUUID == peerUUID {
+			event.IsGoing = true
+		}
+	}
+	return event
+}
+
+// checkEvent повторяет ограничения таблицы society_events
+func checkEvent(event *model.SocietyEvent) error {
+	if utf8.RuneCountInString(event.Title) > maxNameLength {
+		return fmt.Errorf("%w: society_events.title is limited to %d characters", ErrValueTooLong, maxNameLength)
+	}
+	if !event.EndsAt.After(event.StartsAt) {
+		return fmt.Errorf("%w: chk_society_events_period", ErrCheckViolation)
+	}
+	if event.Location == "" && event.Link == "" {
+		return fmt.Errorf("%w: chk_society_events_place", ErrCheckViolation)
+	}
+	return nil
+}
+
+// eventsInPeriod повторяет postgres.eventsInPeriod
+func eventsInPeriod(events []model.SocietyEvent, filter *model.SocietyEventsFilter) []model.SocietyEvent {
+	inPeriod := events[:0]
+	for _, event := range events {
+		if event.EndsAt.After(filter.From) && (filter.To.IsZero() || event.StartsAt.Before(filter.To)) {
+			inPeriod = append(inPeriod, event)
+		}
+	}
+
+	sort.Slice(inPeriod, func(i, j int) bool {
+		if !inPeriod[i].StartsAt.Equal(inPeriod[j].StartsAt) {
+			return inPeriod[i].StartsAt.Before(inPeriod[j].StartsAt)
+		}
+		return inPeriod[i].ID < inPeriod[j].ID
+	})
+	if uint64(len(inPeriod)) > filter.Limit {
+		inPeriod = inPeriod[:filter.Limit]
+	}
+	return inPeriod
+}
+
+func (r *Repository) CreateEvent(_ context.Context, event *model.SocietyEvent) (string, error) {
+	var eventUUID string
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(event.SocietyUUID); err != nil {
+			return err
+		}
+		if err := checkEvent(event); err != nil {
+			return err
+		}
+
+		row := *event
+		row.ID = uuid.Generate().String()
+		row.SocietyName = ""
+		row.CancelledAt = sql.NullTime{}
+		row.GoingCount = 0
+		row.IsGoing = false
+		row.CreatedAt = s.now()
+		row.UpdatedAt = row.CreatedAt
+		s.societyEvents = append(s.societyEvents, row)
+		eventUUID = row.ID
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to insert society_events: %w", err)
+	}
+
+	return eventUUID, nil
+}
+
+func (r *Repository) GetEvent(_ context.Context, eventUUID, peerUUID string) (*model.SocietyEvent, error) {
+	var event model.SocietyEvent
+	err := r.read(func(s *store) error {
+		i, ok := s.societyEvent(eventUUID)
+		if !ok {
+			return sql.ErrNoRows
+		}
+		event = s.eventView(s.societyEvents[i], peerUUID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetEvent: %w", err)
+	}
+
+	return &event, nil
+}
+
+func (r *Repository) GetSocietyEvents(_ context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+	var events []model.SocietyEvent
+	_ = r.read(func(s *store) error {
+		for _, event := range s.societyEvents {
+			if event.SocietyUUID == filter.SocietyUUID {
+				events = append(events, s.eventView(event, filter.PeerUUID))
+			}
+		}
+		return nil
+	})
+
+	return eventsInPeriod(events, filter), nil
+}
+
+func (r *Repository) GetUpcomingEvents(_ context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+	var events []model.SocietyEvent
+	_ = r.read(func(s *store) error {
+		for _, event := range s.societyEvents {
+			if _, ok := s.member(event.SocietyUUID, filter.PeerUUID); ok && !event.CancelledAt.Valid {
+				events = append(events, s.eventView(event, filter.PeerUUID))
+			}
+		}
+		return nil
+	})
+
+	return eventsInPeriod(events, filter), nil
+}
+
+func (r *Repository) UpdateEvent(_ context.Context, event *model.SocietyEvent) error {
+	err := r.write(func(s *store) error {
+		i, ok := s.societyEvent(event.ID)
+		if !ok || s.societyEvents[i].CancelledAt.Valid {
+			return sql.ErrNoRows
+		}
+		if err := checkEvent(event); err != nil {
+			return err
+		}
+
+		row := &s.societyEvents[i]
+		row.Title = event.Title
+		row.Description = event.Description
+		row.StartsAt = event.StartsAt
+		row.EndsAt = event.EndsAt
+		row.Location = event.Location
+		row.Link = event.Link
+		row.Capacity = event.Capacity
+		row.UpdatedAt = s.now()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query UpdateEvent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) CancelEvent(_ context.Context, eventUUID string) error {
+	err := r.write(func(s *store) error {
+		i, ok := s.societyEvent(eventUUID)
+		if !ok || s.societyEvents[i].CancelledAt.Valid {
+			return sql.ErrNoRows
+		}
+
+		now := s.now()
+		s.societyEvents[i].CancelledAt = sql.NullTime{Time: now, Valid: true}
+		s.societyEvents[i].UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query CancelEvent: %w", err)
+	}
+
+	return nil
+}
+
+// SetEventRSVP повторяет postgres.SetEventRSVP
+func (r *Repository) SetEventRSVP(_ context.Context, eventUUID, userUUID string, going bool) (bool, error) {
+	booked := false
+	err := r.write(func(s *store) error {
+		if !going {
+			for i, rsvp := range s.rsvps {
+				if rsvp.eventID == eventUUID && rsvp.userUUID == userUUID {
+					s.rsvps = append(s.rsvps[:i], s.rsvps[i+1:]...)
+					break
+				}
+			}
+			booked = true
+			return nil
+		}
+
+		i, ok := s.societyEvent(eventUUID)
+		if !ok || s.societyEvents[i].CancelledAt.Valid {
+			return sql.ErrNoRows
+		}
+		event := s.eventView(s.societyEvents[i], userUUID)
+		if _, ok := s.member(event.SocietyUUID, userUUID); !ok {
+			return sql.ErrNoRows
+		}
+		if event.IsGoing {
+			booked = true
+			return nil
+		}
+		if event.Capacity > 0 && event.GoingCount >= event.Capacity {
+			return nil
+		}
+
+		s.rsvps = append(s.rsvps, rsvpRow{eventID: eventUUID, userUUID: userUUID})
+		booked = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query SetEventRSVP: %w", err)
+	}
+
+	return booked, nil
+}
+
+// removePollRows удаляет варианты, бюллетени и голоса удалённых опросов
+func (s *store) removePollRows(removed map[string]struct{}) {
+	options := s.pollOptions[:0]
+	for _, option := range s.pollOptions {
+		if _, ok := removed[option.PollUUID]; !ok {
+			options = append(options, option)
+		}
+	}
+	s.pollOptions = options
+
+	ballots := s.ballots[:0]
+	for _, ballot := range s.ballots {
+		if _, ok := removed[ballot.pollID]; !ok {
+			ballots = append(ballots, ballot)
+		}
+	}
+	s.ballots = ballots
+
+	votes := s.votes[:0]
+	for _, vote := range s.votes {
+		if _, ok := removed[vote.pollID]; !ok {
+			votes = append(votes, vote)
+		}
+	}
+	s.votes = votes
+}
+
+func (s *store) poll(pollUUID string) (int, bool) {
+	for i, poll := range s.polls {
+		if poll.ID == pollUUID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// pollView дополняет опрос полями, которые в Postgres считают selectPolls и loadPollOptions
+func (s *store) pollView(poll model.Poll, peerUUID string) model.Poll {
+	for _, ballot := range s.ballots {
+		if ballot.pollID != poll.ID {
+			continue
+		}
+		poll.VoterCount++
+		if ballot.userUUID == peerUUID {
+			poll.HasVoted = true
+		}
+	}
+
+	poll.Options = nil
+	for _, option := range s.pollOptions {
+		if option.PollUUID != poll.ID {
+			continue
+		}
+		option.VoterUUIDs = nil
+		for _, vote := range s.votes {
+			if vote.pollID != poll.ID || vote.optionID != option.ID {
+				continue
+			}
+			option.VoteCount++
+			if vote.voterUUID != "" {
+				option.VoterUUIDs = append(option.VoterUUIDs, vote.voterUUID)
+			}
+		}
+		sort.Strings(option.VoterUUIDs)
+		poll.Options = append(poll.Options, option)
+	}
+	return poll
+}
+
+func (r *Repository) CreatePoll(_ context.Context, poll *model.Poll) (string, error) {
+	var pollUUID string
+	err := r.write(func(s *store) error {
+		if err := s.checkSociety(poll.SocietyUUID); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(poll.Question) > maxNameLength {
+			return fmt.Errorf("%w: society_polls.question is limited to %d characters", ErrValueTooLong, maxNameLength)
+		}
+		if poll.ResultsVisibility != model.PollResultsLive && poll.ResultsVisibility != model.PollResultsAfterClose {
+			return fmt.Errorf("%w: chk_society_polls_results_visibility", ErrCheckViolation)
+		}
+
+		row := *poll
+		row.ID = uuid.Generate().String()
+		row.CreatedAt = s.now()
+		row.VoterCount = 0
+		row.HasVoted = false
+		row.Options = nil
+		s.polls = append(s.polls, row)
+
+		for i, option := range poll.Options {
+			s.nextPollOptionID++
+			s.pollOptions = append(s.pollOptions, model.PollOption{
+				ID:       s.nextPollOptionID,
+				PollUUID: row.ID,
+				Position: int64(i + 1),
+				Text:     option.Text,
+			})
+		}
+		pollUUID = row.ID
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to insert society_polls: %w", err)
+	}
+
+	return pollUUID, nil
+}
+
+func (r *Repository) GetPoll(_ context.Context, pollUUID, peerUUID string) (*model.Poll, error) {
+	var poll model.Poll
+	err := r.read(func(s *store) error {
+		i, ok := s.poll(pollUUID)
+		if !ok {
+			return sql.ErrNoRows
+		}
+		poll = s.pollView(s.polls[i], peerUUID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetPoll: %w", err)
+	}
+
+	return &poll, nil
+}
+
+func (r *Repository) GetSocietyPolls(_ context.Context, filter *model.SocietyPollsFilter) ([]model.Poll, error) {
+	var polls []model.Poll
+	_ = r.read(func(s *store) error {
+		for _, poll := range s.polls {
+			if poll.SocietyUUID == filter.SocietyUUID {
+				polls = append(polls, s.pollView(poll, filter.PeerUUID))
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(polls, func(i, j int) bool {
+		if !polls[i].CreatedAt.Equal(polls[j].CreatedAt) {
+			return polls[i].CreatedAt.After(polls[j].CreatedAt)
+		}
+		return polls[i].ID < polls[j].ID
+	})
+	if uint64(len(polls)) > filter.Limit {
+		polls = polls[:filter.Limit]
+	}
+	return polls, nil
+}
+
+// VotePoll повторяет postgres.VotePoll
+func (r *Repository) VotePoll(_ context.Context, pollUUID, userUUID string, optionIDs []int64) (bool, error) {
+	voted := false
+	err := r.write(func(s *store) error {
+		i, ok := s.poll(pollUUID)
+		if !ok || s.polls[i].IsClosed(s.now()) {
+			return sql.ErrNoRows
+		}
+		poll := s.polls[i]
+		if _, ok := s.member(poll.SocietyUUID, userUUID); !ok {
+			return sql.ErrNoRows
+		}
+		for _, ballot := range s.ballots {
+			if ballot.pollID == pollUUID && ballot.userUUID == userUUID {
+				return nil
+			}
+		}
+
+		voter := userUUID
+		if poll.IsAnonymous {
+			voter = ""
+		}
+		for _, optionID := range optionIDs {
+			found := false
+			for _, option := range s.pollOptions {
+				found = found || (option.PollUUID == pollUUID && option.ID == optionID)
+			}
+			if !found {
+				return fmt.Errorf("%w: option %d does not belong to poll %s", ErrForeignKeyViolation, optionID, pollUUID)
+			}
+		}
+		for _, optionID := range optionIDs {
+			s.votes = append(s.votes, voteRow{pollID: pollUUID, optionID: optionID, voterUUID: voter})
+		}
+		s.ballots = append(s.ballots, ballotRow{pollID: pollUUID, userUUID: userUUID})
+		voted = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query VotePoll: %w", err)
+	}
+
+	return voted, nil
+}
+
+func (r *Repository) ClosePoll(_ context.Context, pollUUID string) error {
+	err := r.write(func(s *store) error {
+		now := s.now()
+		i, ok := s.poll(pollUUID)
+		if !ok || s.polls[i].IsClosed(now) {
+			return sql.ErrNoRows
+		}
+
+		s.polls[i].ClosesAt = sql.NullTime{Time: now, Valid: true}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query ClosePoll: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetNotificationSettings(_ context.Context, societyUUID, userUUID string) (*model.NotificationSettings, error) {
+	var settings model.NotificationSettings
+	err := r.read(func(s *store) error {
+		m, ok := s.member(societyUUID, userUUID)
+		if !ok {
+			return sql.ErrNoRows
+		}
+
+		settings = m.notify
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetNotificationSettings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *Repository) SetNotificationSettings(_ context.Context, settings *model.NotificationSettings) error {
+	err := r.write(func(s *store) error {
+		if settings.Delivery != model.NotifyDeliveryInstant && settings.Delivery != model.NotifyDeliveryDigest {
+			return fmt.Errorf("%w: chk_society_members_notify_delivery", ErrCheckViolation)
+		}
+		if settings.MutedUntil.Valid && !settings.Muted {
+			return fmt.Errorf("%w: chk_society_members_notify_muted_until", ErrCheckViolation)
+		}
+
+		for i, m := range s.members {
+			if m.societyID == settings.SocietyUUID && m.userUUID == settings.UserUUID {
+				s.members[i].notify = *settings
+				return nil
+			}
+		}
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query SetNotificationSettings: %w", err)
+	}
+
+	return nil
+}
+
+// GetNotificationRecipients повторяет отбор postgres: подписка, отсутствие mute и роль
+func (r *Repository) GetNotificationRecipients(_ context.Context, filter *model.NotificationRecipientsFilter) ([]model.NotificationRecipient, error) {
+	var recipients []model.NotificationRecipient
+	err := r.read(func(s *store) error {
+		if filter.Activity != model.NotifyPosts && filter.Activity != model.NotifyAnnouncements &&
+			filter.Activity != model.NotifyEvents && filter.Activity != model.NotifyJoinRequests {
+			return fmt.Errorf("unknown notification activity %q", filter.Activity)
+		}
+
+		now := s.now()
+		var members []memberRow
+		for _, m := range s.members {
+			if m.societyID != filter.SocietyUUID || m.userUUID == filter.ExcludeUUID {
+				continue
+			}
+			if filter.AfterID > 0 && m.id >= filter.AfterID {
+				continue
+			}
+			if !m.notify.Wants(filter.Activity) || m.notify.IsMuted(now) {
+				continue
+			}
+			if filter.Activity == model.NotifyJoinRequests && m.role > roleModerator {
+				continue
+			}
+			if len(filter.Roles) > 0 && !slices.Contains(filter.Roles, int64(m.role)) {
+				continue
+			}
+			members = append(members, m)
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].id > members[j].id })
+		members = members[:min(filter.Limit, uint64(len(members)))]
+
+		for _, m := range members {
+			recipients = append(recipients, model.NotificationRecipient{
+				MemberID: m.id,
+				UserUUID: m.userUUID,
+				Role:     int64(m.role),
+				Delivery: m.notify.Delivery,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetNotificationRecipients: %w", err)
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/distribution/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/repository/repotest"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+func createSociety(t *testing.T, repo *Repository, ownerUUID string) string {
+	t.Helper()
+
+	societyUUID, err := repo.CreateSociety(context.Background(), &model.SocietyData{
+		Name:           "society",
+		FormatID:       1,
+		PostPermission: 1,
+		OwnerUUID:      ownerUUID,
+	})
+	require.NoError(t, err)
+
+	return societyUUID
+}
+
+func TestRepository_Constraints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := New()
+	societyUUID := createSociety(t, repo, uuid.Generate().String())
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "unknown format",
+			run: func() error {
+				_, err := repo.CreateSociety(ctx, &model.SocietyData{Name: "s", FormatID: 4, PostPermission: 1})
+				return err
+			},
+			wantErr: ErrForeignKeyViolation,
+		},
+		{
+			name: "unknown post permission",
+			run: func() error {
+				_, err := repo.CreateSociety(ctx, &model.SocietyData{Name: "s", FormatID: 1, PostPermission: 5})
+				return err
+			},
+			wantErr: ErrForeignKeyViolation,
+		},
+		{
+			name: "name too long",
+			run: func() error {
+				_, err := repo.CreateSociety(ctx, &model.SocietyData{Name: strings.Repeat("я", 256), FormatID: 1, PostPermission: 1})
+				return err
+			},
+			wantErr: ErrValueTooLong,
+		},
+		{
+			name: "member of unknown society",
+			run: func() error {
+				_, err := repo.AddSocietyMembers(ctx, uuid.Generate().String(), uuid.Generate().String())
+				return err
+			},
+			wantErr: ErrForeignKeyViolation,
+		},
+		{
+			name: "request to unknown society",
+			run: func() error {
+				return repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: uuid.Generate().String(), SocietyUUID: uuid.Generate().String()})
+			},
+			wantErr: ErrForeignKeyViolation,
+		},
+		{
+			name:    "remove society with members",
+			run:     func() error { return repo.RemoveSociety(ctx, societyUUID) },
+			wantErr: ErrForeignKeyViolation,
+		},
+		{
+			name: "missing society",
+			run: func() error {
+				_, err := repo.GetOwner(ctx, uuid.Generate().String())
+				return err
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.run(), tt.wantErr)
+		})
+	}
+}
+
+func TestRepository_WithTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := New()
+	ownerUUID := uuid.Generate().String()
+	societyUUID := createSociety(t, repo, ownerUUID)
+
+	t.Run("rollback on error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+			if err := txRepo.RemoveSocietyMembersEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+
+		count, err := repo.CountSubscribe(ctx, societyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+			if err := txRepo.RemoveSocietyMembersEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			return txRepo.RemoveSociety(ctx, societyUUID)
+		})
+		require.NoError(t, err)
+
+		_, err = repo.GetSocietyInfo(ctx, societyUUID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestRepository_GetUserSocieties(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := New()
+	userUUID := uuid.Generate().String()
+
+	var societies []string
+	for i := 0; i < 3; i++ {
+		societyUUID := createSociety(t, repo, uuid.Generate().String())
+		_, err := repo.AddSocietyMembers(ctx, userUUID, societyUUID)
+		require.NoError(t, err)
+		societies = append(societies, societyUUID)
+	}
+
+	page, err := repo.GetUserSocieties(ctx, &model.UserSocietiesFilter{UserUUID: userUUID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, societies[2], page[0].SocietyUUID)
+	assert.Equal(t, societies[1], page[1].SocietyUUID)
+
+	next, err := repo.GetUserSocieties(ctx, &model.UserSocietiesFilter{UserUUID: userUUID, Limit: 2, AfterID: page[1].MemberID})
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	assert.Equal(t, societies[0], next[0].SocietyUUID)
+
+	total, err := repo.CountUserSocieties(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+}
+
+func TestRepository_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := New()
+	societyUUID := createSociety(t, repo, uuid.Generate().String())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userUUID := uuid.Generate().String()
+			_ = repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+				_, err := txRepo.AddSocietyMembers(ctx, userUUID, societyUUID)
+				return err
+			})
+			_, _ = repo.GetSocietyInfoForPeer(ctx, societyUUID, userUUID)
+		}()
+	}
+	wg.Wait()
+
+	count, err := repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(51), count)
+}
+
+func TestRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	repotest.Run(t, func(t *testing.T) repotest.Fixture {
+		repo := New()
+		return repotest.Fixture{
+			Repo: repo,
+			AddTag: func(t *testing.T, societyUUID string, tagID int64, isActive bool) {
+				require.NoError(t, repo.AddSocietyTag(context.Background(), societyUUID, tagID, isActive))
+			},
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+func (r *Repository) GetAutoApprovalRules(ctx context.Context, societyUUID string) ([]model.AutoApprovalRule, error) {
+	query, args, err := sq.Select(
+		"id",
+		"kind",
+		"COALESCE(member_of_society::text, '') AS member_of_society",
+		"COALESCE(daily_limit, 0) AS daily_limit",
+		"COALESCE(invite_code, '') AS invite_code",
+	).
+		From("society_auto_approval_rules").
+		Where(sq.Eq{"society_id": societyUUID}).
+		OrderBy("position").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	rules := []model.AutoApprovalRule{}
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &rules, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetAutoApprovalRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SetAutoApprovalRules заменяет правила целиком. Дневной лимит считается по виду правила,
+// поэтому правка правил не сбрасывает уже израсходованную квоту.
+func (r *Repository) SetAutoApprovalRules(ctx context.Context, societyUUID string, rules []model.AutoApprovalRule) error {
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		query, args, err := sq.Delete("society_auto_approval_rules").
+			Where(sq.Eq{"society_id": societyUUID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete society_auto_approval_rules: %w", err)
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+
+		insert := sq.Insert("society_auto_approval_rules").
+			Columns("society_id", "position", "kind", "member_of_society", "daily_limit", "invite_code")
+		for i, rule := range rules {
+			insert = insert.Values(societyUUID, i+1, rule.Kind,
+				sql.NullString{String: rule.MemberOfSocietyUUID, Valid: rule.MemberOfSocietyUUID != ""},
+				sql.NullInt64{Int64: rule.DailyLimit, Valid: rule.DailyLimit != 0},
+				sql.NullString{String: rule.InviteCode, Valid: rule.InviteCode != ""})
+		}
+		query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_auto_approval_rules insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_auto_approval_rules: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+// AutoApproveMembersRequest сохраняет заявку одобренной по rule и добавляет пользователя в участники.
+// Строка правила блокируется до конца транзакции: параллельные заявки не превысят дневной лимит,
+// а правило, удалённое параллельной правкой, заявку не одобрит. false — правило не сработало.
+func (r *Repository) AutoApproveMembersRequest(ctx context.Context, request *model.MembersRequest, rule *model.AutoApprovalRule) (bool, error) {
+	approved := false
+
+	err := r.withMemberEvent(ctx, model.MemberEventJoin, func(txRepo *Repository) error {
+		query, args, err := sq.Select("COALESCE(daily_limit, 0)").
+			From("society_auto_approval_rules").
+			Where(sq.Eq{"id": rule.ID, "society_id": request.SocietyUUID}).
+			Suffix("FOR UPDATE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		var dailyLimit int64
+		err = sqlx.GetContext(ctx, txRepo.db(), &dailyLimit, query, args...)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock society_auto_approval_rules: %w", err)
+		}
+
+		if rule.Kind == model.AutoApproveDailyLimit {
+			query, args, err = sq.Select("COUNT(*)").
+				From("members_requests").
+				Where(sq.Eq{"society_id": request.SocietyUUID, "auto_approval_rule_kind": model.AutoApproveDailyLimit}).
+				Where("update_at >= date_trunc('day', NOW())").
+				PlaceholderFormat(sq.Dollar).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("failed to build SQL query: %w", err)
+			}
+
+			var approvedToday int64
+			err = sqlx.GetContext(ctx, txRepo.db(), &approvedToday, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to count daily auto approvals: %w", err)
+			}
+			if approvedToday >= dailyLimit {
+				return nil
+			}
+		}
+
+		if err := txRepo.insertMembersRequest(ctx, request, 2, rule); err != nil {
+			return err
+		}
+
+		// одобренная заявка в заполненное сообщество ставит пользователя в очередь
+		if _, err := txRepo.joinOrWaitlist(ctx, request.UserUUID, request.SocietyUUID); err != nil {
+			return err
+		}
+
+		approved = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if approved {
+		r.pins.pin(ctx)
+	}
+	return approved, nil
+}
+
+// insertMembersRequest пишет заявку с ответами; rule задаётся для заявок, одобренных автоматически
+func (r *Repository) insertMembersRequest(ctx context.Context, request *model.MembersRequest, statusID int, rule *model.AutoApprovalRule) error {
+	insert := sq.Insert("members_requests").
+		Columns("user_uuid", "society_id", "status_id", "accepted_rules_version")
+	if rule != nil {
+		insert = insert.Columns("auto_approval_rule_id", "auto_approval_rule_kind").
+			Values(request.UserUUID, request.SocietyUUID, statusID, request.AcceptedRulesVersion, rule.ID, rule.Kind)
+	} else {
+		insert = insert.Values(request.UserUUID, request.SocietyUUID, statusID, request.AcceptedRulesVersion)
+	}
+
+	query, args, err := insert.Suffix("RETURNING id").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build add_members_requests insert query: %w", err)
+	}
+
+	var requestID int64
+	err = sqlx.GetContext(ctx, r.db(), &requestID, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert add_members_requests: %w", err)
+	}
+
+	return r.insertRequestAnswers(ctx, requestID, request.Answers)
+}
@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+func selectChannels(peerUUID string) sq.SelectBuilder {
+	return sq.Select(
+		"c.id",
+		"c.society_id",
+		"c.name",
+		"c.description",
+		"c.format_id",
+		"c.post_permission_id",
+		"(SELECT COUNT(*) FROM society_channel_members cm WHERE cm.channel_id = c.id) AS member_count",
+		"EXISTS (SELECT 1 FROM society_channel_members cm WHERE cm.channel_id = c.id AND cm.user_uuid = peer_uuid.id) AS is_member",
+		"c.created_at",
+	).
+		From("society_channels c").
+		JoinClause("CROSS JOIN (SELECT ?::uuid AS id) peer_uuid", peerUUID)
+}
+
+const channelNameConstraint = "uq_society_channels_name"
+
+// CreateChannel создаёт канал и добавляет в него создателя. Строка сообщества блокируется,
+// чтобы параллельные создания не превысили maxChannels
+func (r *Repository) CreateChannel(ctx context.Context, channel *model.Channel, creatorUUID string, maxChannels int) (string, error) {
+	var channelUUID string
+
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		var societyUUID string
+		err := sqlx.GetContext(ctx, txRepo.db(), &societyUUID, `SELECT id FROM society WHERE id = $1 FOR UPDATE`, channel.SocietyUUID)
+		if err != nil {
+			return fmt.Errorf("failed to lock society: %w", err)
+		}
+
+		var count int
+		err = sqlx.GetContext(ctx, txRepo.db(), &count, `SELECT COUNT(*) FROM society_channels WHERE society_id = $1`, channel.SocietyUUID)
+		if err != nil {
+			return fmt.Errorf("failed to count society_channels: %w", err)
+		}
+		if count >= maxChannels {
+			return model.ErrTooManyChannels
+		}
+
+		query, args, err := sq.Insert("society_channels").
+			Columns("society_id", "name", "description", "format_id", "post_permission_id").
+			Values(channel.SocietyUUID, channel.Name, channel.Description, channel.FormatID, channel.PostPermission).
+			Suffix("RETURNING id").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_channels insert query: %w", err)
+		}
+
+		err = sqlx.GetContext(ctx, txRepo.db(), &channelUUID, query, args...)
+		if isUniqueViolation(err, channelNameConstraint) {
+			return model.ErrChannelNameTaken
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert society_channels: %w", err)
+		}
+
+		return txRepo.insertChannelMember(ctx, channelUUID, creatorUUID)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r.pins.pin(ctx)
+	return channelUUID, nil
+}
+
+func (r *Repository) GetChannel(ctx context.Context, channelUUID, peerUUID string) (*model.Channel, error) {
+	query, args, err := selectChannels(peerUUID).
+		Where(sq.Eq{"c.id": channelUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var channel model.Channel
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &channel, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetChannel: %w", err)
+	}
+
+	return &channel, nil
+}
+
+func (r *Repository) GetSocietyChannels(ctx context.Context, societyUUID, peerUUID string) ([]model.Channel, error) {
+	query, args, err := selectChannels(peerUUID).
+		Where(sq.Eq{"c.society_id": societyUUID}).
+		OrderBy("c.created_at", "c.name").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var channels []model.Channel
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &channels, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyChannels: %w", err)
+	}
+
+	return channels, nil
+}
+
+func (r *Repository) UpdateChannel(ctx context.Context, channel *model.Channel) error {
+	query, args, err := sq.Update("society_channels").
+		Set("name", channel.Name).
+		Set("description", channel.Description).
+		Set("format_id", channel.FormatID).
+		Set("post_permission_id", channel.PostPermission).
+		Where(sq.Eq{"id": channel.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	err = r.execAffectingOne(ctx, "UpdateChannel", query, args...)
+	if isUniqueViolation(err, channelNameConstraint) {
+		return model.ErrChannelNameTaken
+	}
+	return err
+}
+
+// RemoveChannel удаляет канал вместе с его участниками
+func (r *Repository) RemoveChannel(ctx context.Context, channelUUID string) error {
+	query, args, err := sq.Delete("society_channels").
+		Where(sq.Eq{"id": channelUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, "RemoveChannel", query, args...)
+}
+
+// AddChannelMember падает с sql.ErrNoRows, если канала нет или пользователь не участник сообщества.
+// Повторное добавление ничего не меняет
+func (r *Repository) AddChannelMember(ctx context.Context, channelUUID, userUUID string) error {
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		return txRepo.insertChannelMember(ctx, channelUUID, userUUID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+func (r *Repository) RemoveChannelMember(ctx context.Context, channelUUID, userUUID string) error {
+	query, args, err := sq.Delete("society_channel_members").
+		Where(sq.Eq{"channel_id": channelUUID, "user_uuid": userUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, "RemoveChannelMember", query, args...)
+}
+
+// insertChannelMember держит строку участника сообщества FOR SHARE до конца транзакции:
+// параллельный выход из сообщества дождётся вставки, и триггер очистки уберёт её вместе с остальными
+func (r *Repository) insertChannelMember(ctx context.Context, channelUUID, userUUID string) error {
+	query, args, err := sq.Select("c.society_id").
+		From("society_channels c").
+		Join("society_members sm ON sm.society_id = c.society_id").
+		Where(sq.Eq{"c.id": channelUUID, "sm.user_uuid": userUUID}).
+		Limit(1).
+		Suffix("FOR SHARE OF sm").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var societyUUID string
+	err = sqlx.GetContext(ctx, r.db(), &societyUUID, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to lock society member: %w", err)
+	}
+
+	query, args, err = sq.Insert("society_channel_members").
+		Columns("channel_id", "society_id", "user_uuid").
+		Values(channelUUID, societyUUID, userUUID).
+		Suffix("ON CONFLICT (channel_id, user_uuid) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build society_channel_members insert query: %w", err)
+	}
+
+	_, err = r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert society_channel_members: %w", err)
+	}
+
+	return nil
+}
+
+// execAffectingOne выполняет изменение одной строки; если строки нет — sql.ErrNoRows
+func (r *Repository) execAffectingOne(ctx context.Context, name, query string, args ...any) error {
+	res, err := r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query %s: %w", name, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to execute query %s: %w", name, sql.ErrNoRows)
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/s21platform/society-service/internal/config"
+)
+
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+	uniqueViolationCode      = "23505"
+)
+
+func dsn(cfg config.Postgres) string {
+	params := []string{
+		"user=" + cfg.User,
+		"password=" + cfg.Password,
+		"dbname=" + cfg.Database,
+		"host=" + cfg.Host,
+		"port=" + cfg.Port,
+	}
+
+	return strings.Join(append(params, sessionParams(cfg)...), " ")
+}
+
+// migrationDSN подключает к основной базе без statement_timeout: создание индексов
+// и перенос данных в миграциях могут идти дольше лимита для запросов сервиса
+func migrationDSN(cfg config.Postgres) string {
+	cfg.StatementTimeout = 0
+	return dsn(cfg)
+}
+
+// replicaDSN дополняет DSN реплики теми же sslmode, сертификатами и statement_timeout,
+// что и у основной базы; заданные в самом DSN реплики значения важнее
+func replicaDSN(cfg config.Postgres, replica string) (string, error) {
+	if strings.HasPrefix(replica, "postgres://") || strings.HasPrefix(replica, "postgresql://") {
+		var err error
+		replica, err = pq.ParseURL(replica)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse replica DSN: %w", err)
+		}
+	}
+
+	return strings.Join(append(sessionParams(cfg), replica), " "), nil
+}
+
+// sessionParams — настройки TLS и сессии, общие для всех подключений сервиса
+func sessionParams(cfg config.Postgres) []string {
+	params := []string{"sslmode=" + cfg.SSLMode}
+	if cfg.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+cfg.SSLRootCert)
+	}
+	if cfg.SSLCert != "" {
+		params = append(params, "sslcert="+cfg.SSLCert)
+	}
+	if cfg.SSLKey != "" {
+		params = append(params, "sslkey="+cfg.SSLKey)
+	}
+	if cfg.StatementTimeout > 0 {
+		// lib/pq передаёт неизвестные параметры серверу как runtime-настройки сессии
+		params = append(params, fmt.Sprintf("statement_timeout=%d", cfg.StatementTimeout.Milliseconds()))
+	}
+
+	return params
+}
+
+func configurePool(db *sqlx.DB, cfg config.Postgres) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// backoff экспоненциальная задержка перед попыткой attempt (с нуля), не больше maxDelay
+func backoff(initial, maxDelay time.Duration, attempt int) time.Duration {
+	delay := initial
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if maxDelay > 0 && delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
+
+// isRetryableTxError ошибки, после которых транзакцию можно безопасно повторить целиком
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == serializationFailureCode || pqErr.Code == deadlockDetectedCode
+}
+
+// isUniqueViolation — запрос нарушил ограничение уникальности constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == uniqueViolationCode && pqErr.Constraint == constraint
+}
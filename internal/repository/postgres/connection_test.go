@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/s21platform/society-service/internal/config"
+)
+
+func TestDSN(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Postgres{
+		User:             "user",
+		Password:         "pass",
+		Database:         "society",
+		Host:             "localhost",
+		Port:             "5432",
+		SSLMode:          "verify-full",
+		SSLRootCert:      "/etc/ssl/ca.pem",
+		StatementTimeout: 3 * time.Second,
+	}
+
+	assert.Equal(t,
+		"user=user password=pass dbname=society host=localhost port=5432 sslmode=verify-full sslrootcert=/etc/ssl/ca.pem statement_timeout=3000",
+		dsn(cfg))
+	assert.Equal(t,
+		"user=user password=pass dbname=society host=localhost port=5432 sslmode=verify-full sslrootcert=/etc/ssl/ca.pem",
+		migrationDSN(cfg))
+}
+
+func TestReplicaDSN(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Postgres{
+		SSLMode:          "verify-full",
+		SSLRootCert:      "/etc/ssl/ca.pem",
+		StatementTimeout: 3 * time.Second,
+	}
+
+	replica, err := replicaDSN(cfg, "host=replica-1 port=5432 user=reader")
+	assert.NoError(t, err)
+	assert.Equal(t, "sslmode=verify-full sslrootcert=/etc/ssl/ca.pem statement_timeout=3000 host=replica-1 port=5432 user=reader", replica)
+
+	replica, err = replicaDSN(cfg, "postgres://reader@replica-2:5433/society?sslmode=require")
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"sslmode=verify-full sslrootcert=/etc/ssl/ca.pem statement_timeout=3000 dbname='society' host='replica-2' port='5433' sslmode='require' user='reader'",
+		replica, "the replica's own sslmode comes last and wins")
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 500*time.Millisecond, backoff(500*time.Millisecond, 10*time.Second, 0))
+	assert.Equal(t, 2*time.Second, backoff(500*time.Millisecond, 10*time.Second, 2))
+	assert.Equal(t, 10*time.Second, backoff(500*time.Millisecond, 10*time.Second, 10))
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isRetryableTxError(&pq.Error{Code: serializationFailureCode}))
+	assert.True(t, isRetryableTxError(fmt.Errorf("wrapped: %w", &pq.Error{Code: deadlockDetectedCode})))
+	assert.False(t, isRetryableTxError(&pq.Error{Code: "23505"}))
+	assert.False(t, isRetryableTxError(errors.New("boom")))
+	assert.False(t, isRetryableTxError(context.Canceled))
+}
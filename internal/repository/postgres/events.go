@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+func selectEvents(peerUUID string) sq.SelectBuilder {
+	return sq.Select(
+		"e.id",
+		"e.society_id",
+		"s.name AS society_name",
+		"e.author_uuid",
+		"e.title",
+		"e.description",
+		"e.starts_at",
+		"e.ends_at",
+		"e.location",
+		"e.link",
+		"COALESCE(e.capacity, 0) AS capacity",
+		"e.cancelled_at",
+		"(SELECT COUNT(*) FROM society_event_rsvps r WHERE r.event_id = e.id) AS going_count",
+		"EXISTS (SELECT 1 FROM society_event_rsvps r WHERE r.event_id = e.id AND r.user_uuid = peer_uuid.id) AS is_going",
+		"e.created_at",
+		"e.updated_at",
+	).
+		From("society_events e").
+		Join("society s ON s.id = e.society_id").
+		JoinClause("CROSS JOIN (SELECT ?::uuid AS id) peer_uuid", peerUUID)
+}
+
+func eventsInPeriod(query sq.SelectBuilder, filter *model.SocietyEventsFilter) sq.SelectBuilder {
+	query = query.Where(sq.Gt{"e.ends_at": filter.From})
+	if !filter.To.IsZero() {
+		query = query.Where(sq.Lt{"e.starts_at": filter.To})
+	}
+	return query.OrderBy("e.starts_at", "e.id").Limit(filter.Limit)
+}
+
+func eventCapacity(capacity int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: capacity, Valid: capacity > 0}
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, event *model.SocietyEvent) (string, error) {
+	query, args, err := sq.Insert("society_events").
+		Columns("society_id", "author_uuid", "title", "description", "starts_at", "ends_at", "location", "link", "capacity").
+		Values(event.SocietyUUID, event.AuthorUUID, event.Title, event.Description, event.StartsAt, event.EndsAt,
+			event.Location, event.Link, eventCapacity(event.Capacity)).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build society_events insert query: %w", err)
+	}
+
+	var eventUUID string
+	err = sqlx.GetContext(ctx, r.db(), &eventUUID, query, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert society_events: %w", err)
+	}
+
+	r.pins.pin(ctx)
+	return eventUUID, nil
+}
+
+func (r *Repository) GetEvent(ctx context.Context, eventUUID, peerUUID string) (*model.SocietyEvent, error) {
+	query, args, err := selectEvents(peerUUID).
+		Where(sq.Eq{"e.id": eventUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var event model.SocietyEvent
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &event, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetEvent: %w", err)
+	}
+
+	return &event, nil
+}
+
+// GetSocietyEvents возвращает события сообщества за период вместе с отменёнными, ближайшие первыми
+func (r *Repository) GetSocietyEvents(ctx context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+	query, args, err := eventsInPeriod(selectEvents(filter.PeerUUID), filter).
+		Where(sq.Eq{"e.society_id": filter.SocietyUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var events []model.SocietyEvent
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &events, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyEvents: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetUpcomingEvents возвращает неотменённые события всех сообществ, где состоит PeerUUID
+func (r *Repository) GetUpcomingEvents(ctx context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+	query, args, err := eventsInPeriod(selectEvents(filter.PeerUUID), filter).
+		Join("society_members sm ON sm.society_id = e.society_id AND sm.user_uuid = peer_uuid.id").
+		Where(sq.Eq{"e.cancelled_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var events []model.SocietyEvent
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &events, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetUpcomingEvents: %w", err)
+	}
+
+	return events, nil
+}
+
+// UpdateEvent меняет неотменённое событие. Вместимость ниже числа записавшихся никого не снимает,
+// но новые записи не принимаются, пока места не освободятся
+func (r *Repository) UpdateEvent(ctx context.Context, event *model.SocietyEvent) error {
+	query, args, err := sq.Update("society_events").
+		Set("title", event.Title).
+		Set("description", event.Description).
+		Set("starts_at", event.StartsAt).
+		Set("ends_at", event.EndsAt).
+		Set("location", event.Location).
+		Set("link", event.Link).
+		Set("capacity", eventCapacity(event.Capacity)).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": event.ID, "cancelled_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, "UpdateEvent", query, args...)
+}
+
+// CancelEvent отменяет событие; повторная отмена — sql.ErrNoRows
+func (r *Repository) CancelEvent(ctx context.Context, eventUUID string) error {
+	query, args, err := sq.Update("society_events").
+		Set("cancelled_at", sq.Expr("NOW()")).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": eventUUID, "cancelled_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, "CancelEvent", query, args...)
+}
+
+type eventSeats struct {
+	SocietyUUID string        `db:"society_id"`
+	Capacity    sql.NullInt64 `db:"capacity"`
+}
+
+type eventAttendance struct {
+	GoingCount int64 `db:"going_count"`
+	IsGoing    bool  `db:"is_going"`
+}
+
+// SetEventRSVP записывает участника сообщества на событие или снимает запись.
+// Запись падает с sql.ErrNoRows, если событие отменено или пользователь не участник сообщества,
+// и возвращает false, если мест нет. Снятие и повторная запись ничего не меняют
+func (r *Repository) SetEventRSVP(ctx context.Context, eventUUID, userUUID string, going bool) (bool, error) {
+	if !going {
+		query, args, err := sq.Delete("society_event_rsvps").
+			Where(sq.Eq{"event_id": eventUUID, "user_uuid": userUUID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return false, fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		_, err = r.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return false, fmt.Errorf("failed to execute query SetEventRSVP: %w", err)
+		}
+
+		r.pins.pin(ctx)
+		return true, nil
+	}
+
+	booked := false
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		booked = false
+
+		// строка события сериализует записи на него, поэтому вместимость не превышается
+		query, args, err := sq.Select("society_id", "capacity").
+			From("society_events").
+			Where(sq.Eq{"id": eventUUID, "cancelled_at": nil}).
+			Suffix("FOR UPDATE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		var seats eventSeats
+		err = sqlx.GetContext(ctx, txRepo.db(), &seats, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to lock society_events: %w", err)
+		}
+
+		// как и в insertChannelMember, выход из сообщества дождётся записи, и триггер её уберёт
+		query, args, err = sq.Select("1").
+			From("society_members").
+			Where(sq.Eq{"society_id": seats.SocietyUUID, "user_uuid": userUUID}).
+			Suffix("FOR SHARE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		var exists int
+		err = sqlx.GetContext(ctx, txRepo.db(), &exists, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to lock society member: %w", err)
+		}
+
+		query, args, err = sq.Select("COUNT(*) AS going_count").
+			Column(sq.Expr("COALESCE(BOOL_OR(user_uuid = ?), FALSE) AS is_going", userUUID)).
+			From("society_event_rsvps").
+			Where(sq.Eq{"event_id": eventUUID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		var attendance eventAttendance
+		err = sqlx.GetContext(ctx, txRepo.db(), &attendance, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to execute query eventAttendance: %w", err)
+		}
+		if attendance.IsGoing {
+			booked = true
+			return nil
+		}
+		if seats.Capacity.Valid && attendance.GoingCount >= seats.Capacity.Int64 {
+			return nil
+		}
+
+		query, args, err = sq.Insert("society_event_rsvps").
+			Columns("event_id", "user_uuid").
+			Values(eventUUID, userUUID).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_event_rsvps insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_event_rsvps: %w", err)
+		}
+
+		booked = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return booked, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// ключ advisory-блокировки, под которой одна реплика воркера истекает заявки
+const expireRequestsLockName = "society-service.expire-requests"
+
+// просроченные заявки переводятся в expired (status_id = 4), а для каждой в society_outbox
+// пишется событие, чтобы заявителя можно было уведомить
+const expirePendingRequestsQuery = `
+WITH candidates AS (
+    SELECT mr.id
+    FROM members_requests mr
+    JOIN society s ON s.id = mr.society_id
+    WHERE mr.status_id = 1
+      AND s.request_ttl_hours IS NOT NULL
+      AND mr.create_at < NOW() - make_interval(hours => s.request_ttl_hours)
+    ORDER BY mr.id
+    LIMIT $1
+    FOR UPDATE OF mr SKIP LOCKED
+),
+expired AS (
+    UPDATE members_requests mr
+    SET status_id = 4, update_at = NOW()
+    FROM candidates c
+    WHERE mr.id = c.id
+    RETURNING mr.id, mr.society_id, mr.user_uuid, mr.create_at
+),
+events AS (
+    INSERT INTO society_outbox (event_type, society_id, user_uuid, payload)
+    SELECT $2, society_id, user_uuid, jsonb_build_object('request_id', id, 'requested_at', create_at)
+    FROM expired
+)
+SELECT id, society_id, user_uuid FROM expired ORDER BY id`
+
+// SetRequestTTL задаёт срок жизни ожидающих заявок сообщества; 0 отключает истечение
+func (r *Repository) SetRequestTTL(ctx context.Context, societyUUID string, ttlHours int64) error {
+	query, args, err := sq.Update("society").
+		Set("request_ttl_hours", sql.NullInt64{Int64: ttlHours, Valid: ttlHours > 0}).
+		Where(sq.Eq{"id": societyUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	res, err := r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query SetRequestTTL: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows SetRequestTTL: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to execute query SetRequestTTL: %w", sql.ErrNoRows)
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+// ExpirePendingRequests истекает одну пачку просроченных заявок размером до batchSize.
+// locked = false, если пачку сейчас обрабатывает другая реплика воркера.
+func (r *Repository) ExpirePendingRequests(ctx context.Context, batchSize uint64) (expired []model.ExpiredRequest, locked bool, err error) {
+	err = r.withTx(ctx, func(txRepo *Repository) error {
+		err := sqlx.GetContext(ctx, txRepo.db(), &locked,
+			`SELECT pg_try_advisory_xact_lock(hashtext($1))`, expireRequestsLockName)
+		if err != nil {
+			return fmt.Errorf("failed to take advisory lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		err = sqlx.SelectContext(ctx, txRepo.db(), &expired, expirePendingRequestsQuery, batchSize, model.OutboxRequestExpired)
+		if err != nil {
+			return fmt.Errorf("failed to execute query expirePendingRequests: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return expired, locked, nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// ключ advisory-блокировки: привязки к родителю выполняются по одной, иначе две встречные
+// привязки могли бы обе пройти проверку и замкнуть цикл
+const hierarchyLockName = "society-service.hierarchy"
+
+// глубина, дальше которой рекурсивные запросы не поднимаются даже при испорченных данных
+const maxHierarchyDepth = 32
+
+// $1 — будущий родитель, $2 — сообщество: цикл появится, если сообщество уже среди предков родителя.
+// too_deep — обход дошёл до $3 и не добрался до корня, поэтому об отсутствии цикла судить нельзя
+const hierarchyCycleQuery = `
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id, 1 AS depth FROM society WHERE id = $1
+    UNION ALL
+    SELECT s.id, s.parent_id, a.depth + 1
+    FROM society s
+    JOIN ancestors a ON s.id = a.parent_id
+    WHERE a.depth < $3
+)
+SELECT
+    EXISTS (SELECT 1 FROM ancestors WHERE id = $2) AS cycle,
+    EXISTS (SELECT 1 FROM ancestors WHERE depth = $3 AND parent_id IS NOT NULL) AS too_deep`
+
+type hierarchyCheck struct {
+	Cycle   bool `db:"cycle"`
+	TooDeep bool `db:"too_deep"`
+}
+
+const societyAncestorsQuery = `
+WITH RECURSIVE ancestors AS (
+    SELECT p.id, p.name, p.photo_url, p.parent_id, p.inherit_parent_moderation, 1 AS depth
+    FROM society c
+    JOIN society p ON p.id = c.parent_id
+    WHERE c.id = $1
+    UNION ALL
+    SELECT p.id, p.name, p.photo_url, p.parent_id, p.inherit_parent_moderation, a.depth + 1
+    FROM society p
+    JOIN ancestors a ON p.id = a.parent_id
+    WHERE a.depth < $2
+)
+SELECT id, name, photo_url, COALESCE(parent_id::text, '') AS parent_id, inherit_parent_moderation, depth
+FROM ancestors
+ORDER BY depth`
+
+// inheritedModerationExpr — пользователь peerUUID владелец или администратор предка сообщества societyID,
+// от которого оно наследует модерацию. Права наследуются по цепочке, пока у очередного сообщества
+// включён inherit_parent_moderation. Аргументы — SQL-выражения, выражение можно встраивать в чужой запрос
+func inheritedModerationExpr(societyID, peerUUID string) string {
+	return fmt.Sprintf(`EXISTS (
+    WITH RECURSIVE moderating AS (
+        SELECT parent_id AS id, 1 AS depth
+        FROM society
+        WHERE id = %[1]s AND inherit_parent_moderation AND parent_id IS NOT NULL
+        UNION ALL
+        SELECT anc.parent_id, m.depth + 1
+        FROM society anc
+        JOIN moderating m ON anc.id = m.id
+        WHERE anc.inherit_parent_moderation AND anc.parent_id IS NOT NULL AND m.depth < %[3]d
+    )
+    SELECT 1
+    FROM moderating m
+    JOIN society_members sm ON sm.society_id = m.id
+    WHERE sm.user_uuid = %[2]s AND sm.role IN (1, 2)
+)`, societyID, peerUUID, maxHierarchyDepth)
+}
+
+// editorRoleExpr повторяет IsOwnerAdminModerator: своя роль владельца, администратора или модератора,
+// иначе модератор (3) по унаследованной модерации, иначе своя роль
+func editorRoleExpr(roleExpr, societyID, peerUUID string) string {
+	return fmt.Sprintf("CASE WHEN %[1]s IN (1, 2, 3) THEN %[1]s WHEN %[2]s THEN 3 ELSE %[1]s END",
+		roleExpr, inheritedModerationExpr(societyID, peerUUID))
+}
+
+// SetSocietyParent привязывает сообщество к родителю; пустой parentUUID отвязывает его.
+// Возвращает false, если привязка замкнула бы цикл, и model.ErrHierarchyTooDeep, если цепочка
+// предков родителя длиннее maxHierarchyDepth
+func (r *Repository) SetSocietyParent(ctx context.Context, societyUUID, parentUUID string, inheritModeration bool) (bool, error) {
+	parent := sql.NullString{String: parentUUID, Valid: parentUUID != ""}
+	acyclic := true
+
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		_, err := txRepo.db().ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, hierarchyLockName)
+		if err != nil {
+			return fmt.Errorf("failed to take advisory lock: %w", err)
+		}
+
+		if parent.Valid {
+			var check hierarchyCheck
+			err = sqlx.GetContext(ctx, txRepo.db(), &check, hierarchyCycleQuery, parentUUID, societyUUID, maxHierarchyDepth)
+			if err != nil {
+				return fmt.Errorf("failed to execute query hierarchyCycle: %w", err)
+			}
+			if check.Cycle {
+				acyclic = false
+				return nil
+			}
+			if check.TooDeep {
+				return model.ErrHierarchyTooDeep
+			}
+		}
+
+		query, args, err := sq.Update("society").
+			Set("parent_id", parent).
+			Set("inherit_parent_moderation", inheritModeration && parent.Valid).
+			Where(sq.Eq{"id": societyUUID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		return txRepo.execAffectingOne(ctx, "SetSocietyParent", query, args...)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acyclic, nil
+}
+
+func (r *Repository) GetSocietyChildren(ctx context.Context, societyUUID string) ([]model.SocietyNode, error) {
+	query, args, err := sq.Select(
+		"id",
+		"name",
+		"photo_url",
+		"parent_id",
+		"inherit_parent_moderation",
+		"1 AS depth",
+	).
+		From("society").
+		Where(sq.Eq{"parent_id": societyUUID}).
+		OrderBy("name", "id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var children []model.SocietyNode
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &children, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyChildren: %w", err)
+	}
+
+	return children, nil
+}
+
+// GetSocietyAncestors возвращает предков от непосредственного родителя до корня
+func (r *Repository) GetSocietyAncestors(ctx context.Context, societyUUID string) ([]model.SocietyNode, error) {
+	var ancestors []model.SocietyNode
+	err := sqlx.SelectContext(ctx, r.readDB(ctx), &ancestors, societyAncestorsQuery, societyUUID, maxHierarchyDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyAncestors: %w", err)
+	}
+
+	return ancestors, nil
+}
+
+// hasInheritedModeration — владелец или администратор предка, от которого сообщество наследует модерацию
+func (r *Repository) hasInheritedModeration(ctx context.Context, peerUUID, societyUUID string) (bool, error) {
+	var inherited bool
+	err := sqlx.GetContext(ctx, r.db(), &inherited, "SELECT "+inheritedModerationExpr("$1", "$2"), societyUUID, peerUUID)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query inheritedModeration: %w", err)
+	}
+
+	return inherited, nil
+}
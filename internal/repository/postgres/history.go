@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// withMemberEvent выполняет fn в транзакции, где триггер society_members_history
+// запишет удаление участника как eventType, а автором изменения пользователя из контекста
+func (r *Repository) withMemberEvent(ctx context.Context, eventType string, fn func(txRepo *Repository) error) error {
+	return r.withTx(ctx, func(txRepo *Repository) error {
+		actorUUID, _ := ctx.Value(config.KeyUUID).(string)
+		_, err := txRepo.db().ExecContext(ctx,
+			`SELECT set_config('society.member_event', $1, true), set_config('society.actor_uuid', $2, true)`,
+			eventType, actorUUID)
+		if err != nil {
+			return fmt.Errorf("failed to set member event: %w", err)
+		}
+
+		if err := fn(txRepo); err != nil {
+			return err
+		}
+
+		// настройки живут до конца транзакции, а она может продолжиться во внешнем WithTx
+		_, err = txRepo.db().ExecContext(ctx,
+			`SELECT set_config('society.member_event', '', true), set_config('society.actor_uuid', '', true)`)
+		if err != nil {
+			return fmt.Errorf("failed to reset member event: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) GetMembershipTimeline(ctx context.Context, filter *model.MembershipTimelineFilter) ([]model.MembershipEvent, error) {
+	query := sq.Select(
+		"id",
+		"society_id",
+		"user_uuid",
+		"event_type",
+		"role",
+		"COALESCE(previous_role, 0) AS previous_role",
+		"payment_status",
+		"COALESCE(previous_payment_status, 0) AS previous_payment_status",
+		"COALESCE(actor_uuid, '') AS actor_uuid",
+		"created_at",
+	).
+		From("society_membership_events").
+		OrderBy("id DESC").
+		Limit(filter.Limit)
+
+	if filter.UserUUID != "" {
+		query = query.Where(sq.Eq{"user_uuid": filter.UserUUID})
+	}
+	if filter.SocietyUUID != "" {
+		query = query.Where(sq.Eq{"society_id": filter.SocietyUUID})
+	}
+	if filter.AfterID > 0 {
+		query = query.Where(sq.Lt{"id": filter.AfterID})
+	}
+
+	sqlString, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var events []model.MembershipEvent
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &events, sqlString, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetMembershipTimeline: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"github.com/s21platform/society-service/migrations"
+)
+
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+	MigrateRedo   = "redo"
+)
+
+// Migrate применяет встроенные миграции. Все команды берут advisory lock в Postgres,
+// поэтому несколько реплик, стартующих с автомиграцией, не накатывают миграции одновременно.
+// Миграции идут через отдельное подключение без statement_timeout.
+func (r *Repository) Migrate(ctx context.Context, command string, out io.Writer) error {
+	db, err := sql.Open("postgres", r.migrationDSN)
+	if err != nil {
+		return fmt.Errorf("failed to open migration connection: %w", err)
+	}
+	defer db.Close()
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return fmt.Errorf("failed to create migration locker: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create migration provider: %w", err)
+	}
+
+	switch command {
+	case MigrateUp:
+		results, err := provider.Up(ctx)
+		printResults(out, results...)
+		if err != nil {
+			return fmt.Errorf("failed to migrate up: %w", err)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "no migrations to apply")
+		}
+	case MigrateDown:
+		result, err := provider.Down(ctx)
+		printResults(out, result)
+		if err != nil {
+			return fmt.Errorf("failed to migrate down: %w", err)
+		}
+	case MigrateRedo:
+		result, err := provider.Down(ctx)
+		printResults(out, result)
+		if err != nil {
+			return fmt.Errorf("failed to migrate down: %w", err)
+		}
+		result, err = provider.UpByOne(ctx)
+		printResults(out, result)
+		if err != nil {
+			return fmt.Errorf("failed to migrate up: %w", err)
+		}
+	case MigrateStatus:
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get migration status: %w", err)
+		}
+		for _, st := range statuses {
+			appliedAt := "pending"
+			if st.State == goose.StateApplied {
+				appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%-20s %s\n", appliedAt, st.Source.Path)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected one of: up, down, status, redo", command)
+	}
+
+	return nil
+}
+
+func printResults(out io.Writer, results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil || result.Source == nil {
+			continue
+		}
+		fmt.Fprintf(out, "%-4s %s (%v)\n", result.Direction, result.Source.Path, result.Duration)
+	}
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// колонки society_members с подпиской на каждый вид активности
+var notifyColumns = map[string]string{
+	model.NotifyPosts:         "sm.notify_posts",
+	model.NotifyAnnouncements: "sm.notify_announcements",
+	model.NotifyEvents:        "sm.notify_events",
+	model.NotifyJoinRequests:  "sm.notify_join_requests",
+}
+
+// роли, которым вообще приходят уведомления о виде активности; не указан — всем
+var notifyRoles = map[string][]int64{
+	model.NotifyJoinRequests: {1, 2, 3},
+}
+
+// GetNotificationSettings падает с sql.ErrNoRows, если пользователь не участник сообщества
+func (r *Repository) GetNotificationSettings(ctx context.Context, societyUUID, userUUID string) (*model.NotificationSettings, error) {
+	query, args, err := sq.Select(
+		"society_id",
+		"user_uuid",
+		"notify_posts",
+		"notify_announcements",
+		"notify_events",
+		"notify_join_requests",
+		"notify_delivery",
+		"notify_muted",
+		"notify_muted_until",
+	).
+		From("society_members").
+		Where(sq.Eq{"society_id": societyUUID, "user_uuid": userUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var settings model.NotificationSettings
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &settings, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetNotificationSettings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SetNotificationSettings заменяет все настройки; не участник — sql.ErrNoRows
+func (r *Repository) SetNotificationSettings(ctx context.Context, settings *model.NotificationSettings) error {
+	query, args, err := sq.Update("society_members").
+		Set("notify_posts", settings.Posts).
+		Set("notify_announcements", settings.Announcements).
+		Set("notify_events", settings.Events).
+		Set("notify_join_requests", settings.JoinRequests).
+		Set("notify_delivery", settings.Delivery).
+		Set("notify_muted", settings.Muted).
+		Set("notify_muted_until", settings.MutedUntil).
+		Where(sq.Eq{"society_id": settings.SocietyUUID, "user_uuid": settings.UserUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, "SetNotificationSettings", query, args...)
+}
+
+// GetNotificationRecipients возвращает участников, которые хотят уведомления об активности
+// и сейчас не заглушили сообщество, новые участники первыми
+func (r *Repository) GetNotificationRecipients(ctx context.Context, filter *model.NotificationRecipientsFilter) ([]model.NotificationRecipient, error) {
+	column, ok := notifyColumns[filter.Activity]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification activity %q", filter.Activity)
+	}
+
+	query := sq.Select("sm.id", "sm.user_uuid", "sm.role", "sm.notify_delivery").
+		From("society_members sm").
+		Where(sq.Eq{"sm.society_id": filter.SocietyUUID}).
+		Where(column).
+		Where("NOT (sm.notify_muted AND (sm.notify_muted_until IS NULL OR sm.notify_muted_until > NOW()))").
+		OrderBy("sm.id DESC").
+		Limit(filter.Limit)
+
+	if roles, ok := notifyRoles[filter.Activity]; ok {
+		query = query.Where(sq.Eq{"sm.role": roles})
+	}
+	if len(filter.Roles) > 0 {
+		query = query.Where(sq.Eq{"sm.role": filter.Roles})
+	}
+	if filter.ExcludeUUID != "" {
+		query = query.Where(sq.NotEq{"sm.user_uuid": filter.ExcludeUUID})
+	}
+	if filter.AfterID > 0 {
+		query = query.Where(sq.Lt{"sm.id": filter.AfterID})
+	}
+
+	sqlString, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var recipients []model.NotificationRecipient
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &recipients, sqlString, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetNotificationRecipients: %w", err)
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// SKIP LOCKED: параллельные отправщики берут разные пачки
+const unpublishedOutboxQuery = `
+SELECT id, event_type, society_id, user_uuid, payload, created_at
+FROM society_outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED`
+
+// PublishOutbox отдаёт publish пачку неотправленных событий до batchSize и помечает их
+// отправленными, если publish вернул nil. Строки заблокированы, пока идёт publish; если после
+// успешной отправки транзакция не закоммитится, пачка уйдёт повторно (at-least-once).
+// Возвращает число отправленных событий
+func (r *Repository) PublishOutbox(ctx context.Context, batchSize uint64, publish func(ctx context.Context, events []model.OutboxEvent) error) (int, error) {
+	var published int
+
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		var events []model.OutboxEvent
+		err := sqlx.SelectContext(ctx, txRepo.db(), &events, unpublishedOutboxQuery, batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to execute query unpublishedOutbox: %w", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := publish(ctx, events); err != nil {
+			return fmt.Errorf("failed to publish outbox events: %w", err)
+		}
+
+		ids := make([]int64, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		_, err = txRepo.db().ExecContext(ctx,
+			`UPDATE society_outbox SET published_at = NOW() WHERE id = ANY($1)`, pq.Array(ids))
+		if err != nil {
+			return fmt.Errorf("failed to mark society_outbox published: %w", err)
+		}
+
+		published = len(events)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
@@ -0,0 +1,300 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+func selectPolls(peerUUID string) sq.SelectBuilder {
+	return sq.Select(
+		"p.id",
+		"p.society_id",
+		"p.author_uuid",
+		"p.question",
+		"p.is_multiple",
+		"p.is_anonymous",
+		"p.results_visibility",
+		"p.closes_at",
+		"p.created_at",
+		"(SELECT COUNT(*) FROM society_poll_ballots b WHERE b.poll_id = p.id) AS voter_count",
+		"EXISTS (SELECT 1 FROM society_poll_ballots b WHERE b.poll_id = p.id AND b.user_uuid = peer_uuid.id) AS has_voted",
+	).
+		From("society_polls p").
+		JoinClause("CROSS JOIN (SELECT ?::uuid AS id) peer_uuid", peerUUID)
+}
+
+// CreatePoll создаёт опрос с вариантами в порядке Options
+func (r *Repository) CreatePoll(ctx context.Context, poll *model.Poll) (string, error) {
+	var pollUUID string
+
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		query, args, err := sq.Insert("society_polls").
+			Columns("society_id", "author_uuid", "question", "is_multiple", "is_anonymous", "results_visibility", "closes_at").
+			Values(poll.SocietyUUID, poll.AuthorUUID, poll.Question, poll.IsMultiple, poll.IsAnonymous, poll.ResultsVisibility, poll.ClosesAt).
+			Suffix("RETURNING id").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_polls insert query: %w", err)
+		}
+
+		err = sqlx.GetContext(ctx, txRepo.db(), &pollUUID, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_polls: %w", err)
+		}
+
+		insert := sq.Insert("society_poll_options").Columns("poll_id", "position", "text")
+		for i, option := range poll.Options {
+			insert = insert.Values(pollUUID, i+1, option.Text)
+		}
+		query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_poll_options insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_poll_options: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r.pins.pin(ctx)
+	return pollUUID, nil
+}
+
+func (r *Repository) GetPoll(ctx context.Context, pollUUID, peerUUID string) (*model.Poll, error) {
+	query, args, err := selectPolls(peerUUID).
+		Where(sq.Eq{"p.id": pollUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var poll model.Poll
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &poll, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetPoll: %w", err)
+	}
+
+	polls := []model.Poll{poll}
+	if err := r.loadPollOptions(ctx, polls); err != nil {
+		return nil, err
+	}
+
+	return &polls[0], nil
+}
+
+// GetSocietyPolls возвращает опросы сообщества, новые первыми
+func (r *Repository) GetSocietyPolls(ctx context.Context, filter *model.SocietyPollsFilter) ([]model.Poll, error) {
+	query, args, err := selectPolls(filter.PeerUUID).
+		Where(sq.Eq{"p.society_id": filter.SocietyUUID}).
+		OrderBy("p.created_at DESC", "p.id").
+		Limit(filter.Limit).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var polls []model.Poll
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &polls, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyPolls: %w", err)
+	}
+
+	if err := r.loadPollOptions(ctx, polls); err != nil {
+		return nil, err
+	}
+
+	return polls, nil
+}
+
+type pollVoter struct {
+	OptionID  int64  `db:"option_id"`
+	VoterUUID string `db:"voter_uuid"`
+}
+
+// loadPollOptions дополняет опросы вариантами с числом голосов и, для неанонимных, голосовавшими
+func (r *Repository) loadPollOptions(ctx context.Context, polls []model.Poll) error {
+	if len(polls) == 0 {
+		return nil
+	}
+
+	pollUUIDs := make([]string, 0, len(polls))
+	index := make(map[string]int, len(polls))
+	for i, poll := range polls {
+		pollUUIDs = append(pollUUIDs, poll.ID)
+		index[poll.ID] = i
+	}
+
+	query, args, err := sq.Select(
+		"o.id",
+		"o.poll_id",
+		"o.position",
+		"o.text",
+		"(SELECT COUNT(*) FROM society_poll_votes v WHERE v.poll_id = o.poll_id AND v.option_id = o.id) AS vote_count",
+	).
+		From("society_poll_options o").
+		Where(sq.Eq{"o.poll_id": pollUUIDs}).
+		OrderBy("o.poll_id", "o.position").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var options []model.PollOption
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &options, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query GetPollOptions: %w", err)
+	}
+
+	query, args, err = sq.Select("option_id", "voter_uuid").
+		From("society_poll_votes").
+		Where(sq.Eq{"poll_id": pollUUIDs}).
+		Where(sq.NotEq{"voter_uuid": nil}).
+		OrderBy("option_id", "voter_uuid").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var voters []pollVoter
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &voters, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query GetPollVoters: %w", err)
+	}
+
+	byOption := make(map[int64][]string, len(options))
+	for _, voter := range voters {
+		byOption[voter.OptionID] = append(byOption[voter.OptionID], voter.VoterUUID)
+	}
+	for _, option := range options {
+		option.VoterUUIDs = byOption[option.ID]
+		i := index[option.PollUUID]
+		polls[i].Options = append(polls[i].Options, option)
+	}
+
+	return nil
+}
+
+type pollBallot struct {
+	SocietyUUID string `db:"society_id"`
+	IsAnonymous bool   `db:"is_anonymous"`
+}
+
+// VotePoll принимает бюллетень участника сообщества. Падает с sql.ErrNoRows, если опрос закрыт
+// или пользователь не участник; false — пользователь уже голосовал
+func (r *Repository) VotePoll(ctx context.Context, pollUUID, userUUID string, optionIDs []int64) (bool, error) {
+	voted := false
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		voted = false
+
+		// FOR SHARE: закрытие опроса дождётся бюллетеней, принятых до него
+		query, args, err := sq.Select("society_id", "is_anonymous").
+			From("society_polls").
+			Where(sq.Eq{"id": pollUUID}).
+			Where(sq.Or{sq.Eq{"closes_at": nil}, sq.Expr("closes_at > NOW()")}).
+			Suffix("FOR SHARE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		var ballot pollBallot
+		err = sqlx.GetContext(ctx, txRepo.db(), &ballot, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to lock society_polls: %w", err)
+		}
+
+		query, args, err = sq.Select("1").
+			From("society_members").
+			Where(sq.Eq{"society_id": ballot.SocietyUUID, "user_uuid": userUUID}).
+			Suffix("FOR SHARE").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		var exists int
+		err = sqlx.GetContext(ctx, txRepo.db(), &exists, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to lock society member: %w", err)
+		}
+
+		query, args, err = sq.Insert("society_poll_ballots").
+			Columns("poll_id", "user_uuid").
+			Values(pollUUID, userUUID).
+			Suffix("ON CONFLICT (poll_id, user_uuid) DO NOTHING").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_poll_ballots insert query: %w", err)
+		}
+
+		res, err := txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_poll_ballots: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows VotePoll: %w", err)
+		}
+		if affected == 0 {
+			return nil
+		}
+
+		voter := sql.NullString{String: userUUID, Valid: !ballot.IsAnonymous}
+		insert := sq.Insert("society_poll_votes").Columns("poll_id", "option_id", "voter_uuid")
+		for _, optionID := range optionIDs {
+			insert = insert.Values(pollUUID, optionID, voter)
+		}
+		query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_poll_votes insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_poll_votes: %w", err)
+		}
+
+		voted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return voted, nil
+}
+
+// ClosePoll закрывает открытый опрос сейчас; закрытый — sql.ErrNoRows
+func (r *Repository) ClosePoll(ctx context.Context, pollUUID string) error {
+	query, args, err := sq.Update("society_polls").
+		Set("closes_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": pollUUID}).
+		Where(sq.Or{sq.Eq{"closes_at": nil}, sq.Expr("closes_at > NOW()")}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, "ClosePoll", query, args...)
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+type questionRow struct {
+	ID         int64          `db:"id"`
+	Kind       string         `db:"kind"`
+	Prompt     string         `db:"prompt"`
+	Options    pq.StringArray `db:"options"`
+	IsRequired bool           `db:"is_required"`
+}
+
+type requestRow struct {
+	ID                   int64     `db:"id"`
+	UserUUID             string    `db:"user_uuid"`
+	SocietyUUID          string    `db:"society_id"`
+	AcceptedRulesVersion int64     `db:"accepted_rules_version"`
+	CreatedAt            time.Time `db:"create_at"`
+}
+
+type answerRow struct {
+	RequestID  int64          `db:"request_id"`
+	QuestionID int64          `db:"question_id"`
+	Prompt     string         `db:"prompt"`
+	Answer     pq.StringArray `db:"answer"`
+}
+
+func (r *Repository) GetSocietyQuestions(ctx context.Context, societyUUID string) ([]model.SocietyQuestion, error) {
+	query, args, err := sq.Select("id", "kind", "prompt", "options", "is_required").
+		From("society_questions").
+		Where(sq.Eq{"society_id": societyUUID}).
+		OrderBy("position").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var rows []questionRow
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &rows, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyQuestions: %w", err)
+	}
+
+	questions := make([]model.SocietyQuestion, 0, len(rows))
+	for _, row := range rows {
+		questions = append(questions, model.SocietyQuestion{
+			ID:         row.ID,
+			Kind:       row.Kind,
+			Prompt:     row.Prompt,
+			Options:    []string(row.Options),
+			IsRequired: row.IsRequired,
+		})
+	}
+	return questions, nil
+}
+
+// SetSocietyQuestions заменяет анкету целиком; вопросы получают новые id,
+// поэтому ответы на устаревшую анкету не пройдут проверку
+func (r *Repository) SetSocietyQuestions(ctx context.Context, societyUUID string, questions []model.SocietyQuestion) error {
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		query, args, err := sq.Delete("society_questions").
+			Where(sq.Eq{"society_id": societyUUID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete society_questions: %w", err)
+		}
+		if len(questions) == 0 {
+			return nil
+		}
+
+		insert := sq.Insert("society_questions").Columns("society_id", "position", "kind", "prompt", "options", "is_required")
+		for i, question := range questions {
+			options := question.Options
+			if options == nil {
+				options = []string{}
+			}
+			insert = insert.Values(societyUUID, i+1, question.Kind, question.Prompt, pq.StringArray(options), question.IsRequired)
+		}
+		query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_questions insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_questions: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+func (r *Repository) insertRequestAnswers(ctx context.Context, requestID int64, answers []model.RequestAnswer) error {
+	if len(answers) == 0 {
+		return nil
+	}
+
+	insert := sq.Insert("members_requests_answers").Columns("request_id", "question_id", "position", "prompt", "answer")
+	for i, answer := range answers {
+		insert = insert.Values(requestID, answer.QuestionID, i+1, answer.Prompt, pq.StringArray(answer.Values))
+	}
+	query, args, err := insert.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build members_requests_answers insert query: %w", err)
+	}
+
+	_, err = r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert members_requests_answers: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingRequests возвращает ожидающие заявки вместе с ответами на анкету, старые первыми
+func (r *Repository) GetPendingRequests(ctx context.Context, filter *model.PendingRequestsFilter) ([]model.MembersRequest, error) {
+	query := sq.Select("id", "user_uuid", "society_id", "accepted_rules_version", "create_at").
+		From("members_requests").
+		Where(sq.Eq{"society_id": filter.SocietyUUID, "status_id": 1}).
+		OrderBy("id").
+		Limit(filter.Limit)
+	if filter.AfterID > 0 {
+		query = query.Where(sq.Gt{"id": filter.AfterID})
+	}
+
+	sqlString, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var rows []requestRow
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &rows, sqlString, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetPendingRequests: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	requestIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		requestIDs = append(requestIDs, row.ID)
+	}
+
+	sqlString, args, err = sq.Select("request_id", "question_id", "prompt", "answer").
+		From("members_requests_answers").
+		Where("request_id = ANY(?)", pq.Array(requestIDs)).
+		OrderBy("request_id", "position").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var answers []answerRow
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &answers, sqlString, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetPendingRequests answers: %w", err)
+	}
+
+	byRequest := make(map[int64][]model.RequestAnswer, len(rows))
+	for _, answer := range answers {
+		byRequest[answer.RequestID] = append(byRequest[answer.RequestID], model.RequestAnswer{
+			QuestionID: answer.QuestionID,
+			Prompt:     answer.Prompt,
+			Values:     []string(answer.Answer),
+		})
+	}
+
+	requests := make([]model.MembersRequest, 0, len(rows))
+	for _, row := range rows {
+		requests = append(requests, model.MembersRequest{
+			ID:                   row.ID,
+			UserUUID:             row.UserUUID,
+			SocietyUUID:          row.SocietyUUID,
+			AcceptedRulesVersion: row.AcceptedRulesVersion,
+			Answers:              byRequest[row.ID],
+			CreatedAt:            row.CreatedAt,
+		})
+	}
+	return requests, nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// сообщества, у которых society.member_count или society_member_counters разошлись с society_members
+const memberCountDriftQuery = `
+WITH actual AS (
+    SELECT society_id, role, payment_status, count(*) AS count
+    FROM society_members
+    GROUP BY society_id, role, payment_status
+),
+counters_drift AS (
+    SELECT DISTINCT COALESCE(c.society_id, a.society_id) AS society_id
+    FROM society_member_counters c
+    FULL JOIN actual a
+        ON a.society_id = c.society_id AND a.role = c.role AND a.payment_status = c.payment_status
+    WHERE COALESCE(c.count, 0) <> COALESCE(a.count, 0)
+)
+SELECT s.id AS society_id, s.member_count AS stored, COALESCE(t.count, 0) AS actual
+FROM society s
+LEFT JOIN (
+    SELECT society_id, sum(count) AS count
+    FROM actual
+    WHERE payment_status <> 3
+    GROUP BY society_id
+) t ON t.society_id = s.id
+WHERE s.member_count <> COALESCE(t.count, 0)
+   OR s.id IN (SELECT society_id FROM counters_drift)
+ORDER BY s.id`
+
+// ReconcileMemberCounts находит расхождения денормализованных счётчиков участников.
+// При fix = true счётчики найденных сообществ пересчитываются в той же транзакции.
+func (r *Repository) ReconcileMemberCounts(ctx context.Context, fix bool) ([]model.MemberCountDrift, error) {
+	var drifts []model.MemberCountDrift
+
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		err := sqlx.SelectContext(ctx, txRepo.db(), &drifts, memberCountDriftQuery)
+		if err != nil {
+			return fmt.Errorf("failed to execute query memberCountDrift: %w", err)
+		}
+		if !fix || len(drifts) == 0 {
+			return nil
+		}
+
+		societyUUIDs := make([]string, 0, len(drifts))
+		for _, drift := range drifts {
+			societyUUIDs = append(societyUUIDs, drift.SocietyUUID)
+		}
+
+		// блокируем строки сообществ: вставка в society_members проверяет внешний ключ на society,
+		// а триггер счётчиков обновляет society.member_count, так что параллельные вступления
+		// и выходы дождутся конца пересчёта. Блокировка строк участников не мешала вставке новых
+		_, err = txRepo.db().ExecContext(ctx,
+			`SELECT id FROM society WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(societyUUIDs))
+		if err != nil {
+			return fmt.Errorf("failed to lock society: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx,
+			`DELETE FROM society_member_counters WHERE society_id = ANY($1)`, pq.Array(societyUUIDs))
+		if err != nil {
+			return fmt.Errorf("failed to reset society_member_counters: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, `
+			INSERT INTO society_member_counters (society_id, role, payment_status, count)
+			SELECT society_id, role, payment_status, count(*)
+			FROM society_members
+			WHERE society_id = ANY($1)
+			GROUP BY society_id, role, payment_status`, pq.Array(societyUUIDs))
+		if err != nil {
+			return fmt.Errorf("failed to recompute society_member_counters: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, `
+			UPDATE society s
+			SET member_count = (
+				SELECT count(*) FROM society_members sm
+				WHERE sm.society_id = s.id AND sm.payment_status <> 3
+			)
+			WHERE s.id = ANY($1)`, pq.Array(societyUUIDs))
+		if err != nil {
+			return fmt.Errorf("failed to recompute society.member_count: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return drifts, nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/config"
+)
+
+type replica struct {
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// replicaPool раздаёт реплики по кругу, пропуская те, что не прошли последнюю проверку
+type replicaPool struct {
+	replicas []*replica
+	next     atomic.Uint64
+	stop     context.CancelFunc
+}
+
+func newReplicaPool(cfg config.Postgres, dsns []string, healthInterval time.Duration) *replicaPool {
+	pool := &replicaPool{}
+	for _, configured := range dsns {
+		replicaDSN, err := replicaDSN(cfg, configured)
+		if err != nil {
+			log.Printf("failed to open replica: %v", err)
+			continue
+		}
+		db, err := sqlx.Open("postgres", replicaDSN)
+		if err != nil {
+			log.Printf("failed to open replica: %v", err)
+			continue
+		}
+		configurePool(db, cfg)
+		pool.replicas = append(pool.replicas, &replica{db: db})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.stop = cancel
+	pool.checkHealth(ctx)
+	if len(pool.replicas) > 0 && healthInterval > 0 {
+		go pool.watchHealth(ctx, healthInterval)
+	}
+
+	return pool
+}
+
+func (p *replicaPool) checkHealth(ctx context.Context) {
+	for _, rep := range p.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, time.Second)
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+
+		if wasHealthy := rep.healthy.Swap(err == nil); wasHealthy && err != nil {
+			log.Printf("replica is unhealthy: %v", err)
+		}
+	}
+}
+
+func (p *replicaPool) watchHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.checkHealth(ctx)
+		}
+	}
+}
+
+// pick возвращает следующую здоровую реплику или nil, если таких нет
+func (p *replicaPool) pick() *sqlx.DB {
+	if p == nil || len(p.replicas) == 0 {
+		return nil
+	}
+
+	start := p.next.Add(1)
+	for i := 0; i < len(p.replicas); i++ {
+		rep := p.replicas[(start+uint64(i))%uint64(len(p.replicas))]
+		if rep.healthy.Load() {
+			return rep.db
+		}
+	}
+
+	return nil
+}
+
+func (p *replicaPool) Close() {
+	if p == nil {
+		return
+	}
+
+	p.stop()
+	for _, rep := range p.replicas {
+		_ = rep.db.Close()
+	}
+}
+
+const pinSweepThreshold = 10000
+
+// primaryPins после записи на короткое окно направляет чтения пользователя на мастер (read-your-writes)
+type primaryPins struct {
+	mu     sync.Mutex
+	window time.Duration
+	until  map[string]time.Time
+	now    func() time.Time
+}
+
+func newPrimaryPins(window time.Duration) *primaryPins {
+	if window <= 0 {
+		return nil
+	}
+
+	return &primaryPins{
+		window: window,
+		until:  make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+func (p *primaryPins) pin(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	userUUID, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok || userUUID == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if len(p.until) >= pinSweepThreshold {
+		for key, until := range p.until {
+			if now.After(until) {
+				delete(p.until, key)
+			}
+		}
+	}
+	p.until[userUUID] = now.Add(p.window)
+}
+
+func (p *primaryPins) isPinned(ctx context.Context) bool {
+	if p == nil {
+		return false
+	}
+	userUUID, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	until, ok := p.until[userUUID]
+	return ok && p.now().Before(until)
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/s21platform/society-service/internal/config"
+)
+
+func TestReplicaPool_Pick(t *testing.T) {
+	t.Parallel()
+
+	first, second, third := &sqlx.DB{}, &sqlx.DB{}, &sqlx.DB{}
+	pool := &replicaPool{replicas: []*replica{{db: first}, {db: second}, {db: third}}}
+	for _, rep := range pool.replicas {
+		rep.healthy.Store(true)
+	}
+
+	t.Run("round robin over healthy replicas", func(t *testing.T) {
+		picked := map[*sqlx.DB]int{}
+		for i := 0; i < 6; i++ {
+			picked[pool.pick()]++
+		}
+		assert.Equal(t, map[*sqlx.DB]int{first: 2, second: 2, third: 2}, picked)
+	})
+
+	t.Run("unhealthy replicas are skipped", func(t *testing.T) {
+		pool.replicas[1].healthy.Store(false)
+		for i := 0; i < 6; i++ {
+			assert.NotSame(t, second, pool.pick())
+		}
+	})
+
+	t.Run("no healthy replicas", func(t *testing.T) {
+		for _, rep := range pool.replicas {
+			rep.healthy.Store(false)
+		}
+		assert.Nil(t, pool.pick())
+	})
+
+	t.Run("nil pool", func(t *testing.T) {
+		var empty *replicaPool
+		assert.Nil(t, empty.pick())
+	})
+}
+
+func TestRepository_ReadDB(t *testing.T) {
+	t.Parallel()
+
+	primary, replicaDB := &sqlx.DB{}, &sqlx.DB{}
+	pool := &replicaPool{replicas: []*replica{{db: replicaDB}}}
+	pool.replicas[0].healthy.Store(true)
+
+	now := time.Now()
+	pins := newPrimaryPins(time.Second)
+	pins.now = func() time.Time { return now }
+
+	repo := &Repository{connection: primary, replicas: pool, pins: pins}
+	writer := context.WithValue(context.Background(), config.KeyUUID, "writer")
+	reader := context.WithValue(context.Background(), config.KeyUUID, "reader")
+
+	assert.Same(t, replicaDB, repo.readDB(writer))
+
+	pins.pin(writer)
+	assert.Same(t, primary, repo.readDB(writer))
+	assert.Same(t, replicaDB, repo.readDB(reader))
+
+	now = now.Add(2 * time.Second)
+	assert.Same(t, replicaDB, repo.readDB(writer))
+
+	txRepo := &Repository{connection: primary, tx: &sqlx.Tx{}, replicas: pool, pins: pins}
+	assert.Same(t, txRepo.tx, txRepo.readDB(reader))
+}
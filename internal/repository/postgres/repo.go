@@ -10,106 +10,261 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	society "github.com/s21platform/society-proto/society-proto"
 	"github.com/s21platform/society-service/internal/config"
 	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/service"
 )
 
 type Repository struct {
 	connection *sqlx.DB
+	tx         *sqlx.Tx
+	replicas   *replicaPool
+	pins       *primaryPins
+	// migrationDSN — подключение для Migrate, без statement_timeout
+	migrationDSN string
+
+	txMaxRetries   int
+	txRetryBackoff time.Duration
 }
 
 func connect(cfg *config.Config) (*Repository, error) {
-	conStr := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable",
-		cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Database, cfg.Postgres.Host, cfg.Postgres.Port)
-
-	db, err := sqlx.Connect("postgres", conStr)
+	db, err := sqlx.Connect("postgres", dsn(cfg.Postgres))
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open: %w", err)
 	}
+	configurePool(db, cfg.Postgres)
 
 	return &Repository{
-		connection: db,
+		connection:     db,
+		migrationDSN:   migrationDSN(cfg.Postgres),
+		txMaxRetries:   cfg.Postgres.TxMaxRetries,
+		txRetryBackoff: cfg.Postgres.TxRetryBackoff,
 	}, nil
 }
 
-func (r *Repository) Conn() *sqlx.DB {
-	return r.connection
-}
-
 func New(cfg *config.Config) (*Repository, error) {
 	var err error
 	var repo *Repository
 
-	for i := 0; i < 5; i++ {
+	attempts := max(cfg.Postgres.ConnectAttempts, 1)
+	for i := 0; i < attempts; i++ {
 		repo, err = connect(cfg)
 		if err == nil {
+			if len(cfg.Postgres.ReplicaDSNs) > 0 {
+				repo.replicas = newReplicaPool(cfg.Postgres, cfg.Postgres.ReplicaDSNs, cfg.Postgres.ReplicaHealthInterval)
+				repo.pins = newPrimaryPins(cfg.Postgres.ReadYourWritesWindow)
+			}
 			return repo, nil
 		}
 
 		log.Println(err)
-		time.Sleep(500 * time.Millisecond)
+		if i < attempts-1 {
+			time.Sleep(backoff(cfg.Postgres.ConnectInitialBackoff, cfg.Postgres.ConnectMaxBackoff, i))
+		}
 	}
 
 	return nil, err
 }
 
 func (r *Repository) Close() {
+	r.replicas.Close()
 	r.connection.Close()
 }
 
-func (r *Repository) CreateSociety(ctx context.Context, socData *model.SocietyData) (string, error) {
-	tx, err := r.connection.Beginx()
-	if err != nil {
-		return "", fmt.Errorf("failed to start transaction: %w", err)
+// db возвращает транзакцию, если репозиторий открыт внутри WithTx, иначе подключение
+func (r *Repository) db() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
 	}
+	return r.connection
+}
 
-	var societyUUID string
+// readDB отправляет чтение на реплику, если это не транзакция и пользователь недавно ничего не писал
+func (r *Repository) readDB(ctx context.Context) sqlx.ExtContext {
+	if r.tx != nil || r.pins.isPinned(ctx) {
+		return r.db()
+	}
+	if replica := r.replicas.pick(); replica != nil {
+		return replica
+	}
+	return r.connection
+}
 
-	query, args, err := sq.Insert("society").
-		Columns("name", "owner_uuid", "format_id", "post_permission_id", "is_search").
-		Values(socData.Name, socData.OwnerUUID, socData.FormatID, socData.PostPermission, socData.IsSearch).
-		Suffix("RETURNING id").
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
-	if err != nil {
-		_ = tx.Rollback()
-		return "", fmt.Errorf("failed to build society insert query: %w", err)
+// WithTx выполняет fn в одной транзакции: коммит при успехе, откат при ошибке.
+// Вложенный вызов переиспользует уже открытую транзакцию.
+func (r *Repository) WithTx(ctx context.Context, fn func(repo service.DbRepo) error) error {
+	return r.withTx(ctx, func(txRepo *Repository) error {
+		return fn(txRepo)
+	})
+}
+
+// withTx повторяет транзакцию целиком при serialization failure и deadlock,
+// поэтому fn не должна иметь побочных эффектов вне базы
+func (r *Repository) withTx(ctx context.Context, fn func(txRepo *Repository) error) error {
+	if r.tx != nil {
+		return fn(r)
 	}
 
-	err = tx.GetContext(ctx, &societyUUID, query, args...)
-	if err != nil {
-		_ = tx.Rollback()
-		return "", fmt.Errorf("failed to insert society: %w", err)
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = r.runTx(ctx, fn)
+		if err == nil || attempt >= r.txMaxRetries || !isRetryableTxError(err) {
+			return err
+		}
+
+		log.Printf("retrying transaction after %v", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff(r.txRetryBackoff, 0, attempt)):
+		}
 	}
+}
 
-	query, args, err = sq.Insert("society_members").
-		Columns("society_id", "user_uuid", "role", "payment_status").
-		Values(societyUUID, socData.OwnerUUID, "1", "1").
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+func (r *Repository) runTx(ctx context.Context, fn func(txRepo *Repository) error) error {
+	tx, err := r.connection.BeginTxx(ctx, nil)
 	if err != nil {
-		_ = tx.Rollback()
-		return "", fmt.Errorf("failed to build society_members insert query: %w", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, query, args...)
+	err = fn(&Repository{connection: r.connection, tx: tx, replicas: r.replicas, pins: r.pins})
 	if err != nil {
 		_ = tx.Rollback()
-		return "", fmt.Errorf("failed to insert society member: %w", err)
+		return err
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return "", fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.pins.pin(ctx)
+
+	return nil
+}
+
+func (r *Repository) CreateSociety(ctx context.Context, socData *model.SocietyData) (string, error) {
+	var societyUUID string
+
+	err := r.withMemberEvent(ctx, model.MemberEventJoin, func(txRepo *Repository) error {
+		query, args, err := sq.Insert("society").
+			Columns("name", "owner_uuid", "format_id", "post_permission_id", "is_search").
+			Values(socData.Name, socData.OwnerUUID, socData.FormatID, socData.PostPermission, socData.IsSearch).
+			Suffix("RETURNING id").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society insert query: %w", err)
+		}
+
+		err = sqlx.GetContext(ctx, txRepo.db(), &societyUUID, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society: %w", err)
+		}
+
+		query, args, err = sq.Insert("society_members").
+			Columns("society_id", "user_uuid", "role", "payment_status").
+			Values(societyUUID, socData.OwnerUUID, "1", "1").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_members insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society member: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return societyUUID, nil
 }
 
+// GetSocietyInfo возвращает сообщество с активными тегами, без данных о пользователе и числа участников
 func (r *Repository) GetSocietyInfo(ctx context.Context, societyUUID string) (*model.SocietyInfo, error) {
-	var societyInfo model.SocietyInfo
+	query, args, err := sq.Select(
+		"s.name",
+		"s.description",
+		"s.owner_uuid",
+		"s.photo_url",
+		"s.format_id",
+		"s.post_permission_id",
+		"s.is_search",
+		"tags.ids AS tags_id",
+		"COALESCE(s.max_members, 0) AS max_members",
+	).
+		From("society s").
+		JoinClause(societyTagsLateral).
+		Where(sq.Eq{"s.id": societyUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var row societyInfoRow
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &row, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get society info: %w", err)
+	}
+
+	societyInfo := row.SocietyInfo
+	societyInfo.TagsID = row.TagsID
+	societyInfo.MaxMembers = row.MaxMembers
+
+	return &societyInfo, nil
+}
 
+const societyTagsLateral = "CROSS JOIN LATERAL (SELECT COALESCE(array_agg(t.tag_id ORDER BY t.tag_id), '{}') AS ids FROM society_has_tags t WHERE t.society_id = s.id AND t.is_active) tags"
+
+type societyInfoRow struct {
+	model.SocietyInfo
+	peerStateRow
+	CountSubscribe int64         `db:"count_subscribe"`
+	TagsID         pq.Int64Array `db:"tags_id"`
+	MaxMembers     int64         `db:"max_members"`
+}
+
+type peerStateRow struct {
+	model.SocietyPeerState
+	Announcements announcementsJSON `db:"announcements"`
+}
+
+func (r peerStateRow) state() model.SocietyPeerState {
+	state := r.SocietyPeerState
+	state.Announcements = r.Announcements
+	return state
+}
+
+// peerStateColumns — состояние пользователя peer_uuid.id в сообществе societyID и его активные объявления;
+// запрос должен присоединить их через joinPeerState
+func peerStateColumns(societyID string) []string {
+	return []string{
+		"COALESCE(peer.role, 0) AS role",
+		editorRoleExpr("COALESCE(peer.role, 0)", societyID, "peer_uuid.id") + " AS editor_role",
+		"EXISTS (SELECT 1 FROM members_requests mr WHERE mr.society_id = " + societyID + " AND mr.user_uuid = peer_uuid.id AND mr.status_id = 1) AS is_pending",
+		"CASE WHEN wait.id IS NULL THEN 0 ELSE (SELECT COUNT(*) FROM society_waitlist w WHERE w.society_id = " + societyID + " AND w.id <= wait.id) END AS waitlist_position",
+		"announcements.items AS announcements",
+	}
+}
+
+func joinPeerState(query sq.SelectBuilder, societyID string) sq.SelectBuilder {
+	return query.
+		LeftJoin("society_members peer ON peer.society_id = " + societyID + " AND peer.user_uuid = peer_uuid.id").
+		LeftJoin("society_waitlist wait ON wait.society_id = " + societyID + " AND wait.user_uuid = peer_uuid.id").
+		JoinClause(activeAnnouncementsLateral(societyID))
+}
+
+// GetSocietyInfoForPeer за один запрос возвращает сообщество, число участников, активные теги и объявления
+// и состояние запрашивающего пользователя: роль (в том числе унаследованную модерацию), наличие заявки
+// и место в очереди на вступление
+func (r *Repository) GetSocietyInfoForPeer(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyInfo, error) {
 	query := sq.Select(
 		"s.name",
 		"s.description",
@@ -118,32 +273,69 @@ func (r *Repository) GetSocietyInfo(ctx context.Context, societyUUID string) (*m
 		"s.format_id",
 		"s.post_permission_id",
 		"s.is_search",
+		"s.member_count AS count_subscribe",
+		"tags.ids AS tags_id",
+		"COALESCE(s.max_members, 0) AS max_members",
 	).
+		Columns(peerStateColumns("s.id")...).
 		From("society s").
+		JoinClause("CROSS JOIN (SELECT ?::uuid AS id) peer_uuid", peerUUID).
+		JoinClause(societyTagsLateral)
+	sqlString, args, err := joinPeerState(query, "s.id").
 		Where(sq.Eq{"s.id": societyUUID}).
-		PlaceholderFormat(sq.Dollar)
-
-	sqlString, args, err := query.ToSql()
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	err = r.connection.GetContext(ctx, &societyInfo, sqlString, args...)
+	var row societyInfoRow
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &row, sqlString, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get society info: %w", err)
+		return nil, fmt.Errorf("failed to execute query GetSocietyInfoForPeer: %w", err)
 	}
+
+	societyInfo := row.SocietyInfo
+	societyInfo.CountSubscribe = row.CountSubscribe
+	societyInfo.TagsID = row.TagsID
+	societyInfo.MaxMembers = row.MaxMembers
+	societyInfo.SetPeerState(row.state())
+
 	return &societyInfo, nil
 }
 
+// GetSocietyPeerState возвращает то же состояние пользователя и объявления, что и GetSocietyInfoForPeer,
+// без данных самого сообщества; существование сообщества не проверяет
+func (r *Repository) GetSocietyPeerState(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyPeerState, error) {
+	query := sq.Select(peerStateColumns("soc.id")...).
+		FromSelect(sq.Select().Column("?::uuid AS id", societyUUID), "soc").
+		JoinClause("CROSS JOIN (SELECT ?::uuid AS id) peer_uuid", peerUUID)
+	sqlString, args, err := joinPeerState(query, "soc.id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var row peerStateRow
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &row, sqlString, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyPeerState: %w", err)
+	}
+
+	state := row.state()
+	return &state, nil
+}
+
 func (r *Repository) GetTags(ctx context.Context, societyUUID string) ([]int64, error) {
-	query := sq.Select("tag_id").From("society_has_tags").Where(sq.Eq{"society_id": societyUUID})
+	query := sq.Select("tag_id").From("society_has_tags").Where(sq.Eq{"society_id": societyUUID, "is_active": true}).OrderBy("tag_id")
 	sqlString, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL query GetTags: %w", err)
 	}
 
 	var tags []int64
-	err = r.connection.SelectContext(ctx, &tags, sqlString, args...)
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &tags, sqlString, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query GetTags: %w", err)
 	}
@@ -151,14 +343,14 @@ func (r *Repository) GetTags(ctx context.Context, societyUUID string) ([]int64,
 }
 
 func (r *Repository) CountSubscribe(ctx context.Context, societyUUID string) (int64, error) {
-	query := sq.Select("count(*)").From("society_members").Where(sq.Eq{"society_id": societyUUID})
+	query := sq.Select("member_count").From("society").Where(sq.Eq{"id": societyUUID})
 	sqlString, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("failed to build SQL query CountSubscribe: %w", err)
 	}
 
 	var count int64
-	err = r.connection.GetContext(ctx, &count, sqlString, args...)
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &count, sqlString, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute query CountSubscribe: %w", err)
 	}
@@ -166,6 +358,72 @@ func (r *Repository) CountSubscribe(ctx context.Context, societyUUID string) (in
 	return count, nil
 }
 
+func (r *Repository) GetMemberCounters(ctx context.Context, societyUUID string) ([]model.MemberCounter, error) {
+	query, args, err := sq.Select("role", "payment_status", "count").
+		From("society_member_counters").
+		Where(sq.Eq{"society_id": societyUUID}).
+		Where(sq.Gt{"count": 0}).
+		OrderBy("role", "payment_status").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var counters []model.MemberCounter
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &counters, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetMemberCounters: %w", err)
+	}
+
+	return counters, nil
+}
+
+// дни без событий тоже попадают в выборку, чтобы график роста был непрерывным
+const membershipGrowthQuery = `
+SELECT d::date AS day,
+       count(e.id) FILTER (WHERE e.event_type = 'join') AS joins,
+       count(e.id) FILTER (WHERE e.event_type IN ('leave', 'kick')) AS leaves
+FROM generate_series($2::date, now()::date, interval '1 day') d
+LEFT JOIN society_membership_events e
+    ON e.society_id = $1 AND e.created_at >= d AND e.created_at < d + interval '1 day'
+GROUP BY d
+ORDER BY d`
+
+func (r *Repository) GetMembershipGrowth(ctx context.Context, societyUUID string, since time.Time) ([]model.DailyMembership, error) {
+	var growth []model.DailyMembership
+	err := sqlx.SelectContext(ctx, r.readDB(ctx), &growth, membershipGrowthQuery, societyUUID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetMembershipGrowth: %w", err)
+	}
+
+	return growth, nil
+}
+
+func (r *Repository) GetRequestStats(ctx context.Context, societyUUID string) (*model.RequestStats, error) {
+	query, args, err := sq.Select(
+		"count(*) FILTER (WHERE status_id = 1) AS pending",
+		"count(*) FILTER (WHERE status_id = 2) AS approved",
+		"count(*) FILTER (WHERE status_id = 3) AS rejected",
+		"COALESCE(avg(EXTRACT(EPOCH FROM update_at - create_at)) FILTER (WHERE status_id = 2), 0) AS avg_approval_seconds",
+	).
+		From("members_requests").
+		Where(sq.Eq{"society_id": societyUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var stats model.RequestStats
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &stats, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetRequestStats: %w", err)
+	}
+
+	return &stats, nil
+}
+
 func (r *Repository) UpdateSociety(ctx context.Context, societyData *society.UpdateSocietyIn) error {
 	query := sq.Update("society").
 		Set("name", societyData.Name).
@@ -181,14 +439,17 @@ func (r *Repository) UpdateSociety(ctx context.Context, societyData *society.Upd
 		return fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	_, err = r.connection.ExecContext(ctx, sql, args...)
+	_, err = r.db().ExecContext(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update society: %w", err)
 	}
 
+	r.pins.pin(ctx)
 	return nil
 }
 
+// IsOwnerAdminModerator возвращает роль участника; владельцы и администраторы предков,
+// от которых сообщество наследует модерацию, получают роль модератора (3), если своя роль ниже
 func (r *Repository) IsOwnerAdminModerator(ctx context.Context, peerUUID, societyUUID string) (int, error) {
 	query, args, err := sq.Select("role").
 		From("society_members").
@@ -201,18 +462,26 @@ func (r *Repository) IsOwnerAdminModerator(ctx context.Context, peerUUID, societ
 
 	var result model.Role
 
-	err = sqlx.GetContext(ctx, r.connection, &result, query, args...)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return 0, nil
-		}
+	err = sqlx.GetContext(ctx, r.db(), &result, query, args...)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return 0, fmt.Errorf("failed to execute query isOwnerAdminModerator: %w", err)
 	}
+	if result.Role >= 1 && result.Role <= 3 {
+		return result.Role, nil
+	}
+
+	inherited, err := r.hasInheritedModeration(ctx, peerUUID, societyUUID)
+	if err != nil {
+		return 0, err
+	}
+	if inherited {
+		return 3, nil
+	}
 
 	return result.Role, nil
 }
 
-func (r *Repository) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (r *Repository) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string) error {
 	query, args, err := sq.Update("society_has_tags").
 		Set("is_active", false).
 		Where(sq.Eq{"society_id": societyUUID}).
@@ -222,7 +491,7 @@ func (r *Repository) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID
 		return fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, query, args...)
+	_, err = r.db().ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query RemoveSocietyHasTagsEntry: %w", err)
 	}
@@ -230,7 +499,7 @@ func (r *Repository) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID
 	return nil
 }
 
-func (r *Repository) RemoveMembersRequestEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (r *Repository) RemoveMembersRequestEntry(ctx context.Context, societyUUID string) error {
 	query, args, err := sq.Delete("members_requests").
 		Where(sq.Eq{"society_id": societyUUID}).
 		PlaceholderFormat(sq.Dollar).
@@ -239,7 +508,7 @@ func (r *Repository) RemoveMembersRequestEntry(ctx context.Context, societyUUID
 		return fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, query, args...)
+	_, err = r.db().ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to execute query RemoveMembersRequestEntry: %w", err)
 	}
@@ -247,7 +516,7 @@ func (r *Repository) RemoveMembersRequestEntry(ctx context.Context, societyUUID
 	return nil
 }
 
-func (r *Repository) RemoveSocietyMembersEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (r *Repository) RemoveSocietyMembersEntry(ctx context.Context, societyUUID string) error {
 	query, args, err := sq.Delete("society_members").
 		Where("society_id = ?", societyUUID).
 		PlaceholderFormat(sq.Dollar).
@@ -256,15 +525,17 @@ func (r *Repository) RemoveSocietyMembersEntry(ctx context.Context, societyUUID
 		return fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to execute query RemoveMembersRequestEntry: %w", err)
-	}
-
-	return nil
+	// участников удаляет владелец вместе с сообществом, в истории это kick
+	return r.withMemberEvent(ctx, model.MemberEventKick, func(txRepo *Repository) error {
+		_, err := txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to execute query RemoveSocietyMembersEntry: %w", err)
+		}
+		return nil
+	})
 }
 
-func (r *Repository) RemoveSociety(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (r *Repository) RemoveSociety(ctx context.Context, societyUUID string) error {
 	query, args, err := sq.Delete("society").
 		Where("id = ?", societyUUID).
 		PlaceholderFormat(sq.Dollar).
@@ -273,9 +544,9 @@ func (r *Repository) RemoveSociety(ctx context.Context, societyUUID string, tx *
 		return fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	_, err = tx.ExecContext(ctx, query, args...)
+	_, err = r.db().ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to execute query RemoveMembersRequestEntry: %w", err)
+		return fmt.Errorf("failed to execute query RemoveSociety: %w", err)
 	}
 
 	return nil
@@ -292,7 +563,7 @@ func (r *Repository) GetOwner(ctx context.Context, societyId string) (string, er
 	}
 
 	var owner string
-	err = sqlx.GetContext(ctx, r.connection, &owner, query, args...)
+	err = sqlx.GetContext(ctx, r.db(), &owner, query, args...)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute query GetOwner: %w", err)
 	}
@@ -310,47 +581,46 @@ func (r *Repository) GetFormatSociety(ctx context.Context, societyUUID string) (
 		return 0, fmt.Errorf("failed to build SQL query: %w", err)
 	}
 	var role int
-	err = sqlx.GetContext(ctx, r.connection, &role, query, args...)
+	err = sqlx.GetContext(ctx, r.db(), &role, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute query GetFormatSociety: %w", err)
 	}
 	return role, nil
 }
 
-func (r *Repository) AddMembersRequests(ctx context.Context, uuid string, societyUUID string) error {
-	query, args, err := sq.Insert("members_requests").
-		Columns("user_uuid", "society_id", "status_id").
-		Values(uuid, societyUUID, 1).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build add_members_requests insert query: %w", err)
-	}
-
-	_, err = r.connection.ExecContext(ctx, query, args...)
+func (r *Repository) AddMembersRequests(ctx context.Context, request *model.MembersRequest) error {
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		member, err := txRepo.isMember(ctx, request.UserUUID, request.SocietyUUID)
+		if err != nil {
+			return err
+		}
+		if member {
+			return model.ErrAlreadyMember
+		}
+		return txRepo.insertMembersRequest(ctx, request, 1, nil)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to insert add_members_requests: %w", err)
+		return err
 	}
 
+	r.pins.pin(ctx)
 	return nil
 }
 
-func (r *Repository) AddSocietyMembers(ctx context.Context, uuid string, societyUUID string) error {
-	query, args, err := sq.Insert("society_members").
-		Columns("society_id", "user_uuid", "role").
-		Values(societyUUID, uuid, 1).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build add_society_members insert query: %w", err)
-	}
-
-	_, err = r.connection.ExecContext(ctx, query, args...)
+// AddSocietyMembers добавляет участника; true — сообщество заполнено и пользователь встал в очередь
+func (r *Repository) AddSocietyMembers(ctx context.Context, uuid string, societyUUID string) (bool, error) {
+	var waitlisted bool
+	err := r.withMemberEvent(ctx, model.MemberEventJoin, func(txRepo *Repository) error {
+		var err error
+		waitlisted, err = txRepo.joinOrWaitlist(ctx, uuid, societyUUID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to insert add_society_members: %w", err)
+		return false, err
 	}
 
-	return nil
+	r.pins.pin(ctx)
+	return waitlisted, nil
 }
 
 func (r *Repository) GetRoleSocietyMembers(ctx context.Context, uuid string, societyUUID string) (int, error) {
@@ -364,7 +634,7 @@ func (r *Repository) GetRoleSocietyMembers(ctx context.Context, uuid string, soc
 	}
 
 	var role int
-	err = sqlx.GetContext(ctx, r.connection, &role, query, args...)
+	err = sqlx.GetContext(ctx, r.db(), &role, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute query GetRoleSocietyMembers: %w", err)
 	}
@@ -381,32 +651,73 @@ func (r *Repository) UnSubscribeToSociety(ctx context.Context, uuid string, soci
 		return fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	_, err = r.connection.ExecContext(ctx, query, args...)
+	err = r.withMemberEvent(ctx, model.MemberEventLeave, func(txRepo *Repository) error {
+		if _, err := txRepo.db().ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+		return txRepo.fillFreeSeats(ctx, societyUUID)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to execute query UnSubscribeToSociety: %w", err)
 	}
+	r.pins.pin(ctx)
 	return nil
 }
 
-func (r *Repository) GetUserSocieties(ctx context.Context, limit uint64, offset uint64, userUUID string) ([]string, error) {
-	query, args, err := sq.Select("society_id").
-		From("society_members").
-		Where(sq.Eq{"user_uuid": userUUID}).
-		Limit(limit).
-		Offset(offset).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+func (r *Repository) GetUserSocieties(ctx context.Context, filter *model.UserSocietiesFilter) ([]model.SocietyWithOffsetData, error) {
+	query := sq.Select(
+		"s.id",
+		"s.name",
+		"s.photo_url",
+		"s.format_id",
+		"sm.id AS member_id",
+		"caller.id IS NOT NULL AS is_member",
+	).
+		From("society_members sm").
+		Join("society s ON s.id = sm.society_id").
+		LeftJoin("society_members caller ON caller.society_id = sm.society_id AND caller.user_uuid = ?", filter.CallerUUID).
+		Where(sq.Eq{"sm.user_uuid": filter.UserUUID}).
+		OrderBy("sm.id DESC").
+		Limit(filter.Limit)
+
+	if filter.AfterID > 0 {
+		query = query.Where(sq.Lt{"sm.id": filter.AfterID})
+	} else {
+		query = query.Offset(filter.Offset)
+	}
+
+	sqlString, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL query: %w", err)
 	}
 
-	var groups []string
-	err = r.connection.SelectContext(ctx, &groups, query, args...)
+	var data []model.SocietyWithOffsetData
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &data, sqlString, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query GetUserSocieties: %w", err)
 	}
 
-	return groups, nil
+	return data, nil
+}
+
+func (r *Repository) CountUserSocieties(ctx context.Context, userUUID string) (int64, error) {
+	query, args, err := sq.Select("count(*)").
+		From("society_members sm").
+		Join("society s ON s.id = sm.society_id").
+		Where(sq.Eq{"sm.user_uuid": userUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var count int64
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &count, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query CountUserSocieties: %w", err)
+	}
+
+	return count, nil
 }
 
 func (r *Repository) GetInfoSociety(ctx context.Context, groups []string) ([]model.SocietyWithOffsetData, error) {
@@ -420,10 +731,82 @@ func (r *Repository) GetInfoSociety(ctx context.Context, groups []string) ([]mod
 	}
 
 	var data []model.SocietyWithOffsetData
-	err = r.connection.SelectContext(ctx, &data, query, args...)
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &data, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query GetInfoSociety: %w", err)
 	}
 
 	return data, nil
 }
+
+func (r *Repository) GetSocietiesByIDs(ctx context.Context, societyUUIDs []string, callerUUID string) ([]model.SocietySummary, error) {
+	query, args, err := sq.Select(
+		"s.id",
+		"s.name",
+		"s.photo_url",
+		"s.format_id",
+		"s.member_count AS count_subscribe",
+		"COALESCE(caller.role, 0) AS role",
+		"COALESCE(parent.id::text, '') AS parent_id",
+		"COALESCE(parent.name, '') AS parent_name",
+	).
+		From("society s").
+		LeftJoin("society_members caller ON caller.society_id = s.id AND caller.user_uuid = ?", callerUUID).
+		LeftJoin("society parent ON parent.id = s.parent_id").
+		Where(sq.Eq{"s.id": societyUUIDs}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var data []model.SocietySummary
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &data, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietiesByIDs: %w", err)
+	}
+
+	return data, nil
+}
+
+func (r *Repository) IsFormatExists(ctx context.Context, formatID int64) (bool, error) {
+	query, args, err := sq.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("format_society").
+		Where(sq.Eq{"id": formatID}).
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var exists bool
+	err = sqlx.GetContext(ctx, r.db(), &exists, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query IsFormatExists: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *Repository) IsPostPermissionExists(ctx context.Context, postPermissionID int64) (bool, error) {
+	query, args, err := sq.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("post_permission").
+		Where(sq.Eq{"id": postPermissionID}).
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var exists bool
+	err = sqlx.GetContext(ctx, r.db(), &exists, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query IsPostPermissionExists: %w", err)
+	}
+
+	return exists, nil
+}
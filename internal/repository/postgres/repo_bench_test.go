@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/distribution/uuid"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// Бенчмарки требуют живой Postgres с накатанными миграциями:
+// переменные окружения SOCIETY_SERVICE_POSTGRES_* как у сервиса.
+
+func newBenchRepository(b *testing.B) (*Repository, string, string) {
+	b.Helper()
+
+	cfg := config.MustLoad()
+	if cfg.Postgres.Host == "" {
+		b.Skip("SOCIETY_SERVICE_POSTGRES_HOST is not set")
+	}
+
+	repo, err := New(cfg)
+	if err != nil {
+		b.Fatalf("failed to connect: %v", err)
+	}
+	b.Cleanup(repo.Close)
+
+	ctx := context.Background()
+	ownerUUID := uuid.Generate().String()
+	societyUUID, err := repo.CreateSociety(ctx, &model.SocietyData{
+		Name:           "bench society",
+		FormatID:       1,
+		PostPermission: 1,
+		OwnerUUID:      ownerUUID,
+	})
+	if err != nil {
+		b.Fatalf("failed to create society: %v", err)
+	}
+	b.Cleanup(func() {
+		_ = repo.withTx(ctx, func(txRepo *Repository) error {
+			_ = txRepo.RemoveSocietyHasTagsEntry(ctx, societyUUID)
+			_ = txRepo.RemoveMembersRequestEntry(ctx, societyUUID)
+			_ = txRepo.RemoveSocietyMembersEntry(ctx, societyUUID)
+			return txRepo.RemoveSociety(ctx, societyUUID)
+		})
+	})
+
+	return repo, societyUUID, ownerUUID
+}
+
+// BenchmarkGetSocietyInfo_Sequential повторяет прежний путь хендлера: четыре запроса подряд
+func BenchmarkGetSocietyInfo_Sequential(b *testing.B) {
+	repo, societyUUID, ownerUUID := newBenchRepository(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetSocietyInfo(ctx, societyUUID); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.CountSubscribe(ctx, societyUUID); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.GetTags(ctx, societyUUID); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.IsOwnerAdminModerator(ctx, ownerUUID, societyUUID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetSocietyInfo_SingleQuery — нынешний путь хендлера: унаследованная модерация
+// и активные объявления приходят тем же запросом
+func BenchmarkGetSocietyInfo_SingleQuery(b *testing.B) {
+	repo, societyUUID, ownerUUID := newBenchRepository(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetSocietyInfoForPeer(ctx, societyUUID, ownerUUID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
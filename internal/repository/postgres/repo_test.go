@@ -0,0 +1,771 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	society "github.com/s21platform/society-proto/society-proto"
+
+	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/repository/repotest"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+func TestRepository_CreateSociety(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		data    *model.SocietyData
+		wantErr bool
+	}{
+		{
+			name: "open society",
+			data: &model.SocietyData{Name: "open", FormatID: 1, PostPermission: 2, IsSearch: true, OwnerUUID: newUUID()},
+		},
+		{
+			name: "paid society",
+			data: &model.SocietyData{Name: "paid", FormatID: 3, PostPermission: 4, OwnerUUID: newUUID()},
+		},
+		{
+			name:    "unknown format",
+			data:    &model.SocietyData{Name: "broken", FormatID: 99, PostPermission: 1, OwnerUUID: newUUID()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			societyUUID, err := repo.CreateSociety(ctx, tt.data)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			info, err := repo.GetSocietyInfo(ctx, societyUUID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.data.Name, info.Name)
+			assert.Equal(t, tt.data.OwnerUUID, info.OwnerUUID)
+			assert.Equal(t, tt.data.FormatID, info.FormatID)
+			assert.Equal(t, tt.data.PostPermission, info.PostPermission)
+			assert.Equal(t, tt.data.IsSearch, info.IsSearch)
+			assert.NotEmpty(t, info.PhotoURL)
+
+			owner, err := repo.GetOwner(ctx, societyUUID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.data.OwnerUUID, owner)
+
+			format, err := repo.GetFormatSociety(ctx, societyUUID)
+			require.NoError(t, err)
+			assert.Equal(t, int(tt.data.FormatID), format)
+
+			role, err := repo.GetRoleSocietyMembers(ctx, tt.data.OwnerUUID, societyUUID)
+			require.NoError(t, err)
+			assert.Equal(t, 1, role)
+
+			count, err := repo.CountSubscribe(ctx, societyUUID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+		})
+	}
+}
+
+func TestRepository_GetSocietyInfoForPeer(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	ownerUUID, memberUUID, pendingUUID := newUUID(), newUUID(), newUUID()
+	societyUUID := createTestSociety(t, repo, ownerUUID)
+	insertTestMember(t, repo, societyUUID, memberUUID, 4, 1)
+	require.NoError(t, repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: pendingUUID, SocietyUUID: societyUUID}))
+	insertTestTag(t, repo, societyUUID, 7, true)
+	insertTestTag(t, repo, societyUUID, 3, true)
+	insertTestTag(t, repo, societyUUID, 5, false)
+
+	tests := []struct {
+		name        string
+		peerUUID    string
+		wantRole    int
+		wantMember  bool
+		wantPending bool
+	}{
+		{name: "owner", peerUUID: ownerUUID, wantRole: 1, wantMember: true},
+		{name: "member", peerUUID: memberUUID, wantRole: 4, wantMember: true},
+		{name: "pending request", peerUUID: pendingUUID, wantPending: true},
+		{name: "stranger", peerUUID: newUUID()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := repo.GetSocietyInfoForPeer(ctx, societyUUID, tt.peerUUID)
+			require.NoError(t, err)
+			assert.Equal(t, "society", info.Name)
+			assert.Equal(t, ownerUUID, info.OwnerUUID)
+			assert.Equal(t, int64(2), info.CountSubscribe)
+			assert.Equal(t, []int64{3, 7}, info.TagsID)
+			assert.Equal(t, tt.wantRole, info.PeerRole)
+			assert.Equal(t, tt.wantMember, info.IsMember)
+			assert.Equal(t, tt.wantPending, info.IsPending)
+		})
+	}
+
+	t.Run("unknown society", func(t *testing.T) {
+		_, err := repo.GetSocietyInfoForPeer(ctx, newUUID(), ownerUUID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestRepository_GetTags(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	insertTestTag(t, repo, societyUUID, 9, true)
+	insertTestTag(t, repo, societyUUID, 2, true)
+	insertTestTag(t, repo, societyUUID, 4, false)
+
+	tags, err := repo.GetTags(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 9}, tags)
+
+	require.NoError(t, repo.RemoveSocietyHasTagsEntry(ctx, societyUUID))
+	tags, err = repo.GetTags(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
+
+func TestRepository_UpdateSociety(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+
+	err := repo.UpdateSociety(ctx, &society.UpdateSocietyIn{
+		SocietyUUID:    societyUUID,
+		Name:           "renamed",
+		Description:    "about",
+		FormatID:       2,
+		PostPermission: 3,
+		IsSearch:       true,
+	})
+	require.NoError(t, err)
+
+	info, err := repo.GetSocietyInfo(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", info.Name)
+	assert.Equal(t, sql.NullString{String: "about", Valid: true}, info.Description)
+	assert.Equal(t, int64(2), info.FormatID)
+	assert.Equal(t, int64(3), info.PostPermission)
+	assert.True(t, info.IsSearch)
+}
+
+func TestRepository_IsOwnerAdminModerator(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	ownerUUID, adminUUID, memberUUID := newUUID(), newUUID(), newUUID()
+	societyUUID := createTestSociety(t, repo, ownerUUID)
+	insertTestMember(t, repo, societyUUID, adminUUID, 2, 1)
+	insertTestMember(t, repo, societyUUID, memberUUID, 4, 1)
+
+	tests := []struct {
+		name     string
+		peerUUID string
+		want     int
+	}{
+		{name: "owner", peerUUID: ownerUUID, want: 1},
+		{name: "admin", peerUUID: adminUUID, want: 2},
+		{name: "member", peerUUID: memberUUID, want: 4},
+		{name: "not a member", peerUUID: newUUID(), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := repo.IsOwnerAdminModerator(ctx, tt.peerUUID, societyUUID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, role)
+		})
+	}
+}
+
+func TestRepository_Membership(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	userUUID := newUUID()
+
+	waitlisted, err := repo.AddSocietyMembers(ctx, userUUID, societyUUID)
+	require.NoError(t, err)
+	assert.False(t, waitlisted)
+	count, err := repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	role, err := repo.GetRoleSocietyMembers(ctx, userUUID, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, role)
+
+	require.NoError(t, repo.UnSubscribeToSociety(ctx, userUUID, societyUUID))
+	count, err = repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, err = repo.GetRoleSocietyMembers(ctx, userUUID, societyUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRepository_MembersRequests(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	userUUID := newUUID()
+
+	require.NoError(t, repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: userUUID, SocietyUUID: societyUUID}))
+	info, err := repo.GetSocietyInfoForPeer(ctx, societyUUID, userUUID)
+	require.NoError(t, err)
+	assert.True(t, info.IsPending)
+
+	require.NoError(t, repo.RemoveMembersRequestEntry(ctx, societyUUID))
+	info, err = repo.GetSocietyInfoForPeer(ctx, societyUUID, userUUID)
+	require.NoError(t, err)
+	assert.False(t, info.IsPending)
+
+	t.Run("unknown society", func(t *testing.T) {
+		assert.Error(t, repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: userUUID, SocietyUUID: newUUID()}))
+	})
+}
+
+func TestRepository_ExpirePendingRequests(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	staleUUID, freshUUID := newUUID(), newUUID()
+	require.NoError(t, repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: staleUUID, SocietyUUID: societyUUID}))
+	require.NoError(t, repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: freshUUID, SocietyUUID: societyUUID}))
+	_, err := repo.connection.ExecContext(ctx,
+		`UPDATE members_requests SET create_at = NOW() - INTERVAL '3 hours' WHERE user_uuid = $1`, staleUUID)
+	require.NoError(t, err)
+
+	expired, locked, err := repo.ExpirePendingRequests(ctx, 10)
+	require.NoError(t, err)
+	assert.True(t, locked)
+	assert.Empty(t, expired, "society without ttl never expires requests")
+
+	require.NoError(t, repo.SetRequestTTL(ctx, societyUUID, 2))
+	expired, locked, err = repo.ExpirePendingRequests(ctx, 10)
+	require.NoError(t, err)
+	assert.True(t, locked)
+	require.Len(t, expired, 1)
+	assert.Equal(t, staleUUID, expired[0].UserUUID)
+	assert.Equal(t, societyUUID, expired[0].SocietyUUID)
+
+	var statusID int
+	require.NoError(t, repo.connection.GetContext(ctx, &statusID,
+		`SELECT status_id FROM members_requests WHERE id = $1`, expired[0].ID))
+	assert.Equal(t, 4, statusID)
+
+	var eventType string
+	require.NoError(t, repo.connection.GetContext(ctx, &eventType,
+		`SELECT event_type FROM society_outbox WHERE user_uuid = $1 AND published_at IS NULL`, staleUUID))
+	assert.Equal(t, model.OutboxRequestExpired, eventType)
+
+	info, err := repo.GetSocietyInfoForPeer(ctx, societyUUID, freshUUID)
+	require.NoError(t, err)
+	assert.True(t, info.IsPending)
+
+	t.Run("lock held by another replica", func(t *testing.T) {
+		tx, err := repo.connection.BeginTxx(ctx, nil)
+		require.NoError(t, err)
+		defer func() { _ = tx.Rollback() }()
+		_, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, expireRequestsLockName)
+		require.NoError(t, err)
+
+		expired, locked, err := repo.ExpirePendingRequests(ctx, 10)
+		require.NoError(t, err)
+		assert.False(t, locked)
+		assert.Empty(t, expired)
+	})
+}
+
+func TestRepository_PublishOutbox(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	for _, userUUID := range []string{newUUID(), newUUID()} {
+		_, err := repo.connection.ExecContext(ctx,
+			`INSERT INTO society_outbox (event_type, society_id, user_uuid, payload) VALUES ($1, $2, $3, '{"request_id": 1}')`,
+			model.OutboxRequestExpired, societyUUID, userUUID)
+		require.NoError(t, err)
+	}
+
+	published, err := repo.PublishOutbox(ctx, 10, func(context.Context, []model.OutboxEvent) error {
+		return errors.New("webhook is down")
+	})
+	require.Error(t, err)
+	assert.Zero(t, published)
+
+	var sent []model.OutboxEvent
+	published, err = repo.PublishOutbox(ctx, 10, func(_ context.Context, events []model.OutboxEvent) error {
+		sent = append(sent, events...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, published, "failed publish leaves events for the next attempt")
+	require.Len(t, sent, 2)
+	assert.Less(t, sent[0].ID, sent[1].ID)
+	assert.Equal(t, model.OutboxRequestExpired, sent[0].EventType)
+	assert.JSONEq(t, `{"request_id": 1}`, string(sent[0].Payload))
+
+	published, err = repo.PublishOutbox(ctx, 10, func(context.Context, []model.OutboxEvent) error {
+		t.Fatal("published events are not sent again")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Zero(t, published)
+}
+
+func TestRepository_WaitlistConcurrentJoins(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	require.NoError(t, repo.SetMaxMembers(ctx, societyUUID, 3))
+
+	const joins = 10
+	var (
+		wg         sync.WaitGroup
+		waitlisted atomic.Int64
+	)
+	for i := 0; i < joins; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queued, err := repo.AddSocietyMembers(ctx, newUUID(), societyUUID)
+			assert.NoError(t, err)
+			if queued {
+				waitlisted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	count, err := repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, int64(joins-2), waitlisted.Load())
+
+	var queueLength int64
+	require.NoError(t, repo.connection.GetContext(ctx, &queueLength,
+		`SELECT COUNT(*) FROM society_waitlist WHERE society_id = $1`, societyUUID))
+	assert.Equal(t, int64(joins-2), queueLength)
+}
+
+func TestRepository_EventRSVPConcurrent(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	ownerUUID := newUUID()
+	societyUUID := createTestSociety(t, repo, ownerUUID)
+	start := time.Now().Add(time.Hour)
+	eventUUID, err := repo.CreateEvent(ctx, &model.SocietyEvent{
+		SocietyUUID: societyUUID,
+		AuthorUUID:  ownerUUID,
+		Title:       "meetup",
+		StartsAt:    start,
+		EndsAt:      start.Add(time.Hour),
+		Location:    "campus",
+		Capacity:    3,
+	})
+	require.NoError(t, err)
+
+	const rsvps = 10
+	users := make([]string, rsvps)
+	for i := range users {
+		users[i] = newUUID()
+		_, err := repo.AddSocietyMembers(ctx, users[i], societyUUID)
+		require.NoError(t, err)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		booked atomic.Int64
+	)
+	for _, userUUID := range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.SetEventRSVP(ctx, eventUUID, userUUID, true)
+			assert.NoError(t, err)
+			if ok {
+				booked.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	event, err := repo.GetEvent(ctx, eventUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), event.GoingCount)
+	assert.Equal(t, int64(3), booked.Load())
+}
+
+func TestRepository_VotePollConcurrent(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	ownerUUID := newUUID()
+	societyUUID := createTestSociety(t, repo, ownerUUID)
+	pollUUID, err := repo.CreatePoll(ctx, &model.Poll{
+		SocietyUUID:       societyUUID,
+		AuthorUUID:        ownerUUID,
+		Question:          "which day?",
+		IsMultiple:        true,
+		ResultsVisibility: model.PollResultsLive,
+		Options:           []model.PollOption{{Text: "friday"}, {Text: "saturday"}},
+	})
+	require.NoError(t, err)
+	poll, err := repo.GetPoll(ctx, pollUUID, ownerUUID)
+	require.NoError(t, err)
+	optionIDs := []int64{poll.Options[0].ID, poll.Options[1].ID}
+
+	const attempts = 10
+	var (
+		wg    sync.WaitGroup
+		voted atomic.Int64
+	)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.VotePoll(ctx, pollUUID, ownerUUID, optionIDs)
+			assert.NoError(t, err)
+			if ok {
+				voted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	poll, err = repo.GetPoll(ctx, pollUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), voted.Load())
+	assert.Equal(t, int64(1), poll.VoterCount)
+	assert.Equal(t, int64(1), poll.Options[0].VoteCount)
+	assert.Equal(t, int64(1), poll.Options[1].VoteCount)
+}
+
+func TestRepository_CreateChannelConcurrent(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	ownerUUID := newUUID()
+	societyUUID := createTestSociety(t, repo, ownerUUID)
+
+	const (
+		attempts    = 10
+		maxChannels = 3
+	)
+	var (
+		wg      sync.WaitGroup
+		created atomic.Int64
+	)
+	for i := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.CreateChannel(ctx, &model.Channel{
+				SocietyUUID:    societyUUID,
+				Name:           fmt.Sprintf("channel-%d", i),
+				FormatID:       1,
+				PostPermission: 1,
+			}, ownerUUID, maxChannels)
+			if err == nil {
+				created.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, model.ErrTooManyChannels)
+		}()
+	}
+	wg.Wait()
+
+	channels, err := repo.GetSocietyChannels(ctx, societyUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(maxChannels), created.Load())
+	assert.Len(t, channels, maxChannels)
+}
+
+func TestRepository_RemoveSociety(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	t.Run("members still reference society", func(t *testing.T) {
+		societyUUID := createTestSociety(t, repo, newUUID())
+
+		err := repo.RemoveSociety(ctx, societyUUID)
+		var pqErr *pq.Error
+		require.True(t, errors.As(err, &pqErr), "expected pq error, got %v", err)
+		assert.Equal(t, pq.ErrorCode("23503"), pqErr.Code)
+	})
+
+	t.Run("full removal in one transaction", func(t *testing.T) {
+		societyUUID := createTestSociety(t, repo, newUUID())
+		insertTestTag(t, repo, societyUUID, 1, true)
+		insertTestMember(t, repo, societyUUID, newUUID(), 4, 1)
+		require.NoError(t, repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: newUUID(), SocietyUUID: societyUUID}))
+
+		err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+			if err := txRepo.RemoveSocietyHasTagsEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			if err := txRepo.RemoveMembersRequestEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			if err := txRepo.RemoveSocietyMembersEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			return txRepo.RemoveSociety(ctx, societyUUID)
+		})
+		require.NoError(t, err)
+
+		_, err = repo.GetSocietyInfo(ctx, societyUUID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		var tags int
+		require.NoError(t, repo.connection.Get(&tags, `SELECT count(*) FROM society_has_tags WHERE society_id = $1`, societyUUID))
+		assert.Zero(t, tags)
+	})
+
+	t.Run("failed step rolls back the whole removal", func(t *testing.T) {
+		societyUUID := createTestSociety(t, repo, newUUID())
+		insertTestMember(t, repo, societyUUID, newUUID(), 4, 1)
+
+		// участники не удалены, поэтому удаление сообщества падает на FK
+		err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+			if err := txRepo.RemoveMembersRequestEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			return txRepo.RemoveSociety(ctx, societyUUID)
+		})
+		assert.Error(t, err)
+
+		count, err := repo.CountSubscribe(ctx, societyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestRepository_GetUserSocieties(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	userUUID, callerUUID := newUUID(), newUUID()
+	first := createTestSociety(t, repo, newUUID())
+	second := createTestSociety(t, repo, newUUID())
+	third := createTestSociety(t, repo, newUUID())
+	insertTestMember(t, repo, first, userUUID, 4, 1)
+	insertTestMember(t, repo, second, userUUID, 4, 1)
+	insertTestMember(t, repo, third, userUUID, 4, 1)
+	insertTestMember(t, repo, first, callerUUID, 4, 1)
+
+	all, err := repo.GetUserSocieties(ctx, &model.UserSocietiesFilter{UserUUID: userUUID, CallerUUID: callerUUID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	tests := []struct {
+		name   string
+		filter *model.UserSocietiesFilter
+		want   []string
+	}{
+		{
+			name:   "newest membership first",
+			filter: &model.UserSocietiesFilter{UserUUID: userUUID, CallerUUID: callerUUID, Limit: 2},
+			want:   []string{third, second},
+		},
+		{
+			name:   "offset",
+			filter: &model.UserSocietiesFilter{UserUUID: userUUID, CallerUUID: callerUUID, Limit: 2, Offset: 2},
+			want:   []string{first},
+		},
+		{
+			name:   "cursor",
+			filter: &model.UserSocietiesFilter{UserUUID: userUUID, CallerUUID: callerUUID, Limit: 2, AfterID: all[1].MemberID},
+			want:   []string{first},
+		},
+		{
+			name:   "no memberships",
+			filter: &model.UserSocietiesFilter{UserUUID: newUUID(), CallerUUID: callerUUID, Limit: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := repo.GetUserSocieties(ctx, tt.filter)
+			require.NoError(t, err)
+
+			var got []string
+			for _, d := range data {
+				got = append(got, d.SocietyUUID)
+				assert.Equal(t, d.SocietyUUID == first, d.IsMember)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	count, err := repo.CountUserSocieties(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestRepository_GetInfoSociety(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	first := createTestSociety(t, repo, newUUID())
+	second := createTestSociety(t, repo, newUUID())
+
+	data, err := repo.GetInfoSociety(ctx, []string{first, second, newUUID()})
+	require.NoError(t, err)
+
+	got := map[string]model.SocietyWithOffsetData{}
+	for _, d := range data {
+		got[d.SocietyUUID] = d
+	}
+	assert.Len(t, got, 2)
+	assert.Equal(t, "society", got[first].Name)
+	assert.Equal(t, int64(1), got[second].FormatId)
+}
+
+func TestRepository_GetSocietiesByIDs(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	callerUUID := newUUID()
+	owned := createTestSociety(t, repo, callerUUID)
+	joined := createTestSociety(t, repo, newUUID())
+	foreign := createTestSociety(t, repo, newUUID())
+	insertTestMember(t, repo, joined, callerUUID, 4, 1)
+
+	data, err := repo.GetSocietiesByIDs(ctx, []string{owned, joined, foreign, newUUID()}, callerUUID)
+	require.NoError(t, err)
+
+	got := map[string]model.SocietySummary{}
+	for _, d := range data {
+		got[d.SocietyUUID] = d
+	}
+	require.Len(t, got, 3)
+	assert.Equal(t, int64(1), got[owned].Role)
+	assert.Equal(t, int64(4), got[joined].Role)
+	assert.Equal(t, int64(0), got[foreign].Role)
+	assert.Equal(t, int64(2), got[joined].CountSubscribe)
+	assert.Equal(t, int64(1), got[foreign].CountSubscribe)
+}
+
+func TestRepository_DictionaryExists(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		check func(context.Context, int64) (bool, error)
+		id    int64
+		want  bool
+	}{
+		{name: "format open", check: repo.IsFormatExists, id: 1, want: true},
+		{name: "format paid", check: repo.IsFormatExists, id: 3, want: true},
+		{name: "unknown format", check: repo.IsFormatExists, id: 42},
+		{name: "post permission", check: repo.IsPostPermissionExists, id: 4, want: true},
+		{name: "unknown post permission", check: repo.IsPostPermissionExists, id: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := tt.check(ctx, tt.id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, exists)
+		})
+	}
+}
+
+func TestRepository_MemberCounters(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	insertTestMember(t, repo, societyUUID, newUUID(), 4, 2)
+	insertTestMember(t, repo, societyUUID, newUUID(), 4, 2)
+	insertTestMember(t, repo, societyUUID, newUUID(), 4, 3)
+
+	counters, err := repo.GetMemberCounters(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.MemberCounter{
+		{Role: 1, PaymentStatus: 1, Count: 1},
+		{Role: 4, PaymentStatus: 2, Count: 2},
+		{Role: 4, PaymentStatus: 3, Count: 1},
+	}, counters)
+
+	// участники с истёкшей оплатой не входят в member_count
+	count, err := repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	t.Run("reconcile drift", func(t *testing.T) {
+		_, err := repo.connection.Exec(`UPDATE society SET member_count = 10 WHERE id = $1`, societyUUID)
+		require.NoError(t, err)
+
+		drifts, err := repo.ReconcileMemberCounts(ctx, true)
+		require.NoError(t, err)
+		assert.Equal(t, []model.MemberCountDrift{{SocietyUUID: societyUUID, Stored: 10, Actual: 3}}, drifts)
+
+		drifts, err = repo.ReconcileMemberCounts(ctx, false)
+		require.NoError(t, err)
+		assert.Empty(t, drifts)
+	})
+}
+
+func TestRepository_WithTxRollback(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	societyUUID := createTestSociety(t, repo, newUUID())
+	errStop := errors.New("stop")
+
+	err := repo.WithTx(ctx, func(txRepo service.DbRepo) error {
+		if _, err := txRepo.AddSocietyMembers(ctx, newUUID(), societyUUID); err != nil {
+			return err
+		}
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	count, err := repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRepository_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Fixture {
+		repo := newTestRepository(t)
+		return repotest.Fixture{
+			Repo: repo,
+			AddTag: func(t *testing.T, societyUUID string, tagID int64, isActive bool) {
+				insertTestTag(t, repo, societyUUID, tagID, isActive)
+			},
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+func (r *Repository) GetSocietyRules(ctx context.Context, societyUUID string) (*model.SocietyRules, error) {
+	query, args, err := sq.Select("COALESCE(MAX(version), 0)").
+		From("society_rules_versions").
+		Where(sq.Eq{"society_id": societyUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	rules := &model.SocietyRules{Items: []model.SocietyRule{}}
+	err = sqlx.GetContext(ctx, r.readDB(ctx), &rules.Version, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyRules version: %w", err)
+	}
+	if rules.Version == 0 {
+		return rules, nil
+	}
+
+	query, args, err = sq.Select("title", "body").
+		From("society_rules").
+		Where(sq.Eq{"society_id": societyUUID, "version": rules.Version}).
+		OrderBy("position").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &rules.Items, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetSocietyRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SetSocietyRules сохраняет правила новой версией и возвращает её номер.
+// Пустой список тоже версия: так сообщество отменяет требование принять правила.
+func (r *Repository) SetSocietyRules(ctx context.Context, societyUUID, authorUUID string, rules []model.SocietyRule) (int64, error) {
+	var version int64
+
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		// параллельная правка упрётся в первичный ключ (society_id, version)
+		query, args, err := sq.Insert("society_rules_versions").
+			Columns("society_id", "version", "author_uuid").
+			Select(sq.Select().
+				Column("?::uuid", societyUUID).
+				Column("COALESCE(MAX(version), 0) + 1").
+				Column("?::uuid", authorUUID).
+				From("society_rules_versions").
+				Where(sq.Eq{"society_id": societyUUID})).
+			Suffix("RETURNING version").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_rules_versions insert query: %w", err)
+		}
+
+		err = sqlx.GetContext(ctx, txRepo.db(), &version, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_rules_versions: %w", err)
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+
+		insert := sq.Insert("society_rules").Columns("society_id", "version", "position", "title", "body")
+		for i, rule := range rules {
+			insert = insert.Values(societyUUID, version, i+1, rule.Title, rule.Body)
+		}
+		query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build society_rules insert query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert society_rules: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.pins.pin(ctx)
+	return version, nil
+}
+
+func (r *Repository) CreateAnnouncement(ctx context.Context, announcement *model.Announcement) (int64, error) {
+	query, args, err := sq.Insert("society_announcements").
+		Columns("society_id", "author_uuid", "title", "body", "expires_at").
+		Values(announcement.SocietyUUID, announcement.AuthorUUID, announcement.Title, announcement.Body, announcement.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build society_announcements insert query: %w", err)
+	}
+
+	var id int64
+	err = sqlx.GetContext(ctx, r.db(), &id, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert society_announcements: %w", err)
+	}
+
+	r.pins.pin(ctx)
+	return id, nil
+}
+
+func (r *Repository) RemoveAnnouncement(ctx context.Context, societyUUID string, announcementID int64) error {
+	query, args, err := sq.Delete("society_announcements").
+		Where(sq.Eq{"id": announcementID, "society_id": societyUUID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	res, err := r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query RemoveAnnouncement: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows RemoveAnnouncement: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to execute query RemoveAnnouncement: %w", sql.ErrNoRows)
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+// activeAnnouncementsLateral собирает в JSON неистёкшие объявления сообщества societyID в том же порядке,
+// что и GetActiveAnnouncements; результат — колонка announcements.items, читается в announcementsJSON
+func activeAnnouncementsLateral(societyID string) string {
+	return fmt.Sprintf(`CROSS JOIN LATERAL (
+    SELECT COALESCE(json_agg(json_build_object(
+        'id', a.id, 'society_id', a.society_id, 'author_uuid', a.author_uuid, 'title', a.title,
+        'body', a.body, 'expires_at', a.expires_at, 'created_at', a.created_at
+    ) ORDER BY a.created_at DESC, a.id DESC), '[]') AS items
+    FROM society_announcements a
+    WHERE a.society_id = %s AND (a.expires_at IS NULL OR a.expires_at > NOW())
+) announcements`, societyID)
+}
+
+type announcementsJSON []model.Announcement
+
+func (a *announcementsJSON) Scan(src any) error {
+	raw, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("unexpected announcements type %T", src)
+	}
+
+	var items []struct {
+		ID          int64      `json:"id"`
+		SocietyUUID string     `json:"society_id"`
+		AuthorUUID  string     `json:"author_uuid"`
+		Title       string     `json:"title"`
+		Body        string     `json:"body"`
+		ExpiresAt   *time.Time `json:"expires_at"`
+		CreatedAt   time.Time  `json:"created_at"`
+	}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return fmt.Errorf("failed to decode announcements: %w", err)
+	}
+
+	announcements := make(announcementsJSON, 0, len(items))
+	for _, item := range items {
+		announcement := model.Announcement{
+			ID:          item.ID,
+			SocietyUUID: item.SocietyUUID,
+			AuthorUUID:  item.AuthorUUID,
+			Title:       item.Title,
+			Body:        item.Body,
+			CreatedAt:   item.CreatedAt,
+		}
+		if item.ExpiresAt != nil {
+			announcement.ExpiresAt = sql.NullTime{Time: *item.ExpiresAt, Valid: true}
+		}
+		announcements = append(announcements, announcement)
+	}
+
+	*a = announcements
+	return nil
+}
+
+// GetActiveAnnouncements возвращает неистёкшие закреплённые объявления, новые первыми
+func (r *Repository) GetActiveAnnouncements(ctx context.Context, societyUUID string) ([]model.Announcement, error) {
+	query, args, err := sq.Select("id", "society_id", "author_uuid", "title", "body", "expires_at", "created_at").
+		From("society_announcements").
+		Where(sq.Eq{"society_id": societyUUID}).
+		Where(sq.Or{sq.Eq{"expires_at": nil}, sq.Expr("expires_at > NOW()")}).
+		OrderBy("created_at DESC", "id DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var announcements []model.Announcement
+	err = sqlx.SelectContext(ctx, r.readDB(ctx), &announcements, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query GetActiveAnnouncements: %w", err)
+	}
+
+	return announcements, nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// Интеграционные тесты поднимают одноразовый кластер Postgres через initdb и pg_ctl,
+// накатывают встроенные миграции и очищают таблицы после каждого теста.
+// Бинарники ищутся в SOCIETY_SERVICE_TEST_PG_BIN или в PATH, без них тесты пропускаются.
+// initdb не запускается от root, поэтому тесты нужно гонять от обычного пользователя.
+
+var testCluster struct {
+	once    sync.Once
+	pgCtl   string
+	dataDir string
+	started bool
+	cfg     *config.Config
+	skip    string
+	err     error
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	stopTestCluster()
+	os.Exit(code)
+}
+
+func pgBinary(name string) (string, error) {
+	if dir := os.Getenv("SOCIETY_SERVICE_TEST_PG_BIN"); dir != "" {
+		return filepath.Join(dir, name), nil
+	}
+	return exec.LookPath(name)
+}
+
+func freePort() (int, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer lis.Close()
+
+	return lis.Addr().(*net.TCPAddr).Port, nil
+}
+
+func startTestCluster() {
+	initdb, err := pgBinary("initdb")
+	if err != nil {
+		testCluster.skip = "initdb not found: set SOCIETY_SERVICE_TEST_PG_BIN or add Postgres binaries to PATH"
+		return
+	}
+	testCluster.pgCtl, err = pgBinary("pg_ctl")
+	if err != nil {
+		testCluster.skip = "pg_ctl not found: set SOCIETY_SERVICE_TEST_PG_BIN or add Postgres binaries to PATH"
+		return
+	}
+
+	testCluster.dataDir, err = os.MkdirTemp("", "society-pg-")
+	if err != nil {
+		testCluster.err = fmt.Errorf("failed to create data dir: %w", err)
+		return
+	}
+
+	out, err := exec.Command(initdb, "-D", testCluster.dataDir, "-U", "postgres", "-A", "trust", "--no-sync").CombinedOutput()
+	if err != nil {
+		testCluster.err = fmt.Errorf("initdb: %w: %s", err, out)
+		return
+	}
+
+	port, err := freePort()
+	if err != nil {
+		testCluster.err = fmt.Errorf("failed to pick port: %w", err)
+		return
+	}
+
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -c fsync=off", port, testCluster.dataDir)
+	out, err = exec.Command(testCluster.pgCtl, "-D", testCluster.dataDir, "-l", filepath.Join(testCluster.dataDir, "postgres.log"),
+		"-o", opts, "-w", "start").CombinedOutput()
+	if err != nil {
+		testCluster.err = fmt.Errorf("pg_ctl start: %w: %s", err, out)
+		return
+	}
+	testCluster.started = true
+
+	cfg := &config.Config{Postgres: config.Postgres{
+		User:             "postgres",
+		Database:         "postgres",
+		Host:             "127.0.0.1",
+		Port:             strconv.Itoa(port),
+		SSLMode:          "disable",
+		MaxOpenConns:     5,
+		MaxIdleConns:     5,
+		StatementTimeout: 10 * time.Second,
+		ConnectAttempts:  1,
+		TxMaxRetries:     3,
+		TxRetryBackoff:   10 * time.Millisecond,
+	}}
+
+	repo, err := New(cfg)
+	if err != nil {
+		testCluster.err = fmt.Errorf("failed to connect: %w", err)
+		return
+	}
+	defer repo.Close()
+
+	// на стендах uuid-ossp ставится вместе с базой, миграции его не создают
+	if _, err = repo.connection.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`); err != nil {
+		testCluster.err = fmt.Errorf("failed to create uuid-ossp: %w", err)
+		return
+	}
+	if err = repo.Migrate(context.Background(), MigrateUp, io.Discard); err != nil {
+		testCluster.err = err
+		return
+	}
+
+	testCluster.cfg = cfg
+}
+
+func stopTestCluster() {
+	if testCluster.started {
+		_ = exec.Command(testCluster.pgCtl, "-D", testCluster.dataDir, "-m", "immediate", "stop").Run()
+	}
+	if testCluster.dataDir != "" {
+		_ = os.RemoveAll(testCluster.dataDir)
+	}
+}
+
+// newTestRepository возвращает репозиторий поверх тестового кластера.
+// После теста все данные, кроме справочников из миграций, удаляются.
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	testCluster.once.Do(startTestCluster)
+	if testCluster.skip != "" {
+		t.Skip(testCluster.skip)
+	}
+	require.NoError(t, testCluster.err, "failed to start test postgres")
+
+	repo, err := New(testCluster.cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := repo.connection.Exec(`TRUNCATE society, society_has_tags, members_requests, society_members, society_member_counters, society_membership_events, society_outbox, society_waitlist, society_channels, society_events, society_polls CASCADE`)
+		require.NoError(t, err)
+		repo.Close()
+	})
+
+	return repo
+}
+
+func newUUID() string {
+	return uuid.Generate().String()
+}
+
+func createTestSociety(t *testing.T, repo *Repository, ownerUUID string) string {
+	t.Helper()
+
+	societyUUID, err := repo.CreateSociety(context.Background(), &model.SocietyData{
+		Name:           "society",
+		FormatID:       1,
+		PostPermission: 1,
+		OwnerUUID:      ownerUUID,
+	})
+	require.NoError(t, err)
+
+	return societyUUID
+}
+
+func insertTestMember(t *testing.T, repo *Repository, societyUUID, userUUID string, role, paymentStatus int) {
+	t.Helper()
+
+	_, err := repo.connection.Exec(
+		`INSERT INTO society_members (society_id, user_uuid, role, payment_status) VALUES ($1, $2, $3, $4)`,
+		societyUUID, userUUID, role, paymentStatus)
+	require.NoError(t, err)
+}
+
+func insertTestTag(t *testing.T, repo *Repository, societyUUID string, tagID int64, isActive bool) {
+	t.Helper()
+
+	_, err := repo.connection.Exec(
+		`INSERT INTO society_has_tags (society_id, tag_id, is_active) VALUES ($1, $2, $3)`,
+		societyUUID, tagID, isActive)
+	require.NoError(t, err)
+}
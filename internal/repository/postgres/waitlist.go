@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// первые в очереди становятся участниками; $2 = NULL снимает ограничение на число мест.
+// Уже состоящий в сообществе просто уходит из очереди
+const promoteWaitlistedQuery = `
+WITH promoted AS (
+    DELETE FROM society_waitlist
+    WHERE id IN (SELECT id FROM society_waitlist WHERE society_id = $1 ORDER BY id LIMIT $2)
+    RETURNING society_id, user_uuid
+),
+inserted AS (
+    INSERT INTO society_members (society_id, user_uuid, role)
+    SELECT society_id, user_uuid, 4 FROM promoted
+    ON CONFLICT (society_id, user_uuid) DO NOTHING
+    RETURNING 1
+)
+SELECT COUNT(*) FROM inserted`
+
+type societyCapacity struct {
+	MaxMembers  sql.NullInt64 `db:"max_members"`
+	MemberCount int64         `db:"member_count"`
+}
+
+func (c societyCapacity) isFull() bool {
+	return c.MaxMembers.Valid && c.MemberCount >= c.MaxMembers.Int64
+}
+
+// lockSocietyCapacity блокирует строку сообщества до конца транзакции. Через неё проходят
+// вступления, выходы и изменение лимита, поэтому очередь и member_count меняются согласованно
+func (r *Repository) lockSocietyCapacity(ctx context.Context, societyUUID string) (societyCapacity, error) {
+	query, args, err := sq.Select("max_members", "member_count").
+		From("society").
+		Where(sq.Eq{"id": societyUUID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return societyCapacity{}, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var capacity societyCapacity
+	err = sqlx.GetContext(ctx, r.db(), &capacity, query, args...)
+	if err != nil {
+		return societyCapacity{}, fmt.Errorf("failed to lock society: %w", err)
+	}
+
+	return capacity, nil
+}
+
+// promoteWaitlisted отдаёт свободные места очереди и возвращает обновлённую заполненность.
+// Строка сообщества должна быть заблокирована lockSocietyCapacity
+func (r *Repository) promoteWaitlisted(ctx context.Context, societyUUID string, capacity societyCapacity) (societyCapacity, error) {
+	var free sql.NullInt64
+	if capacity.MaxMembers.Valid {
+		if capacity.isFull() {
+			return capacity, nil
+		}
+		free = sql.NullInt64{Int64: capacity.MaxMembers.Int64 - capacity.MemberCount, Valid: true}
+	}
+
+	var promoted int64
+	err := sqlx.GetContext(ctx, r.db(), &promoted, promoteWaitlistedQuery, societyUUID, free)
+	if err != nil {
+		return capacity, fmt.Errorf("failed to execute query promoteWaitlisted: %w", err)
+	}
+
+	capacity.MemberCount += promoted
+	return capacity, nil
+}
+
+// fillFreeSeats вызывается после удаления участника в той же транзакции
+func (r *Repository) fillFreeSeats(ctx context.Context, societyUUID string) error {
+	capacity, err := r.lockSocietyCapacity(ctx, societyUUID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.promoteWaitlisted(ctx, societyUUID, capacity)
+	return err
+}
+
+// isMember проверяет, есть ли у пользователя строка в society_members
+func (r *Repository) isMember(ctx context.Context, userUUID, societyUUID string) (bool, error) {
+	query, args, err := sq.Select("1").
+		From("society_members").
+		Where(sq.Eq{"society_id": societyUUID, "user_uuid": userUUID}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	var exists bool
+	err = sqlx.GetContext(ctx, r.db(), &exists, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query isMember: %w", err)
+	}
+
+	return exists, nil
+}
+
+// joinOrWaitlist добавляет пользователя в участники, а если мест нет — в конец очереди.
+// Места, освободившиеся без выхода участника (например, истёкшая оплата), сначала получает очередь.
+// Участник проверяется под блокировкой сообщества, поэтому параллельные вступления не задвоят его
+func (r *Repository) joinOrWaitlist(ctx context.Context, userUUID, societyUUID string) (bool, error) {
+	capacity, err := r.lockSocietyCapacity(ctx, societyUUID)
+	if err != nil {
+		return false, err
+	}
+	member, err := r.isMember(ctx, userUUID, societyUUID)
+	if err != nil {
+		return false, err
+	}
+	if member {
+		return false, model.ErrAlreadyMember
+	}
+	if capacity.MaxMembers.Valid {
+		capacity, err = r.promoteWaitlisted(ctx, societyUUID, capacity)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if capacity.isFull() {
+		query, args, err := sq.Insert("society_waitlist").
+			Columns("society_id", "user_uuid").
+			Values(societyUUID, userUUID).
+			Suffix("ON CONFLICT (society_id, user_uuid) DO NOTHING").
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return false, fmt.Errorf("failed to build society_waitlist insert query: %w", err)
+		}
+
+		_, err = r.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert society_waitlist: %w", err)
+		}
+		return true, nil
+	}
+
+	query, args, err := sq.Insert("society_members").
+		Columns("society_id", "user_uuid", "role").
+		Values(societyUUID, userUUID, 4).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build add_society_members insert query: %w", err)
+	}
+
+	_, err = r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert add_society_members: %w", err)
+	}
+	return false, nil
+}
+
+// SetMaxMembers задаёт лимит участников; 0 снимает его. Лимит ниже текущего числа участников
+// никого не исключает, а освободившиеся или добавленные места сразу получает очередь
+func (r *Repository) SetMaxMembers(ctx context.Context, societyUUID string, maxMembers int64) error {
+	err := r.withTx(ctx, func(txRepo *Repository) error {
+		capacity, err := txRepo.lockSocietyCapacity(ctx, societyUUID)
+		if err != nil {
+			return err
+		}
+
+		capacity.MaxMembers = sql.NullInt64{Int64: maxMembers, Valid: maxMembers > 0}
+		query, args, err := sq.Update("society").
+			Set("max_members", capacity.MaxMembers).
+			Where(sq.Eq{"id": societyUUID}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL query: %w", err)
+		}
+
+		_, err = txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to execute query SetMaxMembers: %w", err)
+		}
+
+		_, err = txRepo.promoteWaitlisted(ctx, societyUUID, capacity)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+// RemoveSocietyMember исключает участника и отдаёт его место первому в очереди
+func (r *Repository) RemoveSocietyMember(ctx context.Context, uuid string, societyUUID string) error {
+	query, args, err := sq.Delete("society_members").
+		Where(sq.Eq{"society_id": societyUUID, "user_uuid": uuid}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	err = r.withMemberEvent(ctx, model.MemberEventKick, func(txRepo *Repository) error {
+		res, err := txRepo.db().ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to execute query RemoveSocietyMember: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows RemoveSocietyMember: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("failed to execute query RemoveSocietyMember: %w", sql.ErrNoRows)
+		}
+
+		return txRepo.fillFreeSeats(ctx, societyUUID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
+
+func (r *Repository) LeaveWaitlist(ctx context.Context, uuid string, societyUUID string) error {
+	query, args, err := sq.Delete("society_waitlist").
+		Where(sq.Eq{"society_id": societyUUID, "user_uuid": uuid}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL query: %w", err)
+	}
+
+	res, err := r.db().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query LeaveWaitlist: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows LeaveWaitlist: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("failed to execute query LeaveWaitlist: %w", sql.ErrNoRows)
+	}
+
+	r.pins.pin(ctx)
+	return nil
+}
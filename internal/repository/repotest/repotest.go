@@ -0,0 +1,1091 @@
+// Package repotest проверяет общую семантику реализаций service.DbRepo.
+// Каждая реализация (Postgres, память, кэширующие декораторы) запускает Run из своих тестов.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/model"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+// Fixture репозиторий под тестом и доступ к данным, которые нельзя создать через service.DbRepo
+type Fixture struct {
+	Repo service.DbRepo
+	// AddTag привязывает тег к сообществу в обход репозитория
+	AddTag func(t *testing.T, societyUUID string, tagID int64, isActive bool)
+}
+
+// лимит каналов, с которым создаются каналы в тестах, где он не проверяется
+const maxChannels = 50
+
+// Factory создаёт пустой репозиторий для одного подтеста
+type Factory func(t *testing.T) Fixture
+
+func Run(t *testing.T, factory Factory) {
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, factory(t)) })
+	t.Run("RemoveFlow", func(t *testing.T) { testRemoveFlow(t, factory(t)) })
+	t.Run("RemoveFlowRollback", func(t *testing.T) { testRemoveFlowRollback(t, factory(t)) })
+	t.Run("UserSocietiesOrdering", func(t *testing.T) { testUserSocietiesOrdering(t, factory(t)) })
+	t.Run("TagsActivity", func(t *testing.T) { testTagsActivity(t, factory(t)) })
+	t.Run("MembershipGrowth", func(t *testing.T) { testMembershipGrowth(t, factory(t)) })
+	t.Run("MembershipTimeline", func(t *testing.T) { testMembershipTimeline(t, factory(t)) })
+	t.Run("RulesAndAnnouncements", func(t *testing.T) { testRulesAndAnnouncements(t, factory(t)) })
+	t.Run("QuestionsAndAnswers", func(t *testing.T) { testQuestionsAndAnswers(t, factory(t)) })
+	t.Run("AutoApproval", func(t *testing.T) { testAutoApproval(t, factory(t)) })
+	t.Run("Waitlist", func(t *testing.T) { testWaitlist(t, factory(t)) })
+	t.Run("Channels", func(t *testing.T) { testChannels(t, factory(t)) })
+	t.Run("Hierarchy", func(t *testing.T) { testHierarchy(t, factory(t)) })
+	t.Run("HierarchyDepth", func(t *testing.T) { testHierarchyDepth(t, factory(t)) })
+	t.Run("Events", func(t *testing.T) { testEvents(t, factory(t)) })
+	t.Run("Polls", func(t *testing.T) { testPolls(t, factory(t)) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, factory(t)) })
+}
+
+func newUUID() string {
+	return uuid.Generate().String()
+}
+
+func createSociety(t *testing.T, repo service.DbRepo, ownerUUID string) string {
+	t.Helper()
+
+	societyUUID, err := repo.CreateSociety(context.Background(), &model.SocietyData{
+		Name:           "society",
+		FormatID:       1,
+		PostPermission: 1,
+		OwnerUUID:      ownerUUID,
+	})
+	require.NoError(t, err)
+
+	return societyUUID
+}
+
+// addMember добавляет участника в сообщество без лимита
+func addMember(t *testing.T, repo service.DbRepo, userUUID, societyUUID string) {
+	t.Helper()
+
+	waitlisted, err := repo.AddSocietyMembers(context.Background(), userUUID, societyUUID)
+	require.NoError(t, err)
+	require.False(t, waitlisted)
+}
+
+func removeSociety(ctx context.Context, repo service.DbRepo, societyUUID string) error {
+	if err := repo.RemoveSocietyHasTagsEntry(ctx, societyUUID); err != nil {
+		return err
+	}
+	if err := repo.RemoveMembersRequestEntry(ctx, societyUUID); err != nil {
+		return err
+	}
+	if err := repo.RemoveSocietyMembersEntry(ctx, societyUUID); err != nil {
+		return err
+	}
+	return repo.RemoveSociety(ctx, societyUUID)
+}
+
+// testNotFound: точечные чтения возвращают sql.ErrNoRows, пакетные пропускают отсутствующие id
+func testNotFound(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	missing, peerUUID := newUUID(), newUUID()
+
+	_, err := f.Repo.GetSocietyInfo(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "GetSocietyInfo")
+	_, err = f.Repo.GetSocietyInfoForPeer(ctx, missing, peerUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "GetSocietyInfoForPeer")
+	_, err = f.Repo.GetOwner(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "GetOwner")
+	_, err = f.Repo.GetFormatSociety(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "GetFormatSociety")
+	_, err = f.Repo.CountSubscribe(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "CountSubscribe")
+	_, err = f.Repo.GetRoleSocietyMembers(ctx, peerUUID, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "GetRoleSocietyMembers")
+	err = f.Repo.SetRequestTTL(ctx, missing, 24)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "SetRequestTTL")
+	err = f.Repo.SetMaxMembers(ctx, missing, 10)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "SetMaxMembers")
+	err = f.Repo.RemoveSocietyMember(ctx, peerUUID, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "RemoveSocietyMember")
+	err = f.Repo.LeaveWaitlist(ctx, peerUUID, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "LeaveWaitlist")
+
+	role, err := f.Repo.IsOwnerAdminModerator(ctx, peerUUID, missing)
+	require.NoError(t, err)
+	assert.Zero(t, role)
+
+	tags, err := f.Repo.GetTags(ctx, missing)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	counters, err := f.Repo.GetMemberCounters(ctx, missing)
+	require.NoError(t, err)
+	assert.Empty(t, counters)
+
+	existing := createSociety(t, f.Repo, newUUID())
+
+	infos, err := f.Repo.GetInfoSociety(ctx, []string{missing, existing})
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, existing, infos[0].SocietyUUID)
+
+	summaries, err := f.Repo.GetSocietiesByIDs(ctx, []string{missing, existing}, peerUUID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, existing, summaries[0].SocietyUUID)
+}
+
+func testRemoveFlow(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID := newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	f.AddTag(t, societyUUID, 1, true)
+	addMember(t, f.Repo, newUUID(), societyUUID)
+	require.NoError(t, f.Repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: newUUID(), SocietyUUID: societyUUID}))
+	channelUUID, err := f.Repo.CreateChannel(ctx, &model.Channel{SocietyUUID: societyUUID, Name: "general", FormatID: 1, PostPermission: 1}, ownerUUID, maxChannels)
+	require.NoError(t, err)
+
+	err = f.Repo.WithTx(ctx, func(repo service.DbRepo) error {
+		return removeSociety(ctx, repo, societyUUID)
+	})
+	require.NoError(t, err)
+
+	_, err = f.Repo.GetSocietyInfo(ctx, societyUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = f.Repo.CountSubscribe(ctx, societyUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = f.Repo.GetChannel(ctx, channelUUID, ownerUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testRemoveFlowRollback(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID, memberUUID, pendingUUID := newUUID(), newUUID(), newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	f.AddTag(t, societyUUID, 1, true)
+	addMember(t, f.Repo, memberUUID, societyUUID)
+	require.NoError(t, f.Repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: pendingUUID, SocietyUUID: societyUUID}))
+
+	assertIntact := func(t *testing.T) {
+		t.Helper()
+
+		info, err := f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, pendingUUID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), info.CountSubscribe)
+		assert.Equal(t, []int64{1}, info.TagsID)
+		assert.True(t, info.IsPending)
+
+		societyInfo, err := f.Repo.GetSocietyInfo(ctx, societyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, societyInfo.TagsID)
+
+		state, err := f.Repo.GetSocietyPeerState(ctx, societyUUID, pendingUUID)
+		require.NoError(t, err)
+		assert.Equal(t, model.SocietyPeerState{IsPending: true}, *state)
+
+		tags, err := f.Repo.GetTags(ctx, societyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, tags)
+
+		count, err := f.Repo.CountSubscribe(ctx, societyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		role, err := f.Repo.GetRoleSocietyMembers(ctx, memberUUID, societyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, role)
+	}
+	// прогреваем кэширующие реализации до отката
+	assertIntact(t)
+
+	t.Run("error returned by fn", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := f.Repo.WithTx(ctx, func(repo service.DbRepo) error {
+			if err := removeSociety(ctx, repo, societyUUID); err != nil {
+				return err
+			}
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+		assertIntact(t)
+	})
+
+	t.Run("failed statement", func(t *testing.T) {
+		// участники ещё ссылаются на сообщество, поэтому удаление падает
+		err := f.Repo.WithTx(ctx, func(repo service.DbRepo) error {
+			if err := repo.RemoveSocietyHasTagsEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			if err := repo.RemoveMembersRequestEntry(ctx, societyUUID); err != nil {
+				return err
+			}
+			return repo.RemoveSociety(ctx, societyUUID)
+		})
+		assert.Error(t, err)
+		assertIntact(t)
+	})
+}
+
+func testUserSocietiesOrdering(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	userUUID, callerUUID := newUUID(), newUUID()
+
+	var societies []string
+	for i := 0; i < 4; i++ {
+		societyUUID := createSociety(t, f.Repo, newUUID())
+		addMember(t, f.Repo, userUUID, societyUUID)
+		societies = append(societies, societyUUID)
+	}
+	addMember(t, f.Repo, callerUUID, societies[1])
+
+	ids := func(data []model.SocietyWithOffsetData) []string {
+		result := make([]string, 0, len(data))
+		for _, d := range data {
+			result = append(result, d.SocietyUUID)
+		}
+		return result
+	}
+
+	first, err := f.Repo.GetUserSocieties(ctx, &model.UserSocietiesFilter{UserUUID: userUUID, CallerUUID: callerUUID, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{societies[3], societies[2]}, ids(first))
+	assert.Greater(t, first[0].MemberID, first[1].MemberID)
+
+	byOffset, err := f.Repo.GetUserSocieties(ctx, &model.UserSocietiesFilter{UserUUID: userUUID, CallerUUID: callerUUID, Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{societies[1], societies[0]}, ids(byOffset))
+
+	byCursor, err := f.Repo.GetUserSocieties(ctx, &model.UserSocietiesFilter{
+		UserUUID:   userUUID,
+		CallerUUID: callerUUID,
+		Limit:      2,
+		Offset:     100, // при курсоре offset игнорируется
+		AfterID:    first[1].MemberID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{societies[1], societies[0]}, ids(byCursor))
+	assert.True(t, byCursor[0].IsMember)
+	assert.False(t, byCursor[1].IsMember)
+
+	total, err := f.Repo.CountUserSocieties(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+}
+
+func testTagsActivity(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	societyUUID := createSociety(t, f.Repo, newUUID())
+	other := createSociety(t, f.Repo, newUUID())
+	f.AddTag(t, societyUUID, 5, true)
+	f.AddTag(t, societyUUID, 2, true)
+	f.AddTag(t, societyUUID, 3, false)
+	f.AddTag(t, other, 1, true)
+
+	tags, err := f.Repo.GetTags(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, tags)
+
+	info, err := f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, newUUID())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, info.TagsID)
+
+	require.NoError(t, f.Repo.RemoveSocietyHasTagsEntry(ctx, societyUUID))
+
+	tags, err = f.Repo.GetTags(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	info, err = f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, newUUID())
+	require.NoError(t, err)
+	assert.Empty(t, info.TagsID)
+
+	tags, err = f.Repo.GetTags(ctx, other)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, tags)
+}
+
+func testMembershipGrowth(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	societyUUID := createSociety(t, f.Repo, newUUID())
+	memberUUID := newUUID()
+	addMember(t, f.Repo, memberUUID, societyUUID)
+	require.NoError(t, f.Repo.UnSubscribeToSociety(ctx, memberUUID, societyUUID))
+	require.NoError(t, f.Repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: newUUID(), SocietyUUID: societyUUID}))
+
+	growth, err := f.Repo.GetMembershipGrowth(ctx, societyUUID, time.Now().Add(-48*time.Hour))
+	require.NoError(t, err)
+	// два-три дня в зависимости от часового пояса хранилища, но без пропусков
+	require.GreaterOrEqual(t, len(growth), 2)
+	var joins, leaves int64
+	for i, day := range growth {
+		if i > 0 {
+			assert.Equal(t, 24*time.Hour, day.Day.Sub(growth[i-1].Day))
+		}
+		joins += day.Joins
+		leaves += day.Leaves
+	}
+	assert.Equal(t, int64(2), joins)
+	assert.Equal(t, int64(1), leaves)
+
+	stats, err := f.Repo.GetRequestStats(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.Zero(t, stats.Approved)
+}
+
+// testMembershipTimeline: каждое изменение состава пишет событие, удаление участников записывается как kick с инициатором
+func testMembershipTimeline(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID := newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	leaverUUID := newUUID()
+	addMember(t, f.Repo, leaverUUID, societyUUID)
+	require.NoError(t, f.Repo.UnSubscribeToSociety(ctx, leaverUUID, societyUUID))
+	memberUUID := newUUID()
+	addMember(t, f.Repo, memberUUID, societyUUID)
+
+	actorCtx := context.WithValue(ctx, config.KeyUUID, ownerUUID)
+	require.NoError(t, f.Repo.WithTx(actorCtx, func(repo service.DbRepo) error {
+		return repo.RemoveSocietyMembersEntry(actorCtx, societyUUID)
+	}))
+
+	events, err := f.Repo.GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{UserUUID: leaverUUID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, model.MemberEventLeave, events[0].EventType)
+	assert.Equal(t, model.MemberEventJoin, events[1].EventType)
+	assert.Equal(t, int64(4), events[1].Role)
+	assert.Greater(t, events[0].ID, events[1].ID)
+
+	events, err = f.Repo.GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{UserUUID: memberUUID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, model.MemberEventKick, events[0].EventType)
+	assert.Equal(t, ownerUUID, events[0].ActorUUID)
+	assert.Equal(t, societyUUID, events[0].SocietyUUID)
+
+	// постраничный обход истории сообщества по id последнего события
+	all, err := f.Repo.GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{SocietyUUID: societyUUID, Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, all, 6)
+	page, err := f.Repo.GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{
+		SocietyUUID: societyUUID,
+		Limit:       2,
+		AfterID:     all[1].ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, all[2:4], page)
+}
+
+// testRulesAndAnnouncements: правила версионируются целиком, истёкшие объявления не возвращаются,
+// а удаление сообщества уносит и то и другое
+func testRulesAndAnnouncements(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID := newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+
+	rules, err := f.Repo.GetSocietyRules(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Zero(t, rules.Version)
+	assert.Empty(t, rules.Items)
+
+	version, err := f.Repo.SetSocietyRules(ctx, societyUUID, ownerUUID, []model.SocietyRule{
+		{Title: "first", Body: "1"},
+		{Title: "second", Body: "2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+	version, err = f.Repo.SetSocietyRules(ctx, societyUUID, ownerUUID, []model.SocietyRule{
+		{Title: "only", Body: "3"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	rules, err = f.Repo.GetSocietyRules(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, &model.SocietyRules{Version: 2, Items: []model.SocietyRule{{Title: "only", Body: "3"}}}, rules)
+
+	permanentID, err := f.Repo.CreateAnnouncement(ctx, &model.Announcement{
+		SocietyUUID: societyUUID, AuthorUUID: ownerUUID, Title: "permanent", Body: "p",
+	})
+	require.NoError(t, err)
+	_, err = f.Repo.CreateAnnouncement(ctx, &model.Announcement{
+		SocietyUUID: societyUUID, AuthorUUID: ownerUUID, Title: "expired", Body: "e",
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+	activeID, err := f.Repo.CreateAnnouncement(ctx, &model.Announcement{
+		SocietyUUID: societyUUID, AuthorUUID: ownerUUID, Title: "active", Body: "a",
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	announcements, err := f.Repo.GetActiveAnnouncements(ctx, societyUUID)
+	require.NoError(t, err)
+	require.Len(t, announcements, 2)
+	assert.Equal(t, activeID, announcements[0].ID)
+	assert.Equal(t, permanentID, announcements[1].ID)
+	assert.False(t, announcements[1].ExpiresAt.Valid)
+	assert.Equal(t, ownerUUID, announcements[1].AuthorUUID)
+
+	info, err := f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, newUUID())
+	require.NoError(t, err)
+	require.Len(t, info.Announcements, 2, "GetSocietyInfoForPeer returns the same active announcements")
+	assert.Equal(t, activeID, info.Announcements[0].ID)
+	assert.True(t, info.Announcements[0].ExpiresAt.Valid)
+	assert.Equal(t, "permanent", info.Announcements[1].Title)
+	assert.False(t, info.Announcements[1].ExpiresAt.Valid)
+	state, err := f.Repo.GetSocietyPeerState(ctx, societyUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.Len(t, state.Announcements, 2)
+
+	require.NoError(t, f.Repo.RemoveAnnouncement(ctx, societyUUID, permanentID))
+	assert.ErrorIs(t, f.Repo.RemoveAnnouncement(ctx, societyUUID, permanentID), sql.ErrNoRows)
+	assert.ErrorIs(t, f.Repo.RemoveAnnouncement(ctx, newUUID(), activeID), sql.ErrNoRows)
+
+	require.NoError(t, removeSociety(ctx, f.Repo, societyUUID))
+	announcements, err = f.Repo.GetActiveAnnouncements(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Empty(t, announcements)
+	rules, err = f.Repo.GetSocietyRules(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Zero(t, rules.Version)
+}
+
+// testQuestionsAndAnswers: анкета заменяется целиком, ожидающие заявки отдаются с ответами постранично
+func testQuestionsAndAnswers(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	societyUUID := createSociety(t, f.Repo, newUUID())
+
+	require.NoError(t, f.Repo.SetSocietyQuestions(ctx, societyUUID, []model.SocietyQuestion{
+		{Kind: model.QuestionText, Prompt: "old"},
+	}))
+	require.NoError(t, f.Repo.SetSocietyQuestions(ctx, societyUUID, []model.SocietyQuestion{
+		{Kind: model.QuestionText, Prompt: "About you", IsRequired: true},
+		{Kind: model.QuestionMultiChoice, Prompt: "Stack", Options: []string{"Go", "C"}},
+	}))
+
+	questions, err := f.Repo.GetSocietyQuestions(ctx, societyUUID)
+	require.NoError(t, err)
+	require.Len(t, questions, 2)
+	assert.Equal(t, "About you", questions[0].Prompt)
+	assert.True(t, questions[0].IsRequired)
+	assert.Empty(t, questions[0].Options)
+	assert.Equal(t, []string{"Go", "C"}, questions[1].Options)
+	assert.NotEqual(t, questions[0].ID, questions[1].ID)
+
+	firstUUID, secondUUID := newUUID(), newUUID()
+	answers := []model.RequestAnswer{
+		{QuestionID: questions[0].ID, Prompt: questions[0].Prompt, Values: []string{"hi"}},
+		{QuestionID: questions[1].ID, Prompt: questions[1].Prompt, Values: []string{"Go", "C"}},
+	}
+	require.NoError(t, f.Repo.AddMembersRequests(ctx, &model.MembersRequest{
+		UserUUID:             firstUUID,
+		SocietyUUID:          societyUUID,
+		AcceptedRulesVersion: 1,
+		Answers:              answers,
+	}))
+	require.NoError(t, f.Repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: secondUUID, SocietyUUID: societyUUID}))
+
+	page, err := f.Repo.GetPendingRequests(ctx, &model.PendingRequestsFilter{SocietyUUID: societyUUID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, firstUUID, page[0].UserUUID)
+	assert.Equal(t, int64(1), page[0].AcceptedRulesVersion)
+	assert.Equal(t, answers, page[0].Answers)
+
+	page, err = f.Repo.GetPendingRequests(ctx, &model.PendingRequestsFilter{SocietyUUID: societyUUID, Limit: 10, AfterID: page[0].ID})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, secondUUID, page[0].UserUUID)
+	assert.Empty(t, page[0].Answers)
+}
+
+// testAutoApproval: одобрение по правилу делает пользователя участником, дневной лимит общий
+// для сообщества и переживает замену правил, а удалённое правило заявку не одобряет
+func testAutoApproval(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	societyUUID := createSociety(t, f.Repo, newUUID())
+
+	require.NoError(t, f.Repo.SetAutoApprovalRules(ctx, societyUUID, []model.AutoApprovalRule{
+		{Kind: model.AutoApproveInviteCode, InviteCode: "welcome21"},
+		{Kind: model.AutoApproveDailyLimit, DailyLimit: 1},
+	}))
+	rules, err := f.Repo.GetAutoApprovalRules(ctx, societyUUID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "welcome21", rules[0].InviteCode)
+	assert.Empty(t, rules[0].MemberOfSocietyUUID)
+	assert.Equal(t, int64(1), rules[1].DailyLimit)
+
+	firstUUID := newUUID()
+	approved, err := f.Repo.AutoApproveMembersRequest(ctx, &model.MembersRequest{UserUUID: firstUUID, SocietyUUID: societyUUID}, &rules[1])
+	require.NoError(t, err)
+	assert.True(t, approved)
+	role, err := f.Repo.GetRoleSocietyMembers(ctx, firstUUID, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, role)
+
+	_, err = f.Repo.AutoApproveMembersRequest(ctx, &model.MembersRequest{UserUUID: firstUUID, SocietyUUID: societyUUID}, &rules[0])
+	assert.ErrorIs(t, err, model.ErrAlreadyMember, "an approved member is not added twice")
+
+	approved, err = f.Repo.AutoApproveMembersRequest(ctx, &model.MembersRequest{UserUUID: newUUID(), SocietyUUID: societyUUID}, &rules[1])
+	require.NoError(t, err)
+	assert.False(t, approved, "daily limit is exhausted")
+
+	require.NoError(t, f.Repo.SetAutoApprovalRules(ctx, societyUUID, []model.AutoApprovalRule{
+		{Kind: model.AutoApproveDailyLimit, DailyLimit: 1},
+	}))
+	replaced, err := f.Repo.GetAutoApprovalRules(ctx, societyUUID)
+	require.NoError(t, err)
+	require.Len(t, replaced, 1)
+
+	approved, err = f.Repo.AutoApproveMembersRequest(ctx, &model.MembersRequest{UserUUID: newUUID(), SocietyUUID: societyUUID}, &replaced[0])
+	require.NoError(t, err)
+	assert.False(t, approved, "replacing rules must not reset the quota")
+
+	approved, err = f.Repo.AutoApproveMembersRequest(ctx, &model.MembersRequest{UserUUID: newUUID(), SocietyUUID: societyUUID}, &rules[0])
+	require.NoError(t, err)
+	assert.False(t, approved, "deleted rule must not approve")
+
+	pending, err := f.Repo.GetPendingRequests(ctx, &model.PendingRequestsFilter{SocietyUUID: societyUUID, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+// testWaitlist: в заполненное сообщество вступают через очередь, освободившееся место
+// при выходе, исключении или росте лимита получает первый в очереди
+func testWaitlist(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	societyUUID := createSociety(t, f.Repo, newUUID())
+	require.NoError(t, f.Repo.SetMaxMembers(ctx, societyUUID, 2))
+
+	memberUUID, firstUUID, secondUUID, thirdUUID := newUUID(), newUUID(), newUUID(), newUUID()
+	addMember(t, f.Repo, memberUUID, societyUUID)
+	for _, userUUID := range []string{firstUUID, secondUUID, thirdUUID} {
+		waitlisted, err := f.Repo.AddSocietyMembers(ctx, userUUID, societyUUID)
+		require.NoError(t, err)
+		assert.True(t, waitlisted)
+	}
+	waitlisted, err := f.Repo.AddSocietyMembers(ctx, secondUUID, societyUUID)
+	require.NoError(t, err)
+	assert.True(t, waitlisted, "joining twice keeps the place in the queue")
+
+	info, err := f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, secondUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), info.MaxMembers)
+	assert.Equal(t, int64(2), info.WaitlistPosition)
+	assert.False(t, info.IsMember)
+
+	require.NoError(t, f.Repo.UnSubscribeToSociety(ctx, memberUUID, societyUUID))
+	role, err := f.Repo.GetRoleSocietyMembers(ctx, firstUUID, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, role)
+
+	require.NoError(t, f.Repo.LeaveWaitlist(ctx, secondUUID, societyUUID))
+	assert.ErrorIs(t, f.Repo.LeaveWaitlist(ctx, secondUUID, societyUUID), sql.ErrNoRows)
+
+	info, err = f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, thirdUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), info.WaitlistPosition)
+	state, err := f.Repo.GetSocietyPeerState(ctx, societyUUID, thirdUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), state.WaitlistPosition)
+	assert.Zero(t, state.Role)
+	assert.False(t, state.IsPending)
+	state, err = f.Repo.GetSocietyPeerState(ctx, societyUUID, firstUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, state.Role)
+	assert.Equal(t, 4, state.EditorRole)
+	assert.Zero(t, state.WaitlistPosition)
+	assert.Empty(t, state.Announcements)
+
+	require.NoError(t, f.Repo.RemoveSocietyMember(ctx, firstUUID, societyUUID))
+	_, err = f.Repo.GetRoleSocietyMembers(ctx, thirdUUID, societyUUID)
+	require.NoError(t, err)
+	count, err := f.Repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	lateUUID := newUUID()
+	waitlisted, err = f.Repo.AddSocietyMembers(ctx, lateUUID, societyUUID)
+	require.NoError(t, err)
+	assert.True(t, waitlisted)
+	require.NoError(t, f.Repo.SetMaxMembers(ctx, societyUUID, 0))
+	info, err = f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, lateUUID)
+	require.NoError(t, err)
+	assert.True(t, info.IsMember, "removing the limit admits the queue")
+	assert.Zero(t, info.WaitlistPosition)
+	assert.Zero(t, info.MaxMembers)
+
+	// повторное вступление участника не задваивает его и не ставит в очередь заполненного сообщества
+	require.NoError(t, f.Repo.SetMaxMembers(ctx, societyUUID, 1))
+	_, err = f.Repo.AddSocietyMembers(ctx, lateUUID, societyUUID)
+	assert.ErrorIs(t, err, model.ErrAlreadyMember)
+	assert.ErrorIs(t, f.Repo.AddMembersRequests(ctx, &model.MembersRequest{UserUUID: lateUUID, SocietyUUID: societyUUID}), model.ErrAlreadyMember)
+	info, err = f.Repo.GetSocietyInfoForPeer(ctx, societyUUID, lateUUID)
+	require.NoError(t, err)
+	assert.True(t, info.IsMember)
+	assert.Zero(t, info.WaitlistPosition)
+	count, err = f.Repo.CountSubscribe(ctx, societyUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	events, err := f.Repo.GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{UserUUID: firstUUID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, model.MemberEventKick, events[0].EventType)
+}
+
+// testChannels: участники канала — подмножество участников сообщества, выход из сообщества
+// убирает пользователя из каналов, удаление канала убирает его участников
+func testChannels(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID, memberUUID, outsiderUUID := newUUID(), newUUID(), newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	addMember(t, f.Repo, memberUUID, societyUUID)
+
+	channelUUID, err := f.Repo.CreateChannel(ctx, &model.Channel{
+		SocietyUUID:    societyUUID,
+		Name:           "backend",
+		Description:    "Go and databases",
+		FormatID:       2,
+		PostPermission: 3,
+	}, ownerUUID, maxChannels)
+	require.NoError(t, err)
+
+	_, err = f.Repo.CreateChannel(ctx, &model.Channel{SocietyUUID: societyUUID, Name: "backend", FormatID: 1, PostPermission: 1}, ownerUUID, maxChannels)
+	assert.ErrorIs(t, err, model.ErrChannelNameTaken)
+	_, err = f.Repo.CreateChannel(ctx, &model.Channel{SocietyUUID: societyUUID, Name: "frontend", FormatID: 1, PostPermission: 1}, ownerUUID, 1)
+	assert.ErrorIs(t, err, model.ErrTooManyChannels)
+	otherUUID, err := f.Repo.CreateChannel(ctx, &model.Channel{SocietyUUID: societyUUID, Name: "frontend", FormatID: 1, PostPermission: 1}, ownerUUID, maxChannels)
+	require.NoError(t, err)
+	assert.ErrorIs(t, f.Repo.UpdateChannel(ctx, &model.Channel{ID: otherUUID, Name: "backend", FormatID: 1, PostPermission: 1}), model.ErrChannelNameTaken)
+	require.NoError(t, f.Repo.RemoveChannel(ctx, otherUUID))
+
+	channel, err := f.Repo.GetChannel(ctx, channelUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "backend", channel.Name)
+	assert.Equal(t, int64(2), channel.FormatID)
+	assert.Equal(t, int64(3), channel.PostPermission)
+	assert.Equal(t, int64(1), channel.MemberCount, "creator joins the channel")
+	assert.True(t, channel.IsMember)
+
+	assert.ErrorIs(t, f.Repo.AddChannelMember(ctx, channelUUID, outsiderUUID), sql.ErrNoRows, "outsider is not a society member")
+	require.NoError(t, f.Repo.AddChannelMember(ctx, channelUUID, memberUUID))
+	require.NoError(t, f.Repo.AddChannelMember(ctx, channelUUID, memberUUID), "joining twice is a no-op")
+
+	channels, err := f.Repo.GetSocietyChannels(ctx, societyUUID, memberUUID)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assert.Equal(t, int64(2), channels[0].MemberCount)
+	assert.True(t, channels[0].IsMember)
+
+	require.NoError(t, f.Repo.UnSubscribeToSociety(ctx, memberUUID, societyUUID))
+	channel, err = f.Repo.GetChannel(ctx, channelUUID, memberUUID)
+	require.NoError(t, err)
+	assert.False(t, channel.IsMember, "leaving the society leaves its channels")
+	assert.Equal(t, int64(1), channel.MemberCount)
+	assert.ErrorIs(t, f.Repo.RemoveChannelMember(ctx, channelUUID, memberUUID), sql.ErrNoRows)
+
+	require.NoError(t, f.Repo.UpdateChannel(ctx, &model.Channel{ID: channelUUID, Name: "platform", FormatID: 1, PostPermission: 4}))
+	channel, err = f.Repo.GetChannel(ctx, channelUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "platform", channel.Name)
+	assert.Empty(t, channel.Description)
+	assert.Equal(t, int64(1), channel.FormatID)
+
+	require.NoError(t, f.Repo.RemoveChannel(ctx, channelUUID))
+	_, err = f.Repo.GetChannel(ctx, channelUUID, ownerUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.ErrorIs(t, f.Repo.RemoveChannel(ctx, channelUUID), sql.ErrNoRows)
+	assert.ErrorIs(t, f.Repo.UpdateChannel(ctx, &model.Channel{ID: channelUUID, Name: "gone", FormatID: 1, PostPermission: 1}), sql.ErrNoRows)
+}
+
+// testHierarchy: циклы запрещены, предки идут от родителя к корню, модерация наследуется
+// только по цепочке с включённым флагом, а удаление родителя отвязывает детей
+func testHierarchy(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	schoolOwner, campusOwner, chapterOwner, member := newUUID(), newUUID(), newUUID(), newUUID()
+	school := createSociety(t, f.Repo, schoolOwner)
+	campus := createSociety(t, f.Repo, campusOwner)
+	chapter := createSociety(t, f.Repo, chapterOwner)
+	addMember(t, f.Repo, member, chapter)
+
+	acyclic, err := f.Repo.SetSocietyParent(ctx, campus, school, false)
+	require.NoError(t, err)
+	require.True(t, acyclic)
+	acyclic, err = f.Repo.SetSocietyParent(ctx, chapter, campus, true)
+	require.NoError(t, err)
+	require.True(t, acyclic)
+
+	acyclic, err = f.Repo.SetSocietyParent(ctx, school, chapter, false)
+	require.NoError(t, err)
+	assert.False(t, acyclic, "school under its own grandchild")
+
+	ancestors, err := f.Repo.GetSocietyAncestors(ctx, chapter)
+	require.NoError(t, err)
+	require.Len(t, ancestors, 2)
+	assert.Equal(t, campus, ancestors[0].SocietyUUID)
+	assert.Equal(t, int64(1), ancestors[0].Depth)
+	assert.Equal(t, school, ancestors[1].SocietyUUID)
+	assert.Empty(t, ancestors[1].ParentUUID)
+
+	children, err := f.Repo.GetSocietyChildren(ctx, campus)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	assert.Equal(t, chapter, children[0].SocietyUUID)
+	assert.True(t, children[0].InheritParentModeration)
+
+	role, err := f.Repo.IsOwnerAdminModerator(ctx, campusOwner, chapter)
+	require.NoError(t, err)
+	assert.Equal(t, 3, role, "campus owner moderates the chapter")
+	role, err = f.Repo.IsOwnerAdminModerator(ctx, schoolOwner, chapter)
+	require.NoError(t, err)
+	assert.Equal(t, 0, role, "campus does not inherit from school")
+	role, err = f.Repo.IsOwnerAdminModerator(ctx, chapterOwner, chapter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, role, "own role is kept")
+
+	info, err := f.Repo.GetSocietyInfoForPeer(ctx, chapter, campusOwner)
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.EditorRole, "GetSocietyInfoForPeer resolves inherited moderation")
+	assert.False(t, info.IsMember)
+	info, err = f.Repo.GetSocietyInfoForPeer(ctx, chapter, member)
+	require.NoError(t, err)
+	assert.Equal(t, 4, info.EditorRole)
+	state, err := f.Repo.GetSocietyPeerState(ctx, chapter, campusOwner)
+	require.NoError(t, err)
+	assert.Equal(t, 3, state.EditorRole)
+	assert.Zero(t, state.Role)
+
+	summaries, err := f.Repo.GetSocietiesByIDs(ctx, []string{chapter, school}, member)
+	require.NoError(t, err)
+	parents := make(map[string]string, len(summaries))
+	for _, summary := range summaries {
+		parents[summary.SocietyUUID] = summary.ParentUUID
+	}
+	assert.Equal(t, map[string]string{chapter: campus, school: ""}, parents)
+
+	require.NoError(t, removeSociety(ctx, f.Repo, campus))
+	ancestors, err = f.Repo.GetSocietyAncestors(ctx, chapter)
+	require.NoError(t, err)
+	assert.Empty(t, ancestors, "removing the parent unlinks the chapter")
+	role, err = f.Repo.IsOwnerAdminModerator(ctx, member, chapter)
+	require.NoError(t, err)
+	assert.Equal(t, 4, role)
+
+	acyclic, err = f.Repo.SetSocietyParent(ctx, newUUID(), school, false)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.False(t, acyclic)
+}
+
+// testHierarchyDepth: проверка цикла не поднимается выше 32 предков, поэтому более глубокая
+// привязка отклоняется целиком — даже если на самом деле она замкнула бы цикл
+func testHierarchyDepth(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID := newUUID()
+
+	chain := []string{createSociety(t, f.Repo, ownerUUID)}
+	for len(chain) <= 32 {
+		child := createSociety(t, f.Repo, ownerUUID)
+		acyclic, err := f.Repo.SetSocietyParent(ctx, child, chain[len(chain)-1], false)
+		require.NoError(t, err)
+		require.True(t, acyclic)
+		chain = append(chain, child)
+	}
+	root, deepest := chain[0], chain[len(chain)-1]
+
+	_, err := f.Repo.SetSocietyParent(ctx, createSociety(t, f.Repo, ownerUUID), deepest, false)
+	assert.ErrorIs(t, err, model.ErrHierarchyTooDeep)
+
+	_, err = f.Repo.SetSocietyParent(ctx, root, deepest, false)
+	assert.ErrorIs(t, err, model.ErrHierarchyTooDeep, "a cycle past the cap is rejected too")
+	ancestors, err := f.Repo.GetSocietyAncestors(ctx, root)
+	require.NoError(t, err)
+	assert.Empty(t, ancestors)
+}
+
+// testEvents: вместимость ограничивает записи, отменённые события видны в сообществе, но не в ленте
+// пользователя, а выход из сообщества снимает записи на будущие события
+func testEvents(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID, memberUUID, lateUUID, outsiderUUID := newUUID(), newUUID(), newUUID(), newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	addMember(t, f.Repo, memberUUID, societyUUID)
+	addMember(t, f.Repo, lateUUID, societyUUID)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	meetupUUID, err := f.Repo.CreateEvent(ctx, &model.SocietyEvent{
+		SocietyUUID: societyUUID,
+		AuthorUUID:  ownerUUID,
+		Title:       "Go meetup",
+		StartsAt:    start,
+		EndsAt:      start.Add(2 * time.Hour),
+		Location:    "Kazan, room 42",
+		Capacity:    2,
+	})
+	require.NoError(t, err)
+	hackathonUUID, err := f.Repo.CreateEvent(ctx, &model.SocietyEvent{
+		SocietyUUID: societyUUID,
+		AuthorUUID:  ownerUUID,
+		Title:       "Hackathon",
+		StartsAt:    start.Add(48 * time.Hour),
+		EndsAt:      start.Add(72 * time.Hour),
+		Link:        "https://meet.example.com/hack",
+	})
+	require.NoError(t, err)
+
+	for _, userUUID := range []string{ownerUUID, memberUUID, memberUUID} {
+		booked, err := f.Repo.SetEventRSVP(ctx, meetupUUID, userUUID, true)
+		require.NoError(t, err)
+		require.True(t, booked)
+	}
+	booked, err := f.Repo.SetEventRSVP(ctx, meetupUUID, lateUUID, true)
+	require.NoError(t, err)
+	assert.False(t, booked, "meetup is full")
+	_, err = f.Repo.SetEventRSVP(ctx, meetupUUID, outsiderUUID, true)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "outsider is not a society member")
+
+	event, err := f.Repo.GetEvent(ctx, meetupUUID, memberUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Go meetup", event.Title)
+	assert.Equal(t, "society", event.SocietyName)
+	assert.Equal(t, int64(2), event.Capacity)
+	assert.Equal(t, int64(2), event.GoingCount)
+	assert.True(t, event.IsGoing)
+	assert.True(t, start.Equal(event.StartsAt))
+
+	require.NoError(t, f.Repo.UnSubscribeToSociety(ctx, memberUUID, societyUUID))
+	booked, err = f.Repo.SetEventRSVP(ctx, meetupUUID, lateUUID, true)
+	require.NoError(t, err)
+	assert.True(t, booked, "leaving the society frees the seat")
+
+	require.NoError(t, f.Repo.CancelEvent(ctx, hackathonUUID))
+	assert.ErrorIs(t, f.Repo.CancelEvent(ctx, hackathonUUID), sql.ErrNoRows)
+	_, err = f.Repo.SetEventRSVP(ctx, hackathonUUID, ownerUUID, true)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "cancelled event takes no RSVPs")
+	assert.ErrorIs(t, f.Repo.UpdateEvent(ctx, &model.SocietyEvent{
+		ID: hackathonUUID, Title: "Hackathon", StartsAt: start, EndsAt: start.Add(time.Hour), Location: "online",
+	}), sql.ErrNoRows)
+
+	filter := &model.SocietyEventsFilter{SocietyUUID: societyUUID, PeerUUID: lateUUID, From: time.Now(), Limit: 10}
+	events, err := f.Repo.GetSocietyEvents(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, meetupUUID, events[0].ID)
+	assert.True(t, events[0].IsGoing)
+	assert.True(t, events[1].CancelledAt.Valid)
+
+	filter.To = start.Add(time.Hour)
+	events, err = f.Repo.GetSocietyEvents(ctx, filter)
+	require.NoError(t, err)
+	assert.Len(t, events, 1, "hackathon starts after the period")
+
+	upcoming, err := f.Repo.GetUpcomingEvents(ctx, &model.SocietyEventsFilter{PeerUUID: lateUUID, From: time.Now(), Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1, "cancelled events are left out")
+	assert.Equal(t, meetupUUID, upcoming[0].ID)
+	upcoming, err = f.Repo.GetUpcomingEvents(ctx, &model.SocietyEventsFilter{PeerUUID: memberUUID, From: time.Now(), Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, upcoming, "former member")
+
+	require.NoError(t, f.Repo.UpdateEvent(ctx, &model.SocietyEvent{
+		ID: meetupUUID, Title: "Go meetup #2", StartsAt: start, EndsAt: start.Add(3 * time.Hour), Link: "https://meet.example.com/go",
+	}))
+	event, err = f.Repo.GetEvent(ctx, meetupUUID, lateUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Go meetup #2", event.Title)
+	assert.Empty(t, event.Location)
+	assert.Zero(t, event.Capacity)
+
+	booked, err = f.Repo.SetEventRSVP(ctx, meetupUUID, lateUUID, false)
+	require.NoError(t, err)
+	assert.True(t, booked)
+	event, err = f.Repo.GetEvent(ctx, meetupUUID, lateUUID)
+	require.NoError(t, err)
+	assert.False(t, event.IsGoing)
+	assert.Equal(t, int64(1), event.GoingCount)
+
+	require.NoError(t, removeSociety(ctx, f.Repo, societyUUID))
+	_, err = f.Repo.GetEvent(ctx, meetupUUID, ownerUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "events cascade with the society")
+}
+
+func testPolls(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID, memberUUID, outsiderUUID := newUUID(), newUUID(), newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	addMember(t, f.Repo, memberUUID, societyUUID)
+
+	dayUUID, err := f.Repo.CreatePoll(ctx, &model.Poll{
+		SocietyUUID:       societyUUID,
+		AuthorUUID:        ownerUUID,
+		Question:          "Which day for the meetup?",
+		IsMultiple:        true,
+		ResultsVisibility: model.PollResultsLive,
+		Options:           []model.PollOption{{Text: "Friday"}, {Text: "Saturday"}, {Text: "Sunday"}},
+	})
+	require.NoError(t, err)
+
+	poll, err := f.Repo.GetPoll(ctx, dayUUID, memberUUID)
+	require.NoError(t, err)
+	require.Len(t, poll.Options, 3)
+	assert.Equal(t, "Saturday", poll.Options[1].Text)
+	assert.False(t, poll.ClosesAt.Valid)
+	friday, saturday := poll.Options[0].ID, poll.Options[1].ID
+
+	voted, err := f.Repo.VotePoll(ctx, dayUUID, memberUUID, []int64{friday, saturday})
+	require.NoError(t, err)
+	assert.True(t, voted)
+	voted, err = f.Repo.VotePoll(ctx, dayUUID, memberUUID, []int64{saturday})
+	require.NoError(t, err)
+	assert.False(t, voted, "one ballot per user")
+	voted, err = f.Repo.VotePoll(ctx, dayUUID, ownerUUID, []int64{saturday})
+	require.NoError(t, err)
+	assert.True(t, voted)
+	_, err = f.Repo.VotePoll(ctx, dayUUID, outsiderUUID, []int64{friday})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "outsider is not a society member")
+
+	poll, err = f.Repo.GetPoll(ctx, dayUUID, memberUUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), poll.VoterCount)
+	assert.True(t, poll.HasVoted)
+	assert.Equal(t, int64(1), poll.Options[0].VoteCount)
+	assert.Equal(t, int64(2), poll.Options[1].VoteCount)
+	assert.Zero(t, poll.Options[2].VoteCount)
+	assert.ElementsMatch(t, []string{memberUUID, ownerUUID}, poll.Options[1].VoterUUIDs)
+
+	secretUUID, err := f.Repo.CreatePoll(ctx, &model.Poll{
+		SocietyUUID:       societyUUID,
+		AuthorUUID:        ownerUUID,
+		Question:          "Rate the last meetup",
+		IsAnonymous:       true,
+		ResultsVisibility: model.PollResultsAfterClose,
+		Options:           []model.PollOption{{Text: "Good"}, {Text: "Bad"}},
+	})
+	require.NoError(t, err)
+	secret, err := f.Repo.GetPoll(ctx, secretUUID, ownerUUID)
+	require.NoError(t, err)
+	voted, err = f.Repo.VotePoll(ctx, secretUUID, memberUUID, []int64{secret.Options[0].ID})
+	require.NoError(t, err)
+	assert.True(t, voted)
+
+	secret, err = f.Repo.GetPoll(ctx, secretUUID, memberUUID)
+	require.NoError(t, err)
+	assert.True(t, secret.HasVoted)
+	assert.Equal(t, int64(1), secret.Options[0].VoteCount)
+	assert.Empty(t, secret.Options[0].VoterUUIDs, "anonymous votes keep no voter")
+
+	require.NoError(t, f.Repo.ClosePoll(ctx, secretUUID))
+	assert.ErrorIs(t, f.Repo.ClosePoll(ctx, secretUUID), sql.ErrNoRows)
+	_, err = f.Repo.VotePoll(ctx, secretUUID, ownerUUID, []int64{secret.Options[1].ID})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "closed poll takes no votes")
+	secret, err = f.Repo.GetPoll(ctx, secretUUID, ownerUUID)
+	require.NoError(t, err)
+	assert.True(t, secret.IsClosed(time.Now().Add(time.Second)))
+	assert.False(t, secret.HasVoted)
+
+	polls, err := f.Repo.GetSocietyPolls(ctx, &model.SocietyPollsFilter{SocietyUUID: societyUUID, PeerUUID: ownerUUID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, polls, 2)
+	assert.ElementsMatch(t, []string{dayUUID, secretUUID}, []string{polls[0].ID, polls[1].ID})
+	for _, poll := range polls {
+		assert.NotEmpty(t, poll.Options)
+	}
+	polls, err = f.Repo.GetSocietyPolls(ctx, &model.SocietyPollsFilter{SocietyUUID: societyUUID, PeerUUID: ownerUUID, Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, polls, 1)
+
+	require.NoError(t, removeSociety(ctx, f.Repo, societyUUID))
+	_, err = f.Repo.GetPoll(ctx, dayUUID, ownerUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "polls cascade with the society")
+}
+
+func testNotifications(t *testing.T, f Fixture) {
+	ctx := context.Background()
+	ownerUUID, quietUUID, digestUUID, backUUID, outsiderUUID := newUUID(), newUUID(), newUUID(), newUUID(), newUUID()
+	societyUUID := createSociety(t, f.Repo, ownerUUID)
+	for _, userUUID := range []string{quietUUID, digestUUID, backUUID} {
+		addMember(t, f.Repo, userUUID, societyUUID)
+	}
+
+	settings, err := f.Repo.GetNotificationSettings(ctx, societyUUID, quietUUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultNotificationSettings(societyUUID, quietUUID), *settings)
+	_, err = f.Repo.GetNotificationSettings(ctx, societyUUID, outsiderUUID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	quiet := model.DefaultNotificationSettings(societyUUID, quietUUID)
+	quiet.Muted = true
+	quiet.MutedUntil = sql.NullTime{Time: time.Now().Add(time.Hour).Truncate(time.Second), Valid: true}
+	require.NoError(t, f.Repo.SetNotificationSettings(ctx, &quiet))
+
+	digest := model.DefaultNotificationSettings(societyUUID, digestUUID)
+	digest.Posts = false
+	digest.Delivery = model.NotifyDeliveryDigest
+	require.NoError(t, f.Repo.SetNotificationSettings(ctx, &digest))
+
+	back := model.DefaultNotificationSettings(societyUUID, backUUID)
+	back.Muted = true
+	back.MutedUntil = sql.NullTime{Time: time.Now().Add(-time.Hour).Truncate(time.Second), Valid: true}
+	require.NoError(t, f.Repo.SetNotificationSettings(ctx, &back))
+
+	outsider := model.DefaultNotificationSettings(societyUUID, outsiderUUID)
+	assert.ErrorIs(t, f.Repo.SetNotificationSettings(ctx, &outsider), sql.ErrNoRows)
+
+	settings, err = f.Repo.GetNotificationSettings(ctx, societyUUID, quietUUID)
+	require.NoError(t, err)
+	assert.True(t, settings.Muted)
+	assert.True(t, quiet.MutedUntil.Time.Equal(settings.MutedUntil.Time))
+
+	recipientUUIDs := func(filter model.NotificationRecipientsFilter) []string {
+		t.Helper()
+		filter.SocietyUUID = societyUUID
+		if filter.Limit == 0 {
+			filter.Limit = 10
+		}
+		recipients, err := f.Repo.GetNotificationRecipients(ctx, &filter)
+		require.NoError(t, err)
+		uuids := make([]string, 0, len(recipients))
+		for _, recipient := range recipients {
+			uuids = append(uuids, recipient.UserUUID)
+		}
+		return uuids
+	}
+
+	assert.Equal(t, []string{backUUID, digestUUID, ownerUUID}, recipientUUIDs(model.NotificationRecipientsFilter{Activity: model.NotifyEvents}),
+		"newest members first; expired mute no longer applies")
+	assert.Equal(t, []string{backUUID, ownerUUID}, recipientUUIDs(model.NotificationRecipientsFilter{Activity: model.NotifyPosts}))
+	assert.Equal(t, []string{ownerUUID}, recipientUUIDs(model.NotificationRecipientsFilter{Activity: model.NotifyJoinRequests}),
+		"join requests go to moderators only")
+	assert.Equal(t, []string{backUUID, digestUUID}, recipientUUIDs(model.NotificationRecipientsFilter{
+		Activity: model.NotifyAnnouncements, ExcludeUUID: ownerUUID,
+	}))
+	assert.Equal(t, []string{ownerUUID}, recipientUUIDs(model.NotificationRecipientsFilter{
+		Activity: model.NotifyAnnouncements, Roles: []int64{1, 2},
+	}))
+
+	page, err := f.Repo.GetNotificationRecipients(ctx, &model.NotificationRecipientsFilter{
+		SocietyUUID: societyUUID, Activity: model.NotifyEvents, Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, model.NotifyDeliveryDigest, page[1].Delivery)
+	assert.Equal(t, []string{ownerUUID}, recipientUUIDs(model.NotificationRecipientsFilter{
+		Activity: model.NotifyEvents, AfterID: page[1].MemberID,
+	}))
+
+	require.NoError(t, f.Repo.UnSubscribeToSociety(ctx, digestUUID, societyUUID))
+	addMember(t, f.Repo, digestUUID, societyUUID)
+	settings, err = f.Repo.GetNotificationSettings(ctx, societyUUID, digestUUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultNotificationSettings(societyUUID, digestUUID), *settings, "rejoining starts from the defaults")
+}
@@ -3,31 +3,80 @@ package service
 
 import (
 	"context"
-
-	"github.com/jmoiron/sqlx"
+	"time"
 
 	society "github.com/s21platform/society-proto/society-proto"
 	"github.com/s21platform/society-service/internal/model"
 )
 
 type DbRepo interface {
-	Conn() *sqlx.DB
+	WithTx(ctx context.Context, fn func(repo DbRepo) error) error
 	CreateSociety(ctx context.Context, socData *model.SocietyData) (string, error)
 	GetSocietyInfo(ctx context.Context, societyUUID string) (*model.SocietyInfo, error)
+	GetSocietyInfoForPeer(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyInfo, error)
+	GetSocietyPeerState(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyPeerState, error)
 	UpdateSociety(ctx context.Context, societyData *society.UpdateSocietyIn) error
 	IsOwnerAdminModerator(ctx context.Context, peerUUID, societyUUID string) (int, error)
 	GetTags(ctx context.Context, societyUUID string) ([]int64, error)
 	CountSubscribe(ctx context.Context, societyUUID string) (int64, error)
-	RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error
-	RemoveMembersRequestEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error
-	RemoveSocietyMembersEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error
-	RemoveSociety(ctx context.Context, societyUUID string, tx *sqlx.Tx) error
+	GetMemberCounters(ctx context.Context, societyUUID string) ([]model.MemberCounter, error)
+	GetMembershipGrowth(ctx context.Context, societyUUID string, since time.Time) ([]model.DailyMembership, error)
+	GetRequestStats(ctx context.Context, societyUUID string) (*model.RequestStats, error)
+	GetMembershipTimeline(ctx context.Context, filter *model.MembershipTimelineFilter) ([]model.MembershipEvent, error)
+	RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string) error
+	RemoveMembersRequestEntry(ctx context.Context, societyUUID string) error
+	RemoveSocietyMembersEntry(ctx context.Context, societyUUID string) error
+	RemoveSociety(ctx context.Context, societyUUID string) error
 	GetOwner(ctx context.Context, societyId string) (string, error)
 	GetFormatSociety(ctx context.Context, societyUUID string) (int, error)
-	AddMembersRequests(ctx context.Context, uuid string, societyUUID string) error
-	AddSocietyMembers(ctx context.Context, uuid string, societyUUID string) error
+	AddMembersRequests(ctx context.Context, request *model.MembersRequest) error
+	AutoApproveMembersRequest(ctx context.Context, request *model.MembersRequest, rule *model.AutoApprovalRule) (bool, error)
+	SetRequestTTL(ctx context.Context, societyUUID string, ttlHours int64) error
+	GetPendingRequests(ctx context.Context, filter *model.PendingRequestsFilter) ([]model.MembersRequest, error)
+	AddSocietyMembers(ctx context.Context, uuid string, societyUUID string) (bool, error)
 	GetRoleSocietyMembers(ctx context.Context, uuid string, societyUUID string) (int, error)
 	UnSubscribeToSociety(ctx context.Context, uuid string, societyUUID string) error
-	GetUserSocieties(ctx context.Context, limit uint64, offset uint64, userUUID string) ([]string, error)
+	RemoveSocietyMember(ctx context.Context, uuid string, societyUUID string) error
+	LeaveWaitlist(ctx context.Context, uuid string, societyUUID string) error
+	SetMaxMembers(ctx context.Context, societyUUID string, maxMembers int64) error
+	GetUserSocieties(ctx context.Context, filter *model.UserSocietiesFilter) ([]model.SocietyWithOffsetData, error)
+	CountUserSocieties(ctx context.Context, userUUID string) (int64, error)
 	GetInfoSociety(ctx context.Context, groups []string) ([]model.SocietyWithOffsetData, error)
+	GetSocietiesByIDs(ctx context.Context, societyUUIDs []string, callerUUID string) ([]model.SocietySummary, error)
+	SetSocietyParent(ctx context.Context, societyUUID, parentUUID string, inheritModeration bool) (bool, error)
+	GetSocietyChildren(ctx context.Context, societyUUID string) ([]model.SocietyNode, error)
+	GetSocietyAncestors(ctx context.Context, societyUUID string) ([]model.SocietyNode, error)
+	GetSocietyRules(ctx context.Context, societyUUID string) (*model.SocietyRules, error)
+	SetSocietyRules(ctx context.Context, societyUUID, authorUUID string, rules []model.SocietyRule) (int64, error)
+	GetSocietyQuestions(ctx context.Context, societyUUID string) ([]model.SocietyQuestion, error)
+	SetSocietyQuestions(ctx context.Context, societyUUID string, questions []model.SocietyQuestion) error
+	GetAutoApprovalRules(ctx context.Context, societyUUID string) ([]model.AutoApprovalRule, error)
+	SetAutoApprovalRules(ctx context.Context, societyUUID string, rules []model.AutoApprovalRule) error
+	CreateChannel(ctx context.Context, channel *model.Channel, creatorUUID string, maxChannels int) (string, error)
+	GetChannel(ctx context.Context, channelUUID, peerUUID string) (*model.Channel, error)
+	GetSocietyChannels(ctx context.Context, societyUUID, peerUUID string) ([]model.Channel, error)
+	UpdateChannel(ctx context.Context, channel *model.Channel) error
+	RemoveChannel(ctx context.Context, channelUUID string) error
+	AddChannelMember(ctx context.Context, channelUUID, userUUID string) error
+	RemoveChannelMember(ctx context.Context, channelUUID, userUUID string) error
+	CreateEvent(ctx context.Context, event *model.SocietyEvent) (string, error)
+	GetEvent(ctx context.Context, eventUUID, peerUUID string) (*model.SocietyEvent, error)
+	GetSocietyEvents(ctx context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error)
+	GetUpcomingEvents(ctx context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error)
+	UpdateEvent(ctx context.Context, event *model.SocietyEvent) error
+	CancelEvent(ctx context.Context, eventUUID string) error
+	SetEventRSVP(ctx context.Context, eventUUID, userUUID string, going bool) (bool, error)
+	CreatePoll(ctx context.Context, poll *model.Poll) (string, error)
+	GetPoll(ctx context.Context, pollUUID, peerUUID string) (*model.Poll, error)
+	GetSocietyPolls(ctx context.Context, filter *model.SocietyPollsFilter) ([]model.Poll, error)
+	VotePoll(ctx context.Context, pollUUID, userUUID string, optionIDs []int64) (bool, error)
+	ClosePoll(ctx context.Context, pollUUID string) error
+	GetNotificationSettings(ctx context.Context, societyUUID, userUUID string) (*model.NotificationSettings, error)
+	SetNotificationSettings(ctx context.Context, settings *model.NotificationSettings) error
+	GetNotificationRecipients(ctx context.Context, filter *model.NotificationRecipientsFilter) ([]model.NotificationRecipient, error)
+	CreateAnnouncement(ctx context.Context, announcement *model.Announcement) (int64, error)
+	RemoveAnnouncement(ctx context.Context, societyUUID string, announcementID int64) error
+	GetActiveAnnouncements(ctx context.Context, societyUUID string) ([]model.Announcement, error)
+	IsFormatExists(ctx context.Context, formatID int64) (bool, error)
+	IsPostPermissionExists(ctx context.Context, postPermissionID int64) (bool, error)
 }
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// курсор непрозрачен для клиента: внутри лежит id последней записи на странице
+// в таблице, по которой идёт постраничная выдача (society_members, society_membership_events, members_requests)
+
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/docker/distribution/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	society "github.com/s21platform/society-proto/society-proto"
+
+	"github.com/s21platform/society-service/internal/config"
+	"github.com/s21platform/society-service/internal/infra"
+	"github.com/s21platform/society-service/internal/repository/memory"
+	"github.com/s21platform/society-service/internal/service"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) AddFuncName(string) {}
+func (nopLogger) Info(string)        {}
+func (nopLogger) Error(string)       {}
+func (nopLogger) Warn(string)        {}
+
+// startServer поднимает настоящий service.Server поверх memory.Repository за bufconn
+func startServer(t *testing.T) (society.SocietyServiceClient, *memory.Repository) {
+	t.Helper()
+
+	repo := memory.New()
+	lis := bufconn.Listen(1 << 20)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			infra.Verifcation,
+			func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				return handler(context.WithValue(ctx, config.KeyLogger, nopLogger{}), req)
+			},
+		),
+	)
+	society.RegisterSocietyServiceServer(s, service.New(repo))
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return society.NewSocietyServiceClient(conn), repo
+}
+
+func as(userUUID string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "uuid", userUUID)
+}
+
+func TestE2E_SocietyLifecycle(t *testing.T) {
+	t.Parallel()
+
+	client, repo := startServer(t)
+	ownerUUID, userUUID := uuid.Generate().String(), uuid.Generate().String()
+
+	created, err := client.CreateSociety(as(ownerUUID), &society.SetSocietyIn{
+		Name:             "closed club",
+		FormatID:         2,
+		PostPermissionID: 1,
+	})
+	require.NoError(t, err)
+	societyUUID := created.SocietyUUID
+
+	// закрытое сообщество: подписка превращается в заявку
+	_, err = client.SubscribeToSociety(as(userUUID), &society.SubscribeToSocietyIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+
+	info, err := client.GetSocietyInfo(as(userUUID), &society.GetSocietyInfoIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+	assert.True(t, info.IsRequestPending)
+	assert.False(t, info.IsMember)
+	assert.Equal(t, int64(1), info.CountSubscribe)
+
+	require.NoError(t, repo.ApproveMembersRequest(context.Background(), userUUID, societyUUID))
+
+	info, err = client.GetSocietyInfo(as(userUUID), &society.GetSocietyInfoIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+	assert.True(t, info.IsMember)
+	assert.False(t, info.IsRequestPending)
+	assert.False(t, info.CanEditSociety)
+	assert.Equal(t, int64(2), info.CountSubscribe)
+
+	counts, err := client.GetSocietyMemberCounts(as(userUUID), &society.GetSocietyMemberCountsIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), counts.Total)
+	assert.Equal(t, map[int64]int64{1: 1, 4: 1}, counts.ByRole)
+
+	mine, err := client.GetSocietyForUserWithOffset(as(userUUID), &society.GetSocietyForUserWithOffsetIn{UserUUID: userUUID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, mine.Societies, 1)
+	assert.Equal(t, societyUUID, mine.Societies[0].SocietyUUID)
+	assert.Equal(t, int64(1), mine.Total)
+
+	_, err = client.UnSubscribeToSociety(as(userUUID), &society.UnSubscribeToSocietyIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+
+	info, err = client.GetSocietyInfo(as(userUUID), &society.GetSocietyInfoIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+	assert.False(t, info.IsMember)
+	assert.Equal(t, int64(1), info.CountSubscribe)
+
+	_, err = client.RemoveSociety(as(userUUID), &society.RemoveSocietyIn{SocietyUUID: societyUUID})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.RemoveSociety(as(ownerUUID), &society.RemoveSocietyIn{SocietyUUID: societyUUID})
+	require.NoError(t, err)
+
+	_, err = client.GetSocietyInfo(as(ownerUUID), &society.GetSocietyInfoIn{SocietyUUID: societyUUID})
+	assert.Error(t, err)
+}
+
+func TestE2E_OpenSociety(t *testing.T) {
+	t.Parallel()
+
+	client, _ := startServer(t)
+	ownerUUID, userUUID := uuid.Generate().String(), uuid.Generate().String()
+
+	created, err := client.CreateSociety(as(ownerUUID), &society.SetSocietyIn{Name: "open club", FormatID: 1, PostPermissionID: 2})
+	require.NoError(t, err)
+
+	_, err = client.SubscribeToSociety(as(userUUID), &society.SubscribeToSocietyIn{SocietyUUID: created.SocietyUUID})
+	require.NoError(t, err)
+
+	byIDs, err := client.GetSocietiesByIDs(as(userUUID), &society.GetSocietiesByIDsIn{SocietyUUIDs: []string{created.SocietyUUID}})
+	require.NoError(t, err)
+	require.Contains(t, byIDs.Societies, created.SocietyUUID)
+	assert.Equal(t, int64(4), byIDs.Societies[created.SocietyUUID].Role)
+	assert.Equal(t, int64(2), byIDs.Societies[created.SocietyUUID].CountSubscribe)
+
+	owner, err := client.GetSocietyInfo(as(ownerUUID), &society.GetSocietyInfoIn{SocietyUUID: created.SocietyUUID})
+	require.NoError(t, err)
+	assert.True(t, owner.CanEditSociety)
+}
+
+func TestE2E_Unauthenticated(t *testing.T) {
+	t.Parallel()
+
+	client, _ := startServer(t)
+
+	_, err := client.GetSocietyInfo(context.Background(), &society.GetSocietyInfoIn{SocietyUUID: uuid.Generate().String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
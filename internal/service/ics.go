@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/s21platform/society-service/internal/model"
+)
+
+// выгрузка событий в iCalendar (RFC 5545)
+
+const (
+	icsProdID     = "-//s21platform//society-service//RU"
+	icsUIDDomain  = "society-service.s21platform"
+	icsTimeLayout = "20060102T150405Z"
+	icsLineLimit  = 75 // октетов в строке без CRLF
+)
+
+var icsTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// eventsToICS собирает календарь; пустой calendarName не попадает в X-WR-CALNAME.
+// Отменённые события выгружаются со STATUS:CANCELLED, чтобы подписанные календари их убрали
+func eventsToICS(calendarName string, events []model.SocietyEvent, now time.Time) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:"+icsProdID)
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	if calendarName != "" {
+		writeICSLine(&b, "X-WR-CALNAME:"+icsTextEscaper.Replace(calendarName))
+	}
+
+	for _, event := range events {
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+event.ID+"@"+icsUIDDomain)
+		writeICSLine(&b, "DTSTAMP:"+icsTime(now))
+		writeICSLine(&b, "DTSTART:"+icsTime(event.StartsAt))
+		writeICSLine(&b, "DTEND:"+icsTime(event.EndsAt))
+		writeICSLine(&b, "CREATED:"+icsTime(event.CreatedAt))
+		writeICSLine(&b, "LAST-MODIFIED:"+icsTime(event.UpdatedAt))
+		writeICSLine(&b, "SUMMARY:"+icsTextEscaper.Replace(event.Title))
+		if event.Description != "" {
+			writeICSLine(&b, "DESCRIPTION:"+icsTextEscaper.Replace(event.Description))
+		}
+		if event.Location != "" {
+			writeICSLine(&b, "LOCATION:"+icsTextEscaper.Replace(event.Location))
+		}
+		if event.Link != "" {
+			writeICSLine(&b, "URL:"+event.Link)
+		}
+		if event.SocietyName != "" {
+			writeICSLine(&b, "CATEGORIES:"+icsTextEscaper.Replace(event.SocietyName))
+		}
+		if event.CancelledAt.Valid {
+			writeICSLine(&b, "STATUS:CANCELLED")
+		} else {
+			writeICSLine(&b, "STATUS:CONFIRMED")
+		}
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format(icsTimeLayout)
+}
+
+// writeICSLine переносит строку длиннее icsLineLimit октетов: продолжение начинается с пробела,
+// а многобайтовые символы не разрываются
+func writeICSLine(b *strings.Builder, line string) {
+	for len(line) > icsLineLimit {
+		cut := icsLineLimit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n")
+		line = " " + line[cut:]
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -7,9 +7,9 @@ package service
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-	sqlx "github.com/jmoiron/sqlx"
 	society_proto "github.com/s21platform/society-proto/society-proto"
 	model "github.com/s21platform/society-service/internal/model"
 )
@@ -37,26 +37,41 @@ func (m *MockDbRepo) EXPECT() *MockDbRepoMockRecorder {
 	return m.recorder
 }
 
+// AddChannelMember mocks base method.
+func (m *MockDbRepo) AddChannelMember(ctx context.Context, channelUUID, userUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddChannelMember", ctx, channelUUID, userUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddChannelMember indicates an expected call of AddChannelMember.
+func (mr *MockDbRepoMockRecorder) AddChannelMember(ctx, channelUUID, userUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChannelMember", reflect.TypeOf((*MockDbRepo)(nil).AddChannelMember), ctx, channelUUID, userUUID)
+}
+
 // AddMembersRequests mocks base method.
-func (m *MockDbRepo) AddMembersRequests(ctx context.Context, uuid, societyUUID string) error {
+func (m *MockDbRepo) AddMembersRequests(ctx context.Context, request *model.MembersRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddMembersRequests", ctx, uuid, societyUUID)
+	ret := m.ctrl.Call(m, "AddMembersRequests", ctx, request)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddMembersRequests indicates an expected call of AddMembersRequests.
-func (mr *MockDbRepoMockRecorder) AddMembersRequests(ctx, uuid, societyUUID interface{}) *gomock.Call {
+func (mr *MockDbRepoMockRecorder) AddMembersRequests(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembersRequests", reflect.TypeOf((*MockDbRepo)(nil).AddMembersRequests), ctx, uuid, societyUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembersRequests", reflect.TypeOf((*MockDbRepo)(nil).AddMembersRequests), ctx, request)
 }
 
 // AddSocietyMembers mocks base method.
-func (m *MockDbRepo) AddSocietyMembers(ctx context.Context, uuid, societyUUID string) error {
+func (m *MockDbRepo) AddSocietyMembers(ctx context.Context, uuid, societyUUID string) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddSocietyMembers", ctx, uuid, societyUUID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddSocietyMembers indicates an expected call of AddSocietyMembers.
@@ -65,18 +80,47 @@ func (mr *MockDbRepoMockRecorder) AddSocietyMembers(ctx, uuid, societyUUID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSocietyMembers", reflect.TypeOf((*MockDbRepo)(nil).AddSocietyMembers), ctx, uuid, societyUUID)
 }
 
-// Conn mocks base method.
-func (m *MockDbRepo) Conn() *sqlx.DB {
+// AutoApproveMembersRequest mocks base method.
+func (m *MockDbRepo) AutoApproveMembersRequest(ctx context.Context, request *model.MembersRequest, rule *model.AutoApprovalRule) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutoApproveMembersRequest", ctx, request, rule)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AutoApproveMembersRequest indicates an expected call of AutoApproveMembersRequest.
+func (mr *MockDbRepoMockRecorder) AutoApproveMembersRequest(ctx, request, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutoApproveMembersRequest", reflect.TypeOf((*MockDbRepo)(nil).AutoApproveMembersRequest), ctx, request, rule)
+}
+
+// CancelEvent mocks base method.
+func (m *MockDbRepo) CancelEvent(ctx context.Context, eventUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEvent", ctx, eventUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelEvent indicates an expected call of CancelEvent.
+func (mr *MockDbRepoMockRecorder) CancelEvent(ctx, eventUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEvent", reflect.TypeOf((*MockDbRepo)(nil).CancelEvent), ctx, eventUUID)
+}
+
+// ClosePoll mocks base method.
+func (m *MockDbRepo) ClosePoll(ctx context.Context, pollUUID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Conn")
-	ret0, _ := ret[0].(*sqlx.DB)
+	ret := m.ctrl.Call(m, "ClosePoll", ctx, pollUUID)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Conn indicates an expected call of Conn.
-func (mr *MockDbRepoMockRecorder) Conn() *gomock.Call {
+// ClosePoll indicates an expected call of ClosePoll.
+func (mr *MockDbRepoMockRecorder) ClosePoll(ctx, pollUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conn", reflect.TypeOf((*MockDbRepo)(nil).Conn))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePoll", reflect.TypeOf((*MockDbRepo)(nil).ClosePoll), ctx, pollUUID)
 }
 
 // CountSubscribe mocks base method.
@@ -94,6 +138,81 @@ func (mr *MockDbRepoMockRecorder) CountSubscribe(ctx, societyUUID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubscribe", reflect.TypeOf((*MockDbRepo)(nil).CountSubscribe), ctx, societyUUID)
 }
 
+// CountUserSocieties mocks base method.
+func (m *MockDbRepo) CountUserSocieties(ctx context.Context, userUUID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserSocieties", ctx, userUUID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserSocieties indicates an expected call of CountUserSocieties.
+func (mr *MockDbRepoMockRecorder) CountUserSocieties(ctx, userUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserSocieties", reflect.TypeOf((*MockDbRepo)(nil).CountUserSocieties), ctx, userUUID)
+}
+
+// CreateAnnouncement mocks base method.
+func (m *MockDbRepo) CreateAnnouncement(ctx context.Context, announcement *model.Announcement) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnnouncement", ctx, announcement)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAnnouncement indicates an expected call of CreateAnnouncement.
+func (mr *MockDbRepoMockRecorder) CreateAnnouncement(ctx, announcement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnnouncement", reflect.TypeOf((*MockDbRepo)(nil).CreateAnnouncement), ctx, announcement)
+}
+
+// CreateChannel mocks base method.
+func (m *MockDbRepo) CreateChannel(ctx context.Context, channel *model.Channel, creatorUUID string, maxChannels int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChannel", ctx, channel, creatorUUID, maxChannels)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChannel indicates an expected call of CreateChannel.
+func (mr *MockDbRepoMockRecorder) CreateChannel(ctx, channel, creatorUUID, maxChannels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannel", reflect.TypeOf((*MockDbRepo)(nil).CreateChannel), ctx, channel, creatorUUID, maxChannels)
+}
+
+// CreateEvent mocks base method.
+func (m *MockDbRepo) CreateEvent(ctx context.Context, event *model.SocietyEvent) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockDbRepoMockRecorder) CreateEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockDbRepo)(nil).CreateEvent), ctx, event)
+}
+
+// CreatePoll mocks base method.
+func (m *MockDbRepo) CreatePoll(ctx context.Context, poll *model.Poll) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePoll", ctx, poll)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePoll indicates an expected call of CreatePoll.
+func (mr *MockDbRepoMockRecorder) CreatePoll(ctx, poll interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoll", reflect.TypeOf((*MockDbRepo)(nil).CreatePoll), ctx, poll)
+}
+
 // CreateSociety mocks base method.
 func (m *MockDbRepo) CreateSociety(ctx context.Context, socData *model.SocietyData) (string, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +228,66 @@ func (mr *MockDbRepoMockRecorder) CreateSociety(ctx, socData interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSociety", reflect.TypeOf((*MockDbRepo)(nil).CreateSociety), ctx, socData)
 }
 
+// GetActiveAnnouncements mocks base method.
+func (m *MockDbRepo) GetActiveAnnouncements(ctx context.Context, societyUUID string) ([]model.Announcement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAnnouncements", ctx, societyUUID)
+	ret0, _ := ret[0].([]model.Announcement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAnnouncements indicates an expected call of GetActiveAnnouncements.
+func (mr *MockDbRepoMockRecorder) GetActiveAnnouncements(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAnnouncements", reflect.TypeOf((*MockDbRepo)(nil).GetActiveAnnouncements), ctx, societyUUID)
+}
+
+// GetAutoApprovalRules mocks base method.
+func (m *MockDbRepo) GetAutoApprovalRules(ctx context.Context, societyUUID string) ([]model.AutoApprovalRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutoApprovalRules", ctx, societyUUID)
+	ret0, _ := ret[0].([]model.AutoApprovalRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutoApprovalRules indicates an expected call of GetAutoApprovalRules.
+func (mr *MockDbRepoMockRecorder) GetAutoApprovalRules(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoApprovalRules", reflect.TypeOf((*MockDbRepo)(nil).GetAutoApprovalRules), ctx, societyUUID)
+}
+
+// GetChannel mocks base method.
+func (m *MockDbRepo) GetChannel(ctx context.Context, channelUUID, peerUUID string) (*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannel", ctx, channelUUID, peerUUID)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannel indicates an expected call of GetChannel.
+func (mr *MockDbRepoMockRecorder) GetChannel(ctx, channelUUID, peerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockDbRepo)(nil).GetChannel), ctx, channelUUID, peerUUID)
+}
+
+// GetEvent mocks base method.
+func (m *MockDbRepo) GetEvent(ctx context.Context, eventUUID, peerUUID string) (*model.SocietyEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventUUID, peerUUID)
+	ret0, _ := ret[0].(*model.SocietyEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockDbRepoMockRecorder) GetEvent(ctx, eventUUID, peerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockDbRepo)(nil).GetEvent), ctx, eventUUID, peerUUID)
+}
+
 // GetFormatSociety mocks base method.
 func (m *MockDbRepo) GetFormatSociety(ctx context.Context, societyUUID string) (int, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +318,81 @@ func (mr *MockDbRepoMockRecorder) GetInfoSociety(ctx, groups interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfoSociety", reflect.TypeOf((*MockDbRepo)(nil).GetInfoSociety), ctx, groups)
 }
 
+// GetMemberCounters mocks base method.
+func (m *MockDbRepo) GetMemberCounters(ctx context.Context, societyUUID string) ([]model.MemberCounter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberCounters", ctx, societyUUID)
+	ret0, _ := ret[0].([]model.MemberCounter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberCounters indicates an expected call of GetMemberCounters.
+func (mr *MockDbRepoMockRecorder) GetMemberCounters(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberCounters", reflect.TypeOf((*MockDbRepo)(nil).GetMemberCounters), ctx, societyUUID)
+}
+
+// GetMembershipGrowth mocks base method.
+func (m *MockDbRepo) GetMembershipGrowth(ctx context.Context, societyUUID string, since time.Time) ([]model.DailyMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembershipGrowth", ctx, societyUUID, since)
+	ret0, _ := ret[0].([]model.DailyMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembershipGrowth indicates an expected call of GetMembershipGrowth.
+func (mr *MockDbRepoMockRecorder) GetMembershipGrowth(ctx, societyUUID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembershipGrowth", reflect.TypeOf((*MockDbRepo)(nil).GetMembershipGrowth), ctx, societyUUID, since)
+}
+
+// GetMembershipTimeline mocks base method.
+func (m *MockDbRepo) GetMembershipTimeline(ctx context.Context, filter *model.MembershipTimelineFilter) ([]model.MembershipEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembershipTimeline", ctx, filter)
+	ret0, _ := ret[0].([]model.MembershipEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembershipTimeline indicates an expected call of GetMembershipTimeline.
+func (mr *MockDbRepoMockRecorder) GetMembershipTimeline(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembershipTimeline", reflect.TypeOf((*MockDbRepo)(nil).GetMembershipTimeline), ctx, filter)
+}
+
+// GetNotificationRecipients mocks base method.
+func (m *MockDbRepo) GetNotificationRecipients(ctx context.Context, filter *model.NotificationRecipientsFilter) ([]model.NotificationRecipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationRecipients", ctx, filter)
+	ret0, _ := ret[0].([]model.NotificationRecipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationRecipients indicates an expected call of GetNotificationRecipients.
+func (mr *MockDbRepoMockRecorder) GetNotificationRecipients(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationRecipients", reflect.TypeOf((*MockDbRepo)(nil).GetNotificationRecipients), ctx, filter)
+}
+
+// GetNotificationSettings mocks base method.
+func (m *MockDbRepo) GetNotificationSettings(ctx context.Context, societyUUID, userUUID string) (*model.NotificationSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationSettings", ctx, societyUUID, userUUID)
+	ret0, _ := ret[0].(*model.NotificationSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationSettings indicates an expected call of GetNotificationSettings.
+func (mr *MockDbRepoMockRecorder) GetNotificationSettings(ctx, societyUUID, userUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationSettings", reflect.TypeOf((*MockDbRepo)(nil).GetNotificationSettings), ctx, societyUUID, userUUID)
+}
+
 // GetOwner mocks base method.
 func (m *MockDbRepo) GetOwner(ctx context.Context, societyId string) (string, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +408,51 @@ func (mr *MockDbRepoMockRecorder) GetOwner(ctx, societyId interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwner", reflect.TypeOf((*MockDbRepo)(nil).GetOwner), ctx, societyId)
 }
 
+// GetPendingRequests mocks base method.
+func (m *MockDbRepo) GetPendingRequests(ctx context.Context, filter *model.PendingRequestsFilter) ([]model.MembersRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingRequests", ctx, filter)
+	ret0, _ := ret[0].([]model.MembersRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingRequests indicates an expected call of GetPendingRequests.
+func (mr *MockDbRepoMockRecorder) GetPendingRequests(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingRequests", reflect.TypeOf((*MockDbRepo)(nil).GetPendingRequests), ctx, filter)
+}
+
+// GetPoll mocks base method.
+func (m *MockDbRepo) GetPoll(ctx context.Context, pollUUID, peerUUID string) (*model.Poll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoll", ctx, pollUUID, peerUUID)
+	ret0, _ := ret[0].(*model.Poll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoll indicates an expected call of GetPoll.
+func (mr *MockDbRepoMockRecorder) GetPoll(ctx, pollUUID, peerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoll", reflect.TypeOf((*MockDbRepo)(nil).GetPoll), ctx, pollUUID, peerUUID)
+}
+
+// GetRequestStats mocks base method.
+func (m *MockDbRepo) GetRequestStats(ctx context.Context, societyUUID string) (*model.RequestStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequestStats", ctx, societyUUID)
+	ret0, _ := ret[0].(*model.RequestStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRequestStats indicates an expected call of GetRequestStats.
+func (mr *MockDbRepoMockRecorder) GetRequestStats(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestStats", reflect.TypeOf((*MockDbRepo)(nil).GetRequestStats), ctx, societyUUID)
+}
+
 // GetRoleSocietyMembers mocks base method.
 func (m *MockDbRepo) GetRoleSocietyMembers(ctx context.Context, uuid, societyUUID string) (int, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +468,81 @@ func (mr *MockDbRepoMockRecorder) GetRoleSocietyMembers(ctx, uuid, societyUUID i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleSocietyMembers", reflect.TypeOf((*MockDbRepo)(nil).GetRoleSocietyMembers), ctx, uuid, societyUUID)
 }
 
+// GetSocietiesByIDs mocks base method.
+func (m *MockDbRepo) GetSocietiesByIDs(ctx context.Context, societyUUIDs []string, callerUUID string) ([]model.SocietySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietiesByIDs", ctx, societyUUIDs, callerUUID)
+	ret0, _ := ret[0].([]model.SocietySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietiesByIDs indicates an expected call of GetSocietiesByIDs.
+func (mr *MockDbRepoMockRecorder) GetSocietiesByIDs(ctx, societyUUIDs, callerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietiesByIDs", reflect.TypeOf((*MockDbRepo)(nil).GetSocietiesByIDs), ctx, societyUUIDs, callerUUID)
+}
+
+// GetSocietyAncestors mocks base method.
+func (m *MockDbRepo) GetSocietyAncestors(ctx context.Context, societyUUID string) ([]model.SocietyNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyAncestors", ctx, societyUUID)
+	ret0, _ := ret[0].([]model.SocietyNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyAncestors indicates an expected call of GetSocietyAncestors.
+func (mr *MockDbRepoMockRecorder) GetSocietyAncestors(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyAncestors", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyAncestors), ctx, societyUUID)
+}
+
+// GetSocietyChannels mocks base method.
+func (m *MockDbRepo) GetSocietyChannels(ctx context.Context, societyUUID, peerUUID string) ([]model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyChannels", ctx, societyUUID, peerUUID)
+	ret0, _ := ret[0].([]model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyChannels indicates an expected call of GetSocietyChannels.
+func (mr *MockDbRepoMockRecorder) GetSocietyChannels(ctx, societyUUID, peerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyChannels", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyChannels), ctx, societyUUID, peerUUID)
+}
+
+// GetSocietyChildren mocks base method.
+func (m *MockDbRepo) GetSocietyChildren(ctx context.Context, societyUUID string) ([]model.SocietyNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyChildren", ctx, societyUUID)
+	ret0, _ := ret[0].([]model.SocietyNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyChildren indicates an expected call of GetSocietyChildren.
+func (mr *MockDbRepoMockRecorder) GetSocietyChildren(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyChildren", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyChildren), ctx, societyUUID)
+}
+
+// GetSocietyEvents mocks base method.
+func (m *MockDbRepo) GetSocietyEvents(ctx context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyEvents", ctx, filter)
+	ret0, _ := ret[0].([]model.SocietyEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyEvents indicates an expected call of GetSocietyEvents.
+func (mr *MockDbRepoMockRecorder) GetSocietyEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyEvents", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyEvents), ctx, filter)
+}
+
 // GetSocietyInfo mocks base method.
 func (m *MockDbRepo) GetSocietyInfo(ctx context.Context, societyUUID string) (*model.SocietyInfo, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +558,81 @@ func (mr *MockDbRepoMockRecorder) GetSocietyInfo(ctx, societyUUID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyInfo", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyInfo), ctx, societyUUID)
 }
 
+// GetSocietyInfoForPeer mocks base method.
+func (m *MockDbRepo) GetSocietyInfoForPeer(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyInfoForPeer", ctx, societyUUID, peerUUID)
+	ret0, _ := ret[0].(*model.SocietyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyInfoForPeer indicates an expected call of GetSocietyInfoForPeer.
+func (mr *MockDbRepoMockRecorder) GetSocietyInfoForPeer(ctx, societyUUID, peerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyInfoForPeer", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyInfoForPeer), ctx, societyUUID, peerUUID)
+}
+
+// GetSocietyPeerState mocks base method.
+func (m *MockDbRepo) GetSocietyPeerState(ctx context.Context, societyUUID, peerUUID string) (*model.SocietyPeerState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyPeerState", ctx, societyUUID, peerUUID)
+	ret0, _ := ret[0].(*model.SocietyPeerState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyPeerState indicates an expected call of GetSocietyPeerState.
+func (mr *MockDbRepoMockRecorder) GetSocietyPeerState(ctx, societyUUID, peerUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyPeerState", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyPeerState), ctx, societyUUID, peerUUID)
+}
+
+// GetSocietyPolls mocks base method.
+func (m *MockDbRepo) GetSocietyPolls(ctx context.Context, filter *model.SocietyPollsFilter) ([]model.Poll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyPolls", ctx, filter)
+	ret0, _ := ret[0].([]model.Poll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyPolls indicates an expected call of GetSocietyPolls.
+func (mr *MockDbRepoMockRecorder) GetSocietyPolls(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyPolls", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyPolls), ctx, filter)
+}
+
+// GetSocietyQuestions mocks base method.
+func (m *MockDbRepo) GetSocietyQuestions(ctx context.Context, societyUUID string) ([]model.SocietyQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyQuestions", ctx, societyUUID)
+	ret0, _ := ret[0].([]model.SocietyQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyQuestions indicates an expected call of GetSocietyQuestions.
+func (mr *MockDbRepoMockRecorder) GetSocietyQuestions(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyQuestions", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyQuestions), ctx, societyUUID)
+}
+
+// GetSocietyRules mocks base method.
+func (m *MockDbRepo) GetSocietyRules(ctx context.Context, societyUUID string) (*model.SocietyRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocietyRules", ctx, societyUUID)
+	ret0, _ := ret[0].(*model.SocietyRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocietyRules indicates an expected call of GetSocietyRules.
+func (mr *MockDbRepoMockRecorder) GetSocietyRules(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocietyRules", reflect.TypeOf((*MockDbRepo)(nil).GetSocietyRules), ctx, societyUUID)
+}
+
 // GetTags mocks base method.
 func (m *MockDbRepo) GetTags(ctx context.Context, societyUUID string) ([]int64, error) {
 	m.ctrl.T.Helper()
@@ -199,19 +648,49 @@ func (mr *MockDbRepoMockRecorder) GetTags(ctx, societyUUID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockDbRepo)(nil).GetTags), ctx, societyUUID)
 }
 
+// GetUpcomingEvents mocks base method.
+func (m *MockDbRepo) GetUpcomingEvents(ctx context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpcomingEvents", ctx, filter)
+	ret0, _ := ret[0].([]model.SocietyEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpcomingEvents indicates an expected call of GetUpcomingEvents.
+func (mr *MockDbRepoMockRecorder) GetUpcomingEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpcomingEvents", reflect.TypeOf((*MockDbRepo)(nil).GetUpcomingEvents), ctx, filter)
+}
+
 // GetUserSocieties mocks base method.
-func (m *MockDbRepo) GetUserSocieties(ctx context.Context, limit, offset uint64, userUUID string) ([]string, error) {
+func (m *MockDbRepo) GetUserSocieties(ctx context.Context, filter *model.UserSocietiesFilter) ([]model.SocietyWithOffsetData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSocieties", ctx, limit, offset, userUUID)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "GetUserSocieties", ctx, filter)
+	ret0, _ := ret[0].([]model.SocietyWithOffsetData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserSocieties indicates an expected call of GetUserSocieties.
-func (mr *MockDbRepoMockRecorder) GetUserSocieties(ctx, limit, offset, userUUID interface{}) *gomock.Call {
+func (mr *MockDbRepoMockRecorder) GetUserSocieties(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSocieties", reflect.TypeOf((*MockDbRepo)(nil).GetUserSocieties), ctx, limit, offset, userUUID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSocieties", reflect.TypeOf((*MockDbRepo)(nil).GetUserSocieties), ctx, filter)
+}
+
+// IsFormatExists mocks base method.
+func (m *MockDbRepo) IsFormatExists(ctx context.Context, formatID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFormatExists", ctx, formatID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFormatExists indicates an expected call of IsFormatExists.
+func (mr *MockDbRepoMockRecorder) IsFormatExists(ctx, formatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFormatExists", reflect.TypeOf((*MockDbRepo)(nil).IsFormatExists), ctx, formatID)
 }
 
 // IsOwnerAdminModerator mocks base method.
@@ -229,60 +708,260 @@ func (mr *MockDbRepoMockRecorder) IsOwnerAdminModerator(ctx, peerUUID, societyUU
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwnerAdminModerator", reflect.TypeOf((*MockDbRepo)(nil).IsOwnerAdminModerator), ctx, peerUUID, societyUUID)
 }
 
+// IsPostPermissionExists mocks base method.
+func (m *MockDbRepo) IsPostPermissionExists(ctx context.Context, postPermissionID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPostPermissionExists", ctx, postPermissionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPostPermissionExists indicates an expected call of IsPostPermissionExists.
+func (mr *MockDbRepoMockRecorder) IsPostPermissionExists(ctx, postPermissionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPostPermissionExists", reflect.TypeOf((*MockDbRepo)(nil).IsPostPermissionExists), ctx, postPermissionID)
+}
+
+// LeaveWaitlist mocks base method.
+func (m *MockDbRepo) LeaveWaitlist(ctx context.Context, uuid, societyUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveWaitlist", ctx, uuid, societyUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveWaitlist indicates an expected call of LeaveWaitlist.
+func (mr *MockDbRepoMockRecorder) LeaveWaitlist(ctx, uuid, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveWaitlist", reflect.TypeOf((*MockDbRepo)(nil).LeaveWaitlist), ctx, uuid, societyUUID)
+}
+
+// RemoveAnnouncement mocks base method.
+func (m *MockDbRepo) RemoveAnnouncement(ctx context.Context, societyUUID string, announcementID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAnnouncement", ctx, societyUUID, announcementID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAnnouncement indicates an expected call of RemoveAnnouncement.
+func (mr *MockDbRepoMockRecorder) RemoveAnnouncement(ctx, societyUUID, announcementID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAnnouncement", reflect.TypeOf((*MockDbRepo)(nil).RemoveAnnouncement), ctx, societyUUID, announcementID)
+}
+
+// RemoveChannel mocks base method.
+func (m *MockDbRepo) RemoveChannel(ctx context.Context, channelUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveChannel", ctx, channelUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveChannel indicates an expected call of RemoveChannel.
+func (mr *MockDbRepoMockRecorder) RemoveChannel(ctx, channelUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChannel", reflect.TypeOf((*MockDbRepo)(nil).RemoveChannel), ctx, channelUUID)
+}
+
+// RemoveChannelMember mocks base method.
+func (m *MockDbRepo) RemoveChannelMember(ctx context.Context, channelUUID, userUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveChannelMember", ctx, channelUUID, userUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveChannelMember indicates an expected call of RemoveChannelMember.
+func (mr *MockDbRepoMockRecorder) RemoveChannelMember(ctx, channelUUID, userUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChannelMember", reflect.TypeOf((*MockDbRepo)(nil).RemoveChannelMember), ctx, channelUUID, userUUID)
+}
+
 // RemoveMembersRequestEntry mocks base method.
-func (m *MockDbRepo) RemoveMembersRequestEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (m *MockDbRepo) RemoveMembersRequestEntry(ctx context.Context, societyUUID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveMembersRequestEntry", ctx, societyUUID, tx)
+	ret := m.ctrl.Call(m, "RemoveMembersRequestEntry", ctx, societyUUID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveMembersRequestEntry indicates an expected call of RemoveMembersRequestEntry.
-func (mr *MockDbRepoMockRecorder) RemoveMembersRequestEntry(ctx, societyUUID, tx interface{}) *gomock.Call {
+func (mr *MockDbRepoMockRecorder) RemoveMembersRequestEntry(ctx, societyUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMembersRequestEntry", reflect.TypeOf((*MockDbRepo)(nil).RemoveMembersRequestEntry), ctx, societyUUID, tx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMembersRequestEntry", reflect.TypeOf((*MockDbRepo)(nil).RemoveMembersRequestEntry), ctx, societyUUID)
 }
 
 // RemoveSociety mocks base method.
-func (m *MockDbRepo) RemoveSociety(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (m *MockDbRepo) RemoveSociety(ctx context.Context, societyUUID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveSociety", ctx, societyUUID, tx)
+	ret := m.ctrl.Call(m, "RemoveSociety", ctx, societyUUID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveSociety indicates an expected call of RemoveSociety.
-func (mr *MockDbRepoMockRecorder) RemoveSociety(ctx, societyUUID, tx interface{}) *gomock.Call {
+func (mr *MockDbRepoMockRecorder) RemoveSociety(ctx, societyUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSociety", reflect.TypeOf((*MockDbRepo)(nil).RemoveSociety), ctx, societyUUID, tx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSociety", reflect.TypeOf((*MockDbRepo)(nil).RemoveSociety), ctx, societyUUID)
 }
 
 // RemoveSocietyHasTagsEntry mocks base method.
-func (m *MockDbRepo) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (m *MockDbRepo) RemoveSocietyHasTagsEntry(ctx context.Context, societyUUID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveSocietyHasTagsEntry", ctx, societyUUID, tx)
+	ret := m.ctrl.Call(m, "RemoveSocietyHasTagsEntry", ctx, societyUUID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveSocietyHasTagsEntry indicates an expected call of RemoveSocietyHasTagsEntry.
-func (mr *MockDbRepoMockRecorder) RemoveSocietyHasTagsEntry(ctx, societyUUID, tx interface{}) *gomock.Call {
+func (mr *MockDbRepoMockRecorder) RemoveSocietyHasTagsEntry(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSocietyHasTagsEntry", reflect.TypeOf((*MockDbRepo)(nil).RemoveSocietyHasTagsEntry), ctx, societyUUID)
+}
+
+// RemoveSocietyMember mocks base method.
+func (m *MockDbRepo) RemoveSocietyMember(ctx context.Context, uuid, societyUUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSocietyMember", ctx, uuid, societyUUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSocietyMember indicates an expected call of RemoveSocietyMember.
+func (mr *MockDbRepoMockRecorder) RemoveSocietyMember(ctx, uuid, societyUUID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSocietyHasTagsEntry", reflect.TypeOf((*MockDbRepo)(nil).RemoveSocietyHasTagsEntry), ctx, societyUUID, tx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSocietyMember", reflect.TypeOf((*MockDbRepo)(nil).RemoveSocietyMember), ctx, uuid, societyUUID)
 }
 
 // RemoveSocietyMembersEntry mocks base method.
-func (m *MockDbRepo) RemoveSocietyMembersEntry(ctx context.Context, societyUUID string, tx *sqlx.Tx) error {
+func (m *MockDbRepo) RemoveSocietyMembersEntry(ctx context.Context, societyUUID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveSocietyMembersEntry", ctx, societyUUID, tx)
+	ret := m.ctrl.Call(m, "RemoveSocietyMembersEntry", ctx, societyUUID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveSocietyMembersEntry indicates an expected call of RemoveSocietyMembersEntry.
-func (mr *MockDbRepoMockRecorder) RemoveSocietyMembersEntry(ctx, societyUUID, tx interface{}) *gomock.Call {
+func (mr *MockDbRepoMockRecorder) RemoveSocietyMembersEntry(ctx, societyUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSocietyMembersEntry", reflect.TypeOf((*MockDbRepo)(nil).RemoveSocietyMembersEntry), ctx, societyUUID)
+}
+
+// SetAutoApprovalRules mocks base method.
+func (m *MockDbRepo) SetAutoApprovalRules(ctx context.Context, societyUUID string, rules []model.AutoApprovalRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAutoApprovalRules", ctx, societyUUID, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAutoApprovalRules indicates an expected call of SetAutoApprovalRules.
+func (mr *MockDbRepoMockRecorder) SetAutoApprovalRules(ctx, societyUUID, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAutoApprovalRules", reflect.TypeOf((*MockDbRepo)(nil).SetAutoApprovalRules), ctx, societyUUID, rules)
+}
+
+// SetEventRSVP mocks base method.
+func (m *MockDbRepo) SetEventRSVP(ctx context.Context, eventUUID, userUUID string, going bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEventRSVP", ctx, eventUUID, userUUID, going)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEventRSVP indicates an expected call of SetEventRSVP.
+func (mr *MockDbRepoMockRecorder) SetEventRSVP(ctx, eventUUID, userUUID, going interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventRSVP", reflect.TypeOf((*MockDbRepo)(nil).SetEventRSVP), ctx, eventUUID, userUUID, going)
+}
+
+// SetMaxMembers mocks base method.
+func (m *MockDbRepo) SetMaxMembers(ctx context.Context, societyUUID string, maxMembers int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaxMembers", ctx, societyUUID, maxMembers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMaxMembers indicates an expected call of SetMaxMembers.
+func (mr *MockDbRepoMockRecorder) SetMaxMembers(ctx, societyUUID, maxMembers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaxMembers", reflect.TypeOf((*MockDbRepo)(nil).SetMaxMembers), ctx, societyUUID, maxMembers)
+}
+
+// SetNotificationSettings mocks base method.
+func (m *MockDbRepo) SetNotificationSettings(ctx context.Context, settings *model.NotificationSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationSettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationSettings indicates an expected call of SetNotificationSettings.
+func (mr *MockDbRepoMockRecorder) SetNotificationSettings(ctx, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationSettings", reflect.TypeOf((*MockDbRepo)(nil).SetNotificationSettings), ctx, settings)
+}
+
+// SetRequestTTL mocks base method.
+func (m *MockDbRepo) SetRequestTTL(ctx context.Context, societyUUID string, ttlHours int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRequestTTL", ctx, societyUUID, ttlHours)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRequestTTL indicates an expected call of SetRequestTTL.
+func (mr *MockDbRepoMockRecorder) SetRequestTTL(ctx, societyUUID, ttlHours interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSocietyMembersEntry", reflect.TypeOf((*MockDbRepo)(nil).RemoveSocietyMembersEntry), ctx, societyUUID, tx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequestTTL", reflect.TypeOf((*MockDbRepo)(nil).SetRequestTTL), ctx, societyUUID, ttlHours)
+}
+
+// SetSocietyParent mocks base method.
+func (m *MockDbRepo) SetSocietyParent(ctx context.Context, societyUUID, parentUUID string, inheritModeration bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSocietyParent", ctx, societyUUID, parentUUID, inheritModeration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSocietyParent indicates an expected call of SetSocietyParent.
+func (mr *MockDbRepoMockRecorder) SetSocietyParent(ctx, societyUUID, parentUUID, inheritModeration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSocietyParent", reflect.TypeOf((*MockDbRepo)(nil).SetSocietyParent), ctx, societyUUID, parentUUID, inheritModeration)
+}
+
+// SetSocietyQuestions mocks base method.
+func (m *MockDbRepo) SetSocietyQuestions(ctx context.Context, societyUUID string, questions []model.SocietyQuestion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSocietyQuestions", ctx, societyUUID, questions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSocietyQuestions indicates an expected call of SetSocietyQuestions.
+func (mr *MockDbRepoMockRecorder) SetSocietyQuestions(ctx, societyUUID, questions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSocietyQuestions", reflect.TypeOf((*MockDbRepo)(nil).SetSocietyQuestions), ctx, societyUUID, questions)
+}
+
+// SetSocietyRules mocks base method.
+func (m *MockDbRepo) SetSocietyRules(ctx context.Context, societyUUID, authorUUID string, rules []model.SocietyRule) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSocietyRules", ctx, societyUUID, authorUUID, rules)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSocietyRules indicates an expected call of SetSocietyRules.
+func (mr *MockDbRepoMockRecorder) SetSocietyRules(ctx, societyUUID, authorUUID, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSocietyRules", reflect.TypeOf((*MockDbRepo)(nil).SetSocietyRules), ctx, societyUUID, authorUUID, rules)
 }
 
 // UnSubscribeToSociety mocks base method.
@@ -299,6 +978,34 @@ func (mr *MockDbRepoMockRecorder) UnSubscribeToSociety(ctx, uuid, societyUUID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnSubscribeToSociety", reflect.TypeOf((*MockDbRepo)(nil).UnSubscribeToSociety), ctx, uuid, societyUUID)
 }
 
+// UpdateChannel mocks base method.
+func (m *MockDbRepo) UpdateChannel(ctx context.Context, channel *model.Channel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChannel", ctx, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChannel indicates an expected call of UpdateChannel.
+func (mr *MockDbRepoMockRecorder) UpdateChannel(ctx, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannel", reflect.TypeOf((*MockDbRepo)(nil).UpdateChannel), ctx, channel)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockDbRepo) UpdateEvent(ctx context.Context, event *model.SocietyEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockDbRepoMockRecorder) UpdateEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockDbRepo)(nil).UpdateEvent), ctx, event)
+}
+
 // UpdateSociety mocks base method.
 func (m *MockDbRepo) UpdateSociety(ctx context.Context, societyData *society_proto.UpdateSocietyIn) error {
 	m.ctrl.T.Helper()
@@ -312,3 +1019,32 @@ func (mr *MockDbRepoMockRecorder) UpdateSociety(ctx, societyData interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSociety", reflect.TypeOf((*MockDbRepo)(nil).UpdateSociety), ctx, societyData)
 }
+
+// VotePoll mocks base method.
+func (m *MockDbRepo) VotePoll(ctx context.Context, pollUUID, userUUID string, optionIDs []int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VotePoll", ctx, pollUUID, userUUID, optionIDs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VotePoll indicates an expected call of VotePoll.
+func (mr *MockDbRepoMockRecorder) VotePoll(ctx, pollUUID, userUUID, optionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VotePoll", reflect.TypeOf((*MockDbRepo)(nil).VotePoll), ctx, pollUUID, userUUID, optionIDs)
+}
+
+// WithTx mocks base method.
+func (m *MockDbRepo) WithTx(ctx context.Context, fn func(DbRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockDbRepoMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockDbRepo)(nil).WithTx), ctx, fn)
+}
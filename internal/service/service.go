@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	logger_lib "github.com/s21platform/logger-lib"
 
@@ -34,9 +38,18 @@ func (s *Server) CreateSociety(ctx context.Context, in *society.SetSocietyIn) (*
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
-	if in.Name == "" {
-		logger.Error("failed to Name society is empty")
-		return nil, status.Error(codes.InvalidArgument, "name not provided")
+	v := validateSetSocietyIn(in)
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+	if err := s.checkSocietySettings(ctx, v, in.FormatID, in.PostPermissionID); err != nil {
+		logger.Error(fmt.Sprintf("failed to checkSocietySettings from BD: %v", err))
+		return nil, err
+	}
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
 	}
 
 	SocietyData := model.SocietyData{
@@ -64,33 +77,16 @@ func (s *Server) GetSocietyInfo(ctx context.Context, in *society.GetSocietyInfoI
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
-	if in.SocietyUUID == "" {
-		logger.Error("failed to SocietyUUID is empty")
-		return nil, status.Error(codes.InvalidArgument, "societyUUID not provided")
-	}
-
-	societyInfo, err := s.dbR.GetSocietyInfo(ctx, in.SocietyUUID)
-
-	if err != nil {
-		logger.Error("failed to GetSocietyInfo from BD")
+	if err := validateGetSocietyInfoIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
 		return nil, err
 	}
 
-	if !societyInfo.Description.Valid {
-		societyInfo.Description.String = ""
-	}
-
-	count, err := s.dbR.CountSubscribe(ctx, in.SocietyUUID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get count of subscribers: %w", err)
-	}
-	societyInfo.CountSubscribe = count
-
-	getTag, err := s.dbR.GetTags(ctx, in.SocietyUUID)
+	societyInfo, err := s.dbR.GetSocietyInfoForPeer(ctx, in.SocietyUUID, uuid)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tags: %w", err)
+		logger.Error("failed to GetSocietyInfoForPeer from BD")
+		return nil, err
 	}
-	societyInfo.TagsID = getTag
 
 	var tags []*society.TagsID
 	for _, tag := range societyInfo.TagsID {
@@ -102,32 +98,38 @@ func (s *Server) GetSocietyInfo(ctx context.Context, in *society.GetSocietyInfoI
 		description = societyInfo.Description.String
 	}
 
-	canEditSocietyInt, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
-	canEditSociety := false
-	if err != nil {
-		logger.Error("failed to IsOwnerAdminModerator from BD")
-		return nil, err
-	}
-	if canEditSocietyInt <= 3 && canEditSocietyInt >= 1 {
-		canEditSociety = true
-	}
-	societyInfo.CanEditSociety = canEditSociety
+	// право редактирования считается как в UpdateSociety, с модерацией, унаследованной от предков
+	societyInfo.CanEditSociety = canEditSociety(societyInfo.EditorRole)
 
 	out := &society.GetSocietyInfoOut{
-		Name:           societyInfo.Name,
-		Description:    description,
-		OwnerUUID:      societyInfo.OwnerUUID,
-		PhotoURL:       societyInfo.PhotoURL,
-		FormatID:       societyInfo.FormatID,
-		PostPermission: societyInfo.PostPermission,
-		IsSearch:       societyInfo.IsSearch,
-		CountSubscribe: societyInfo.CountSubscribe,
-		TagsID:         tags,
-		CanEditSociety: canEditSociety,
+		Name:             societyInfo.Name,
+		Description:      description,
+		OwnerUUID:        societyInfo.OwnerUUID,
+		PhotoURL:         societyInfo.PhotoURL,
+		FormatID:         societyInfo.FormatID,
+		PostPermission:   societyInfo.PostPermission,
+		IsSearch:         societyInfo.IsSearch,
+		CountSubscribe:   societyInfo.CountSubscribe,
+		TagsID:           tags,
+		CanEditSociety:   societyInfo.CanEditSociety,
+		IsMember:         societyInfo.IsMember,
+		IsRequestPending: societyInfo.IsPending,
+		Announcements:    make([]*society.Announcement, 0, len(societyInfo.Announcements)),
+		MaxMembers:       societyInfo.MaxMembers,
+		IsWaitlisted:     societyInfo.WaitlistPosition > 0,
+		WaitlistPosition: societyInfo.WaitlistPosition,
+	}
+	for _, announcement := range societyInfo.Announcements {
+		out.Announcements = append(out.Announcements, announcementToProto(announcement))
 	}
 	return out, nil
 }
 
+// canEditSociety — роль из IsOwnerAdminModerator, с которой можно менять сообщество: владелец, админ или модератор
+func canEditSociety(role int) bool {
+	return role >= 1 && role <= 3
+}
+
 func (s *Server) UpdateSociety(ctx context.Context, in *society.UpdateSocietyIn) (*society.EmptySociety, error) {
 	logger := logger_lib.FromContext(ctx, config.KeyLogger)
 	logger.AddFuncName("UpdateSociety")
@@ -138,14 +140,10 @@ func (s *Server) UpdateSociety(ctx context.Context, in *society.UpdateSocietyIn)
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
-	if in.SocietyUUID == "" {
-		logger.Error("failed to SocietyUUID is empty")
-		return nil, status.Error(codes.InvalidArgument, "societyUUID not provided")
-	}
-
-	if in.Name == "" {
-		logger.Error("failed to Name society is empty")
-		return nil, status.Error(codes.InvalidArgument, "failed to name not provided")
+	v := validateUpdateSocietyIn(in)
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
 	}
 
 	isAllowed, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
@@ -159,11 +157,20 @@ func (s *Server) UpdateSociety(ctx context.Context, in *society.UpdateSocietyIn)
 		return nil, status.Error(codes.InvalidArgument, "failed to IsOwnerAdminModerator from BD")
 	}
 
-	if isAllowed != 1 && isAllowed != 2 && isAllowed != 3 {
+	if !canEditSociety(isAllowed) {
 		logger.Error("failed to IsOwnerAdminModerator from BD")
 		return nil, status.Error(codes.InvalidArgument, "failed to peer is not Owner, Admin or Moderator")
 	}
 
+	if err := s.checkSocietySettings(ctx, v, in.FormatID, in.PostPermission); err != nil {
+		logger.Error(fmt.Sprintf("failed to checkSocietySettings from BD: %v", err))
+		return nil, err
+	}
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
 	err = s.dbR.UpdateSociety(ctx, in)
 
 	if err != nil {
@@ -184,6 +191,11 @@ func (s *Server) RemoveSociety(ctx context.Context, in *society.RemoveSocietyIn)
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
+	if err := validateRemoveSocietyIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
 	role, err := s.dbR.GetOwner(ctx, in.SocietyUUID)
 	if err != nil {
 		logger.Error("failed to IsOwnerAdminModerator from BD")
@@ -194,39 +206,26 @@ func (s *Server) RemoveSociety(ctx context.Context, in *society.RemoveSocietyIn)
 		return nil, status.Error(codes.InvalidArgument, "failed to CheckRole from BD: the user does not have the rights to delete the community.")
 	}
 
-	tx, err := s.dbR.Conn().Beginx()
-	if err != nil {
-		logger.Error("failed to begin transaction")
-		return nil, status.Error(codes.Internal, "failed to start transaction")
-	}
-
-	err = s.dbR.RemoveSocietyHasTagsEntry(ctx, in.SocietyUUID, tx)
-	if err != nil {
-		_ = tx.Rollback()
-		logger.Error("failed to RemoveSocietyHasTagsEntry from BD")
-		return nil, err
-	}
-	err = s.dbR.RemoveMembersRequestEntry(ctx, in.SocietyUUID, tx)
-	if err != nil {
-		_ = tx.Rollback()
-		logger.Error("failed to RemoveMembersRequestEntry from BD")
-		return nil, err
-	}
-	err = s.dbR.RemoveSocietyMembersEntry(ctx, in.SocietyUUID, tx)
-	if err != nil {
-		_ = tx.Rollback()
-		logger.Error("failed to RemoveSocietyMembersEntry from BD")
-		return nil, err
-	}
-	err = s.dbR.RemoveSociety(ctx, in.SocietyUUID, tx)
+	err = s.dbR.WithTx(ctx, func(repo DbRepo) error {
+		if err := repo.RemoveSocietyHasTagsEntry(ctx, in.SocietyUUID); err != nil {
+			logger.Error("failed to RemoveSocietyHasTagsEntry from BD")
+			return err
+		}
+		if err := repo.RemoveMembersRequestEntry(ctx, in.SocietyUUID); err != nil {
+			logger.Error("failed to RemoveMembersRequestEntry from BD")
+			return err
+		}
+		if err := repo.RemoveSocietyMembersEntry(ctx, in.SocietyUUID); err != nil {
+			logger.Error("failed to RemoveSocietyMembersEntry from BD")
+			return err
+		}
+		if err := repo.RemoveSociety(ctx, in.SocietyUUID); err != nil {
+			logger.Error("failed to RemoveSociety from BD")
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		_ = tx.Rollback()
-		logger.Error("failed to RemoveSociety from BD")
-		return nil, err
-	}
-
-	if err := tx.Commit(); err != nil {
-		logger.Error("failed to commit transaction")
 		return nil, err
 	}
 
@@ -243,6 +242,11 @@ func (s *Server) SubscribeToSociety(ctx context.Context, in *society.SubscribeTo
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
+	if err := validateSubscribeToSocietyIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
 	format, err := s.dbR.GetFormatSociety(ctx, in.SocietyUUID)
 	if err != nil {
 		logger.Error("failed to GetFormatSociety from BD")
@@ -250,13 +254,64 @@ func (s *Server) SubscribeToSociety(ctx context.Context, in *society.SubscribeTo
 	}
 
 	if format != 1 {
-		err = s.dbR.AddMembersRequests(ctx, uuid, in.SocietyUUID)
+		request := &model.MembersRequest{UserUUID: uuid, SocietyUUID: in.SocietyUUID}
+		// в закрытое сообщество заявку подают с согласием на текущую версию правил и ответами на анкету
+		if format == 2 {
+			rules, err := s.dbR.GetSocietyRules(ctx, in.SocietyUUID)
+			if err != nil {
+				logger.Error("failed to GetSocietyRules from BD")
+				return nil, err
+			}
+			if len(rules.Items) > 0 {
+				if in.AcceptedRulesVersion != rules.Version {
+					logger.Error("failed to peer did not accept current rules")
+					return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("current rules version %d must be accepted", rules.Version))
+				}
+				request.AcceptedRulesVersion = rules.Version
+			}
+
+			questions, err := s.dbR.GetSocietyQuestions(ctx, in.SocietyUUID)
+			if err != nil {
+				logger.Error("failed to GetSocietyQuestions from BD")
+				return nil, err
+			}
+			v, answers := validateAnswers(questions, in.Answers)
+			if err := v.err(); err != nil {
+				logger.Error(fmt.Sprintf("failed to validate answers: %v", err))
+				return nil, err
+			}
+			request.Answers = answers
+		}
+
+		approved, err := s.autoApprove(ctx, request, in.InviteCode)
+		if errors.Is(err, model.ErrAlreadyMember) {
+			logger.Error("failed to user is already a member of the society")
+			return nil, status.Error(codes.AlreadyExists, "user is already a member of the society")
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to autoApprove: %v", err))
+			return nil, err
+		}
+		if approved {
+			return &society.EmptySociety{}, nil
+		}
+
+		err = s.dbR.AddMembersRequests(ctx, request)
+		if errors.Is(err, model.ErrAlreadyMember) {
+			logger.Error("failed to user is already a member of the society")
+			return nil, status.Error(codes.AlreadyExists, "user is already a member of the society")
+		}
 		if err != nil {
 			logger.Error("failed to AddMembersRequests from BD")
 			return nil, err
 		}
 	} else {
-		err = s.dbR.AddSocietyMembers(ctx, uuid, in.SocietyUUID)
+		// в заполненное сообщество пользователь встаёт в очередь, место видно в GetSocietyInfo
+		_, err = s.dbR.AddSocietyMembers(ctx, uuid, in.SocietyUUID)
+		if errors.Is(err, model.ErrAlreadyMember) {
+			logger.Error("failed to user is already a member of the society")
+			return nil, status.Error(codes.AlreadyExists, "user is already a member of the society")
+		}
 		if err != nil {
 			logger.Error("failed to AddSocietyMembers from BD")
 			return nil, err
@@ -276,7 +331,20 @@ func (s *Server) UnSubscribeToSociety(ctx context.Context, in *society.UnSubscri
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
+	if err := validateUnSubscribeToSocietyIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
 	_, err := s.dbR.GetRoleSocietyMembers(ctx, uuid, in.SocietyUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// не участник может стоять в очереди на вступление
+		if err := s.dbR.LeaveWaitlist(ctx, uuid, in.SocietyUUID); err != nil {
+			logger.Error("failed to LeaveWaitlist from BD")
+			return nil, err
+		}
+		return &society.EmptySociety{}, nil
+	}
 	if err != nil {
 		logger.Error("failed to GetRoleSocietyMembers from BD")
 		return nil, err
@@ -295,47 +363,1988 @@ func (s *Server) GetSocietyForUserWithOffset(ctx context.Context, in *society.Ge
 	logger.AddFuncName("GetSocietyForUserWithOffset")
 
 	uuid, ok := ctx.Value(config.KeyUUID).(string)
-	_ = uuid
 	if !ok {
 		logger.Error("failed to not found UUID in context")
 		return nil, status.Error(codes.Internal, "uuid not found in context")
 	}
 
-	if in.Offset < 0 || in.Limit < 0 {
-		logger.Error(fmt.Sprintf("invalid value: must be >= 0, got offset = %d, limit = %d", in.Offset, in.Limit))
-		return nil, status.Errorf(codes.InvalidArgument, "invalid value: must be >= 0, got offset = %d, limit = %d", in.Offset, in.Limit)
+	if err := validateGetSocietyForUserWithOffsetIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	filter := model.UserSocietiesFilter{
+		UserUUID:   in.UserUUID,
+		CallerUUID: uuid,
+		Limit:      uint64(in.Limit),
+		Offset:     uint64(in.Offset),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultSocietiesPage
+	}
+	if in.Cursor != "" {
+		// валидатор уже проверил курсор, offset при курсорной пагинации игнорируется
+		filter.AfterID, _ = decodeCursor(in.Cursor)
 	}
 
-	groups, err := s.dbR.GetUserSocieties(ctx, uint64(in.Limit), uint64(in.Offset), in.UserUUID)
+	societies, err := s.dbR.GetUserSocieties(ctx, &filter)
 	if err != nil {
 		logger.Error("failed to GetUserSocieties from BD")
 		return nil, err
 	}
 
-	infoGroup, err := s.dbR.GetInfoSociety(ctx, groups)
+	total, err := s.dbR.CountUserSocieties(ctx, in.UserUUID)
 	if err != nil {
-		logger.Error("failed to GetInfoSociety from BD")
+		logger.Error("failed to CountUserSocieties from BD")
 		return nil, err
 	}
 
 	out := society.GetSocietyForUserWithOffsetOut{
-		Societies: make([]*society.Society, len(infoGroup)),
-		Total:     int64(len(infoGroup)),
+		Societies: make([]*society.Society, len(societies)),
+		Total:     total,
 	}
-	for i := 0; i < len(infoGroup); i++ {
+	for i := 0; i < len(societies); i++ {
 		tmp := &society.Society{
-			SocietyUUID: infoGroup[i].SocietyUUID,
-			Name:        infoGroup[i].Name,
-			PhotoURL:    infoGroup[i].PhotoURL,
-			IsMember:    true,
-			FormatId:    infoGroup[i].FormatId,
+			SocietyUUID: societies[i].SocietyUUID,
+			Name:        societies[i].Name,
+			PhotoURL:    societies[i].PhotoURL,
+			IsMember:    societies[i].IsMember,
+			FormatId:    societies[i].FormatId,
 		}
 		out.Societies[i] = tmp
 	}
+	if len(societies) > 0 && uint64(len(societies)) == filter.Limit {
+		out.NextCursor = encodeCursor(societies[len(societies)-1].MemberID)
+	}
 
 	return &out, nil
 }
 
+func (s *Server) GetSocietiesByIDs(ctx context.Context, in *society.GetSocietiesByIDsIn) (*society.GetSocietiesByIDsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietiesByIDs")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetSocietiesByIDsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	unique := make(map[string]struct{}, len(in.SocietyUUIDs))
+	societyUUIDs := make([]string, 0, len(in.SocietyUUIDs))
+	for _, societyUUID := range in.SocietyUUIDs {
+		if _, ok := unique[societyUUID]; ok {
+			continue
+		}
+		unique[societyUUID] = struct{}{}
+		societyUUIDs = append(societyUUIDs, societyUUID)
+	}
+
+	summaries, err := s.dbR.GetSocietiesByIDs(ctx, societyUUIDs, uuid)
+	if err != nil {
+		logger.Error("failed to GetSocietiesByIDs from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietiesByIDsOut{
+		Societies: make(map[string]*society.SocietySummary, len(summaries)),
+	}
+	for _, summary := range summaries {
+		out.Societies[summary.SocietyUUID] = &society.SocietySummary{
+			SocietyUUID:    summary.SocietyUUID,
+			Name:           summary.Name,
+			PhotoURL:       summary.PhotoURL,
+			FormatID:       summary.FormatID,
+			CountSubscribe: summary.CountSubscribe,
+			Role:           summary.Role,
+			ParentUUID:     summary.ParentUUID,
+			ParentName:     summary.ParentName,
+		}
+	}
+
+	return out, nil
+}
+
+func (s *Server) GetSocietyMemberCounts(ctx context.Context, in *society.GetSocietyMemberCountsIn) (*society.GetSocietyMemberCountsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyMemberCounts")
+
+	if err := validateGetSocietyMemberCountsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	total, err := s.dbR.CountSubscribe(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to CountSubscribe from BD")
+		return nil, err
+	}
+
+	counters, err := s.dbR.GetMemberCounters(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetMemberCounters from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyMemberCountsOut{
+		Total:           total,
+		ByRole:          make(map[int64]int64),
+		ByPaymentStatus: make(map[int64]int64),
+	}
+	for _, counter := range counters {
+		out.ByRole[counter.Role] += counter.Count
+		out.ByPaymentStatus[counter.PaymentStatus] += counter.Count
+	}
+
+	return out, nil
+}
+
+func (s *Server) GetSocietyStats(ctx context.Context, in *society.GetSocietyStatsIn) (*society.GetSocietyStatsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyStats")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetSocietyStatsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	role, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return nil, err
+	}
+	if role != 1 && role != 2 {
+		logger.Error("failed to peer is not Owner or Admin")
+		return nil, status.Error(codes.PermissionDenied, "statistics are available to owners and admins only")
+	}
+
+	days := in.Days
+	if days == 0 {
+		days = defaultStatsDays
+	}
+	since := time.Now().UTC().AddDate(0, 0, -int(days-1))
+
+	growth, err := s.dbR.GetMembershipGrowth(ctx, in.SocietyUUID, since)
+	if err != nil {
+		logger.Error("failed to GetMembershipGrowth from BD")
+		return nil, err
+	}
+
+	requests, err := s.dbR.GetRequestStats(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetRequestStats from BD")
+		return nil, err
+	}
+
+	counters, err := s.dbR.GetMemberCounters(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetMemberCounters from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyStatsOut{
+		Growth:                    make([]*society.DailyMembership, 0, len(growth)),
+		PendingRequests:           requests.Pending,
+		AvgApprovalLatencySeconds: int64(requests.AvgApprovalSeconds),
+		ByRole:                    make(map[int64]int64),
+	}
+	for _, day := range growth {
+		out.Growth = append(out.Growth, &society.DailyMembership{
+			Date:   day.Day.Format(time.DateOnly),
+			Joins:  day.Joins,
+			Leaves: day.Leaves,
+		})
+	}
+	if decided := requests.Approved + requests.Rejected; decided > 0 {
+		out.ApprovalRate = float64(requests.Approved) / float64(decided)
+	}
+	for _, counter := range counters {
+		out.ByRole[counter.Role] += counter.Count
+	}
+
+	return out, nil
+}
+
+func (s *Server) GetMembershipTimeline(ctx context.Context, in *society.GetMembershipTimelineIn) (*society.GetMembershipTimelineOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetMembershipTimeline")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetMembershipTimelineIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	// свою историю видит сам пользователь, историю сообщества — владелец, админы и модераторы
+	if in.UserUUID != "" && in.UserUUID != uuid {
+		logger.Error("failed to peer requested another user's timeline")
+		return nil, status.Error(codes.PermissionDenied, "only your own membership timeline is available")
+	}
+	if in.SocietyUUID != "" {
+		role, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
+		if err != nil {
+			logger.Error("failed to IsOwnerAdminModerator from BD")
+			return nil, err
+		}
+		if role < 1 || role > 3 {
+			logger.Error("failed to peer is not Owner, Admin or Moderator")
+			return nil, status.Error(codes.PermissionDenied, "society timeline is available to owners, admins and moderators only")
+		}
+	}
+
+	filter := model.MembershipTimelineFilter{
+		UserUUID:    in.UserUUID,
+		SocietyUUID: in.SocietyUUID,
+		Limit:       uint64(in.Limit),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultTimelineLimit
+	}
+	if in.Cursor != "" {
+		filter.AfterID, _ = decodeCursor(in.Cursor)
+	}
+
+	events, err := s.dbR.GetMembershipTimeline(ctx, &filter)
+	if err != nil {
+		logger.Error("failed to GetMembershipTimeline from BD")
+		return nil, err
+	}
+
+	out := &society.GetMembershipTimelineOut{
+		Events: make([]*society.MembershipEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		out.Events = append(out.Events, &society.MembershipEvent{
+			EventID:               event.ID,
+			SocietyUUID:           event.SocietyUUID,
+			UserUUID:              event.UserUUID,
+			EventType:             event.EventType,
+			Role:                  event.Role,
+			PreviousRole:          event.PreviousRole,
+			PaymentStatus:         event.PaymentStatus,
+			PreviousPaymentStatus: event.PreviousPaymentStatus,
+			ActorUUID:             event.ActorUUID,
+			CreatedAt:             event.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if len(events) > 0 && uint64(len(events)) == filter.Limit {
+		out.NextCursor = encodeCursor(events[len(events)-1].ID)
+	}
+
+	return out, nil
+}
+
+func (s *Server) SetSocietyRules(ctx context.Context, in *society.SetSocietyRulesIn) (*society.SetSocietyRulesOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetSocietyRules")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateSetSocietyRulesIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	role, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return nil, err
+	}
+	if role != 1 && role != 2 {
+		logger.Error("failed to peer is not Owner or Admin")
+		return nil, status.Error(codes.PermissionDenied, "rules are managed by owners and admins only")
+	}
+
+	rules := make([]model.SocietyRule, 0, len(in.Rules))
+	for _, rule := range in.Rules {
+		rules = append(rules, model.SocietyRule{Title: rule.Title, Body: rule.Body})
+	}
+
+	version, err := s.dbR.SetSocietyRules(ctx, in.SocietyUUID, uuid, rules)
+	if err != nil {
+		logger.Error("failed to SetSocietyRules from BD")
+		return nil, err
+	}
+
+	return &society.SetSocietyRulesOut{Version: version}, nil
+}
+
+func (s *Server) GetSocietyRules(ctx context.Context, in *society.GetSocietyRulesIn) (*society.GetSocietyRulesOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyRules")
+
+	if err := validateGetSocietyRulesIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	rules, err := s.dbR.GetSocietyRules(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetSocietyRules from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyRulesOut{
+		Version: rules.Version,
+		Rules:   make([]*society.SocietyRule, 0, len(rules.Items)),
+	}
+	for _, rule := range rules.Items {
+		out.Rules = append(out.Rules, &society.SocietyRule{Title: rule.Title, Body: rule.Body})
+	}
+
+	return out, nil
+}
+
+func (s *Server) SetSocietyQuestions(ctx context.Context, in *society.SetSocietyQuestionsIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetSocietyQuestions")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateSetSocietyQuestionsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	role, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return nil, err
+	}
+	if role != 1 {
+		logger.Error("failed to peer is not Owner")
+		return nil, status.Error(codes.PermissionDenied, "application questions are managed by the owner only")
+	}
+
+	questions := make([]model.SocietyQuestion, 0, len(in.Questions))
+	for _, question := range in.Questions {
+		questions = append(questions, model.SocietyQuestion{
+			Kind:       question.Kind,
+			Prompt:     question.Prompt,
+			Options:    question.Options,
+			IsRequired: question.IsRequired,
+		})
+	}
+
+	if err := s.dbR.SetSocietyQuestions(ctx, in.SocietyUUID, questions); err != nil {
+		logger.Error("failed to SetSocietyQuestions from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+func (s *Server) GetSocietyQuestions(ctx context.Context, in *society.GetSocietyQuestionsIn) (*society.GetSocietyQuestionsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyQuestions")
+
+	if err := validateGetSocietyQuestionsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	questions, err := s.dbR.GetSocietyQuestions(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetSocietyQuestions from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyQuestionsOut{Questions: make([]*society.SocietyQuestion, 0, len(questions))}
+	for _, question := range questions {
+		out.Questions = append(out.Questions, &society.SocietyQuestion{
+			QuestionID: question.ID,
+			Kind:       question.Kind,
+			Prompt:     question.Prompt,
+			Options:    question.Options,
+			IsRequired: question.IsRequired,
+		})
+	}
+
+	return out, nil
+}
+
+func (s *Server) GetSocietyRequests(ctx context.Context, in *society.GetSocietyRequestsIn) (*society.GetSocietyRequestsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyRequests")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetSocietyRequestsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	filter := model.PendingRequestsFilter{
+		SocietyUUID: in.SocietyUUID,
+		Limit:       uint64(in.Limit),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultRequestsLimit
+	}
+	if in.Cursor != "" {
+		filter.AfterID, _ = decodeCursor(in.Cursor)
+	}
+
+	requests, err := s.dbR.GetPendingRequests(ctx, &filter)
+	if err != nil {
+		logger.Error("failed to GetPendingRequests from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyRequestsOut{Requests: make([]*society.MembersRequest, 0, len(requests))}
+	for _, request := range requests {
+		answers := make([]*society.RequestAnswer, 0, len(request.Answers))
+		for _, answer := range request.Answers {
+			answers = append(answers, &society.RequestAnswer{
+				QuestionID: answer.QuestionID,
+				Prompt:     answer.Prompt,
+				Values:     answer.Values,
+			})
+		}
+		out.Requests = append(out.Requests, &society.MembersRequest{
+			RequestID:            request.ID,
+			UserUUID:             request.UserUUID,
+			AcceptedRulesVersion: request.AcceptedRulesVersion,
+			Answers:              answers,
+			CreatedAt:            request.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if len(requests) > 0 && uint64(len(requests)) == filter.Limit {
+		out.NextCursor = encodeCursor(requests[len(requests)-1].ID)
+	}
+
+	return out, nil
+}
+
+func (s *Server) SetAutoApprovalRules(ctx context.Context, in *society.SetAutoApprovalRulesIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetAutoApprovalRules")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateSetAutoApprovalRulesIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdmin(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	rules := make([]model.AutoApprovalRule, 0, len(in.Rules))
+	for _, rule := range in.Rules {
+		// сохраняется только параметр своего вида
+		converted := model.AutoApprovalRule{Kind: rule.Kind}
+		switch rule.Kind {
+		case model.AutoApproveMemberOf:
+			converted.MemberOfSocietyUUID = rule.MemberOfSocietyUUID
+		case model.AutoApproveDailyLimit:
+			converted.DailyLimit = rule.DailyLimit
+		case model.AutoApproveInviteCode:
+			converted.InviteCode = rule.InviteCode
+		}
+		rules = append(rules, converted)
+	}
+
+	if err := s.dbR.SetAutoApprovalRules(ctx, in.SocietyUUID, rules); err != nil {
+		logger.Error("failed to SetAutoApprovalRules from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+func (s *Server) GetAutoApprovalRules(ctx context.Context, in *society.GetAutoApprovalRulesIn) (*society.GetAutoApprovalRulesOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetAutoApprovalRules")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetAutoApprovalRulesIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	// в правилах лежат коды приглашений, поэтому их видят только владелец и админы
+	if err := s.checkOwnerAdmin(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.dbR.GetAutoApprovalRules(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetAutoApprovalRules from BD")
+		return nil, err
+	}
+
+	out := &society.GetAutoApprovalRulesOut{Rules: make([]*society.AutoApprovalRule, 0, len(rules))}
+	for _, rule := range rules {
+		out.Rules = append(out.Rules, &society.AutoApprovalRule{
+			RuleID:              rule.ID,
+			Kind:                rule.Kind,
+			MemberOfSocietyUUID: rule.MemberOfSocietyUUID,
+			DailyLimit:          rule.DailyLimit,
+			InviteCode:          rule.InviteCode,
+		})
+	}
+
+	return out, nil
+}
+
+// autoApprove проверяет правила автоодобрения по порядку; заявку одобряет первое подошедшее,
+// и оно же записывается в заявку. false — ни одно правило не сработало, заявка остаётся на рассмотрении.
+func (s *Server) autoApprove(ctx context.Context, request *model.MembersRequest, inviteCode string) (bool, error) {
+	rules, err := s.dbR.GetAutoApprovalRules(ctx, request.SocietyUUID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get auto approval rules: %w", err)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+
+		matched := false
+		switch rule.Kind {
+		case model.AutoApproveMemberOf:
+			_, err := s.dbR.GetRoleSocietyMembers(ctx, request.UserUUID, rule.MemberOfSocietyUUID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return false, fmt.Errorf("failed to check membership in %s: %w", rule.MemberOfSocietyUUID, err)
+			}
+			matched = err == nil
+		case model.AutoApproveInviteCode:
+			matched = inviteCode != "" && subtle.ConstantTimeCompare([]byte(inviteCode), []byte(rule.InviteCode)) == 1
+		case model.AutoApproveDailyLimit:
+			// лимит проверяется в репозитории под блокировкой правила
+			matched = true
+		}
+		if !matched {
+			continue
+		}
+
+		approved, err := s.dbR.AutoApproveMembersRequest(ctx, request, rule)
+		if err != nil {
+			return false, fmt.Errorf("failed to auto approve by rule %d: %w", rule.ID, err)
+		}
+		if approved {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SetRequestTTL задаёт, через сколько часов ожидающие заявки истекают; 0 отключает истечение
+func (s *Server) SetRequestTTL(ctx context.Context, in *society.SetRequestTTLIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetRequestTTL")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateSetRequestTTLIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdmin(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	if err := s.dbR.SetRequestTTL(ctx, in.SocietyUUID, in.TTLHours); err != nil {
+		logger.Error("failed to SetRequestTTL from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+func (s *Server) SetMaxMembers(ctx context.Context, in *society.SetMaxMembersIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetMaxMembers")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateSetMaxMembersIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdmin(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	if err := s.dbR.SetMaxMembers(ctx, in.SocietyUUID, in.MaxMembers); err != nil {
+		logger.Error("failed to SetMaxMembers from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// RemoveSocietyMember исключает участника; владелец исключает админов, админ — только участников ниже себя
+func (s *Server) RemoveSocietyMember(ctx context.Context, in *society.RemoveSocietyMemberIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("RemoveSocietyMember")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateRemoveSocietyMemberIn(in, uuid).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	peerRole, err := s.dbR.IsOwnerAdminModerator(ctx, uuid, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return nil, err
+	}
+	if peerRole != 1 && peerRole != 2 {
+		logger.Error("failed to peer is not Owner or Admin")
+		return nil, status.Error(codes.PermissionDenied, "only owners and admins are allowed")
+	}
+
+	memberRole, err := s.dbR.GetRoleSocietyMembers(ctx, in.UserUUID, in.SocietyUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to GetRoleSocietyMembers from BD: member not found")
+		return nil, status.Error(codes.NotFound, "member not found")
+	}
+	if err != nil {
+		logger.Error("failed to GetRoleSocietyMembers from BD")
+		return nil, err
+	}
+	if memberRole <= peerRole {
+		logger.Error("failed to member role is not lower than peer role")
+		return nil, status.Error(codes.PermissionDenied, "only members with a lower role can be removed")
+	}
+
+	err = s.dbR.RemoveSocietyMember(ctx, in.UserUUID, in.SocietyUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to RemoveSocietyMember from BD: member not found")
+		return nil, status.Error(codes.NotFound, "member not found")
+	}
+	if err != nil {
+		logger.Error("failed to RemoveSocietyMember from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// SetSocietyParent привязывает сообщество к родителю или отвязывает его (пустой ParentUUID).
+// Привязка требует прав владельца или админа и в сообществе, и в родителе
+func (s *Server) SetSocietyParent(ctx context.Context, in *society.SetSocietyParentIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetSocietyParent")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateSetSocietyParentIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdmin(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+	if in.ParentUUID != "" {
+		if err := s.checkOwnerAdmin(ctx, uuid, in.ParentUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	acyclic, err := s.dbR.SetSocietyParent(ctx, in.SocietyUUID, in.ParentUUID, in.InheritModeration)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to SetSocietyParent from BD: society not found")
+		return nil, status.Error(codes.NotFound, "society not found")
+	}
+	if errors.Is(err, model.ErrHierarchyTooDeep) {
+		logger.Error("failed to SetSocietyParent from BD: hierarchy is too deep")
+		return nil, status.Error(codes.FailedPrecondition, "society hierarchy is too deep")
+	}
+	if err != nil {
+		logger.Error("failed to SetSocietyParent from BD")
+		return nil, err
+	}
+	if !acyclic {
+		logger.Error("failed to society is an ancestor of the parent")
+		return nil, status.Error(codes.FailedPrecondition, "society cannot be linked under its own descendant")
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+func (s *Server) GetSocietyChildren(ctx context.Context, in *society.GetSocietyChildrenIn) (*society.GetSocietyChildrenOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyChildren")
+
+	if err := validateGetSocietyChildrenIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	children, err := s.dbR.GetSocietyChildren(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetSocietyChildren from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyChildrenOut{Children: make([]*society.SocietyNode, 0, len(children))}
+	for _, child := range children {
+		out.Children = append(out.Children, societyNodeToProto(child))
+	}
+	return out, nil
+}
+
+// GetSocietyAncestors возвращает предков от непосредственного родителя до корня
+func (s *Server) GetSocietyAncestors(ctx context.Context, in *society.GetSocietyAncestorsIn) (*society.GetSocietyAncestorsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyAncestors")
+
+	if err := validateGetSocietyAncestorsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	ancestors, err := s.dbR.GetSocietyAncestors(ctx, in.SocietyUUID)
+	if err != nil {
+		logger.Error("failed to GetSocietyAncestors from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyAncestorsOut{Ancestors: make([]*society.SocietyNode, 0, len(ancestors))}
+	for _, ancestor := range ancestors {
+		out.Ancestors = append(out.Ancestors, societyNodeToProto(ancestor))
+	}
+	return out, nil
+}
+
+func societyNodeToProto(node model.SocietyNode) *society.SocietyNode {
+	return &society.SocietyNode{
+		SocietyUUID:             node.SocietyUUID,
+		Name:                    node.Name,
+		PhotoURL:                node.PhotoURL,
+		ParentUUID:              node.ParentUUID,
+		InheritParentModeration: node.InheritParentModeration,
+		Depth:                   node.Depth,
+	}
+}
+
+func (s *Server) CreateChannel(ctx context.Context, in *society.CreateChannelIn) (*society.CreateChannelOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("CreateChannel")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v := validateCreateChannelIn(in)
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdmin(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSocietySettings(ctx, v, in.FormatID, in.PostPermission); err != nil {
+		logger.Error(fmt.Sprintf("failed to checkSocietySettings from BD: %v", err))
+		return nil, err
+	}
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	channelUUID, err := s.dbR.CreateChannel(ctx, &model.Channel{
+		SocietyUUID:    in.SocietyUUID,
+		Name:           in.Name,
+		Description:    in.Description,
+		FormatID:       in.FormatID,
+		PostPermission: in.PostPermission,
+	}, uuid, maxChannelsCount)
+	if errors.Is(err, model.ErrTooManyChannels) {
+		logger.Error("failed to society has too many channels")
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("society can have at most %d channels", maxChannelsCount))
+	}
+	if errors.Is(err, model.ErrChannelNameTaken) {
+		logger.Error("failed to channel name is taken")
+		return nil, status.Error(codes.AlreadyExists, "channel with this name already exists")
+	}
+	if err != nil {
+		logger.Error("failed to CreateChannel from BD")
+		return nil, err
+	}
+
+	return &society.CreateChannelOut{ChannelUUID: channelUUID}, nil
+}
+
+func (s *Server) GetChannel(ctx context.Context, in *society.GetChannelIn) (*society.GetChannelOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetChannel")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetChannelIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	channel, err := s.getChannel(ctx, in.ChannelUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &society.GetChannelOut{Channel: channelToProto(*channel)}, nil
+}
+
+func (s *Server) GetSocietyChannels(ctx context.Context, in *society.GetSocietyChannelsIn) (*society.GetSocietyChannelsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyChannels")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetSocietyChannelsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	channels, err := s.dbR.GetSocietyChannels(ctx, in.SocietyUUID, uuid)
+	if err != nil {
+		logger.Error("failed to GetSocietyChannels from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyChannelsOut{Channels: make([]*society.Channel, 0, len(channels))}
+	for _, channel := range channels {
+		out.Channels = append(out.Channels, channelToProto(channel))
+	}
+	return out, nil
+}
+
+// UpdateChannel, как и UpdateSociety, доступен владельцу, админам и модераторам сообщества
+func (s *Server) UpdateChannel(ctx context.Context, in *society.UpdateChannelIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("UpdateChannel")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v := validateUpdateChannelIn(in)
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	channel, err := s.getChannel(ctx, in.ChannelUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, channel.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSocietySettings(ctx, v, in.FormatID, in.PostPermission); err != nil {
+		logger.Error(fmt.Sprintf("failed to checkSocietySettings from BD: %v", err))
+		return nil, err
+	}
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	err = s.dbR.UpdateChannel(ctx, &model.Channel{
+		ID:             in.ChannelUUID,
+		Name:           in.Name,
+		Description:    in.Description,
+		FormatID:       in.FormatID,
+		PostPermission: in.PostPermission,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to UpdateChannel from BD: channel not found")
+		return nil, status.Error(codes.NotFound, "channel not found")
+	}
+	if errors.Is(err, model.ErrChannelNameTaken) {
+		logger.Error("failed to channel name is taken")
+		return nil, status.Error(codes.AlreadyExists, "channel with this name already exists")
+	}
+	if err != nil {
+		logger.Error("failed to UpdateChannel from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+func (s *Server) RemoveChannel(ctx context.Context, in *society.RemoveChannelIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("RemoveChannel")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateRemoveChannelIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	channel, err := s.getChannel(ctx, in.ChannelUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdmin(ctx, uuid, channel.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	err = s.dbR.RemoveChannel(ctx, in.ChannelUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to RemoveChannel from BD: channel not found")
+		return nil, status.Error(codes.NotFound, "channel not found")
+	}
+	if err != nil {
+		logger.Error("failed to RemoveChannel from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// JoinChannel без UserUUID добавляет в канал самого пользователя. В открытый канал участник сообщества
+// вступает сам, в закрытые и платные, как и других пользователей, добавляют владелец, админы и модераторы
+func (s *Server) JoinChannel(ctx context.Context, in *society.JoinChannelIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("JoinChannel")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateJoinChannelIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	userUUID := in.UserUUID
+	if userUUID == "" {
+		userUUID = uuid
+	}
+
+	channel, err := s.getChannel(ctx, in.ChannelUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if userUUID != uuid || channel.FormatID != 1 {
+		if err := s.checkOwnerAdminModerator(ctx, uuid, channel.SocietyUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.dbR.AddChannelMember(ctx, in.ChannelUUID, userUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to AddChannelMember from BD: user is not a society member")
+		return nil, status.Error(codes.FailedPrecondition, "only society members can join its channels")
+	}
+	if err != nil {
+		logger.Error("failed to AddChannelMember from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// LeaveChannel без UserUUID выводит из канала самого пользователя, других исключает модерация сообщества
+func (s *Server) LeaveChannel(ctx context.Context, in *society.LeaveChannelIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("LeaveChannel")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateLeaveChannelIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	userUUID := in.UserUUID
+	if userUUID == "" {
+		userUUID = uuid
+	}
+
+	if userUUID != uuid {
+		channel, err := s.getChannel(ctx, in.ChannelUUID, uuid)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkOwnerAdminModerator(ctx, uuid, channel.SocietyUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.dbR.RemoveChannelMember(ctx, in.ChannelUUID, userUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to RemoveChannelMember from BD: member not found")
+		return nil, status.Error(codes.NotFound, "channel member not found")
+	}
+	if err != nil {
+		logger.Error("failed to RemoveChannelMember from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// getChannel переводит отсутствие канала в codes.NotFound
+func (s *Server) getChannel(ctx context.Context, channelUUID, peerUUID string) (*model.Channel, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	channel, err := s.dbR.GetChannel(ctx, channelUUID, peerUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to GetChannel from BD: channel not found")
+		return nil, status.Error(codes.NotFound, "channel not found")
+	}
+	if err != nil {
+		logger.Error("failed to GetChannel from BD")
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+func channelToProto(channel model.Channel) *society.Channel {
+	return &society.Channel{
+		ChannelUUID:    channel.ID,
+		SocietyUUID:    channel.SocietyUUID,
+		Name:           channel.Name,
+		Description:    channel.Description,
+		FormatID:       channel.FormatID,
+		PostPermission: channel.PostPermission,
+		MemberCount:    channel.MemberCount,
+		IsMember:       channel.IsMember,
+	}
+}
+
+// CreateEvent, как и объявления, доступен владельцу, админам и модераторам сообщества
+func (s *Server) CreateEvent(ctx context.Context, in *society.CreateEventIn) (*society.CreateEventOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("CreateEvent")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, event := validateCreateEventIn(in, time.Now())
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	event.AuthorUUID = uuid
+	eventUUID, err := s.dbR.CreateEvent(ctx, event)
+	if err != nil {
+		logger.Error("failed to CreateEvent from BD")
+		return nil, err
+	}
+
+	return &society.CreateEventOut{EventUUID: eventUUID}, nil
+}
+
+func (s *Server) GetEvent(ctx context.Context, in *society.GetEventIn) (*society.GetEventOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetEvent")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetEventIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	event, err := s.getEvent(ctx, in.EventUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checkSocietyReader(ctx, uuid, event.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	return &society.GetEventOut{Event: eventToProto(*event)}, nil
+}
+
+// GetSocietyEvents возвращает события сообщества, которые ещё не закончились, вместе с отменёнными
+func (s *Server) GetSocietyEvents(ctx context.Context, in *society.GetSocietyEventsIn) (*society.GetSocietyEventsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyEvents")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, filter := validateGetSocietyEventsIn(in, time.Now())
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+	filter.PeerUUID = uuid
+
+	if _, err := s.checkSocietyReader(ctx, uuid, filter.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.dbR.GetSocietyEvents(ctx, filter)
+	if err != nil {
+		logger.Error("failed to GetSocietyEvents from BD")
+		return nil, err
+	}
+
+	out := &society.GetSocietyEventsOut{Events: make([]*society.SocietyEvent, 0, len(events))}
+	for _, event := range events {
+		out.Events = append(out.Events, eventToProto(event))
+	}
+	return out, nil
+}
+
+// GetUpcomingEvents собирает неотменённые события всех сообществ пользователя
+func (s *Server) GetUpcomingEvents(ctx context.Context, in *society.GetUpcomingEventsIn) (*society.GetUpcomingEventsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetUpcomingEvents")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, filter := validateGetUpcomingEventsIn(in, time.Now())
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+	filter.PeerUUID = uuid
+
+	events, err := s.dbR.GetUpcomingEvents(ctx, filter)
+	if err != nil {
+		logger.Error("failed to GetUpcomingEvents from BD")
+		return nil, err
+	}
+
+	out := &society.GetUpcomingEventsOut{Events: make([]*society.SocietyEvent, 0, len(events))}
+	for _, event := range events {
+		out.Events = append(out.Events, eventToProto(event))
+	}
+	return out, nil
+}
+
+func (s *Server) UpdateEvent(ctx context.Context, in *society.UpdateEventIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("UpdateEvent")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, event := validateUpdateEventIn(in)
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	current, err := s.getEvent(ctx, in.EventUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, current.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	if current.CancelledAt.Valid {
+		logger.Error("failed to event is cancelled")
+		return nil, status.Error(codes.FailedPrecondition, "cancelled event cannot be changed")
+	}
+
+	err = s.dbR.UpdateEvent(ctx, event)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to UpdateEvent from BD: event is cancelled")
+		return nil, status.Error(codes.FailedPrecondition, "cancelled event cannot be changed")
+	}
+	if err != nil {
+		logger.Error("failed to UpdateEvent from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// CancelEvent оставляет событие в календаре со статусом отмены
+func (s *Server) CancelEvent(ctx context.Context, in *society.CancelEventIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("CancelEvent")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateCancelEventIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	event, err := s.getEvent(ctx, in.EventUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, event.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	err = s.dbR.CancelEvent(ctx, in.EventUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to CancelEvent from BD: event is already cancelled")
+		return nil, status.Error(codes.FailedPrecondition, "event is already cancelled")
+	}
+	if err != nil {
+		logger.Error("failed to CancelEvent from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// RSVPEvent записывает пользователя на событие своего сообщества (Going) или снимает запись
+func (s *Server) RSVPEvent(ctx context.Context, in *society.RSVPEventIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("RSVPEvent")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateRSVPEventIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	event, err := s.getEvent(ctx, in.EventUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Going {
+		if event.CancelledAt.Valid {
+			logger.Error("failed to event is cancelled")
+			return nil, status.Error(codes.FailedPrecondition, "event is cancelled")
+		}
+		if !event.EndsAt.After(time.Now()) {
+			logger.Error("failed to event is over")
+			return nil, status.Error(codes.FailedPrecondition, "event is over")
+		}
+	}
+
+	booked, err := s.dbR.SetEventRSVP(ctx, in.EventUUID, uuid, in.Going)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to SetEventRSVP from BD: user is not a society member")
+		return nil, status.Error(codes.FailedPrecondition, "only society members can RSVP to its events")
+	}
+	if err != nil {
+		logger.Error("failed to SetEventRSVP from BD")
+		return nil, err
+	}
+	if !booked {
+		logger.Error("failed to event is full")
+		return nil, status.Error(codes.FailedPrecondition, "event is full")
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// ExportEventsICS выгружает ещё не закончившиеся события сообщества или, без SocietyUUID,
+// ближайшие события всех сообществ пользователя в формате iCalendar
+func (s *Server) ExportEventsICS(ctx context.Context, in *society.ExportEventsICSIn) (*society.ExportEventsICSOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("ExportEventsICS")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateExportEventsICSIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	now := time.Now()
+	filter := &model.SocietyEventsFilter{
+		SocietyUUID: in.SocietyUUID,
+		PeerUUID:    uuid,
+		From:        now,
+		Limit:       maxCalendarEvents,
+	}
+
+	if in.SocietyUUID == "" {
+		events, err := s.dbR.GetUpcomingEvents(ctx, filter)
+		if err != nil {
+			logger.Error("failed to GetUpcomingEvents from BD")
+			return nil, err
+		}
+		return &society.ExportEventsICSOut{Calendar: eventsToICS("", events, now)}, nil
+	}
+
+	info, err := s.dbR.GetSocietyInfo(ctx, in.SocietyUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to GetSocietyInfo from BD: society not found")
+		return nil, status.Error(codes.NotFound, "society not found")
+	}
+	if err != nil {
+		logger.Error("failed to GetSocietyInfo from BD")
+		return nil, err
+	}
+	if _, err := s.checkSocietyReader(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.dbR.GetSocietyEvents(ctx, filter)
+	if err != nil {
+		logger.Error("failed to GetSocietyEvents from BD")
+		return nil, err
+	}
+
+	return &society.ExportEventsICSOut{Calendar: eventsToICS(info.Name, events, now)}, nil
+}
+
+func (s *Server) getEvent(ctx context.Context, eventUUID, peerUUID string) (*model.SocietyEvent, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	event, err := s.dbR.GetEvent(ctx, eventUUID, peerUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to GetEvent from BD: event not found")
+		return nil, status.Error(codes.NotFound, "event not found")
+	}
+	if err != nil {
+		logger.Error("failed to GetEvent from BD")
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func eventToProto(event model.SocietyEvent) *society.SocietyEvent {
+	return &society.SocietyEvent{
+		EventUUID:   event.ID,
+		SocietyUUID: event.SocietyUUID,
+		SocietyName: event.SocietyName,
+		AuthorUUID:  event.AuthorUUID,
+		Title:       event.Title,
+		Description: event.Description,
+		StartsAt:    event.StartsAt.UTC().Format(time.RFC3339),
+		EndsAt:      event.EndsAt.UTC().Format(time.RFC3339),
+		Location:    event.Location,
+		Link:        event.Link,
+		Capacity:    event.Capacity,
+		GoingCount:  event.GoingCount,
+		IsGoing:     event.IsGoing,
+		IsCancelled: event.CancelledAt.Valid,
+	}
+}
+
+func (s *Server) CreatePoll(ctx context.Context, in *society.CreatePollIn) (*society.CreatePollOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("CreatePoll")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, poll := validateCreatePollIn(in, time.Now())
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	poll.AuthorUUID = uuid
+	pollUUID, err := s.dbR.CreatePoll(ctx, poll)
+	if err != nil {
+		logger.Error("failed to CreatePoll from BD")
+		return nil, err
+	}
+
+	return &society.CreatePollOut{PollUUID: pollUUID}, nil
+}
+
+func (s *Server) GetPoll(ctx context.Context, in *society.GetPollIn) (*society.GetPollOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetPoll")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetPollIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	poll, err := s.getPoll(ctx, in.PollUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	// владелец, админ или модератор видят результаты опроса до его закрытия
+	role, err := s.checkSocietyReader(ctx, uuid, poll.SocietyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &society.GetPollOut{Poll: pollToProto(*poll, time.Now(), canEditSociety(role))}, nil
+}
+
+// GetSocietyPolls возвращает опросы сообщества, новые первыми
+func (s *Server) GetSocietyPolls(ctx context.Context, in *society.GetSocietyPollsIn) (*society.GetSocietyPollsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetSocietyPolls")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetSocietyPollsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	filter := &model.SocietyPollsFilter{
+		SocietyUUID: in.SocietyUUID,
+		PeerUUID:    uuid,
+		Limit:       uint64(in.Limit),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPollsLimit
+	}
+
+	role, err := s.checkSocietyReader(ctx, uuid, in.SocietyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	polls, err := s.dbR.GetSocietyPolls(ctx, filter)
+	if err != nil {
+		logger.Error("failed to GetSocietyPolls from BD")
+		return nil, err
+	}
+
+	now := time.Now()
+	moderator := canEditSociety(role)
+	out := &society.GetSocietyPollsOut{Polls: make([]*society.Poll, 0, len(polls))}
+	for _, poll := range polls {
+		out.Polls = append(out.Polls, pollToProto(poll, now, moderator))
+	}
+	return out, nil
+}
+
+// VotePoll принимает голос участника сообщества; переголосовать нельзя
+func (s *Server) VotePoll(ctx context.Context, in *society.VotePollIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("VotePoll")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateVotePollIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	poll, err := s.getPoll(ctx, in.PollUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.IsClosed(time.Now()) {
+		logger.Error("failed to poll is closed")
+		return nil, status.Error(codes.FailedPrecondition, "poll is closed")
+	}
+
+	if err := validatePollChoice(in.OptionIDs, poll).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	voted, err := s.dbR.VotePoll(ctx, in.PollUUID, uuid, in.OptionIDs)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to VotePoll from BD: poll is closed or user is not a society member")
+		return nil, status.Error(codes.FailedPrecondition, "only society members can vote in open polls")
+	}
+	if err != nil {
+		logger.Error("failed to VotePoll from BD")
+		return nil, err
+	}
+	if !voted {
+		logger.Error("failed to user has already voted")
+		return nil, status.Error(codes.AlreadyExists, "already voted in this poll")
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// ClosePoll досрочно закрывает опрос
+func (s *Server) ClosePoll(ctx context.Context, in *society.ClosePollIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("ClosePoll")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateClosePollIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	poll, err := s.getPoll(ctx, in.PollUUID, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, poll.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	err = s.dbR.ClosePoll(ctx, in.PollUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to ClosePoll from BD: poll is already closed")
+		return nil, status.Error(codes.FailedPrecondition, "poll is already closed")
+	}
+	if err != nil {
+		logger.Error("failed to ClosePoll from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+func (s *Server) getPoll(ctx context.Context, pollUUID, peerUUID string) (*model.Poll, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	poll, err := s.dbR.GetPoll(ctx, pollUUID, peerUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to GetPoll from BD: poll not found")
+		return nil, status.Error(codes.NotFound, "poll not found")
+	}
+	if err != nil {
+		logger.Error("failed to GetPoll from BD")
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func pollResultsOpen(poll model.Poll, now time.Time) bool {
+	return poll.ResultsVisibility == model.PollResultsLive || poll.IsClosed(now)
+}
+
+// pollToProto скрывает голоса, пока результаты опроса закрыты для peer
+func pollToProto(poll model.Poll, now time.Time, moderator bool) *society.Poll {
+	hidden := !moderator && !pollResultsOpen(poll, now)
+
+	out := &society.Poll{
+		PollUUID:          poll.ID,
+		SocietyUUID:       poll.SocietyUUID,
+		AuthorUUID:        poll.AuthorUUID,
+		Question:          poll.Question,
+		IsMultiple:        poll.IsMultiple,
+		IsAnonymous:       poll.IsAnonymous,
+		ResultsVisibility: poll.ResultsVisibility,
+		CreatedAt:         poll.CreatedAt.UTC().Format(time.RFC3339),
+		IsClosed:          poll.IsClosed(now),
+		VoterCount:        poll.VoterCount,
+		HasVoted:          poll.HasVoted,
+		ResultsHidden:     hidden,
+		Options:           make([]*society.PollOption, 0, len(poll.Options)),
+	}
+	if poll.ClosesAt.Valid {
+		out.ClosesAt = poll.ClosesAt.Time.UTC().Format(time.RFC3339)
+	}
+	for _, option := range poll.Options {
+		protoOption := &society.PollOption{OptionID: option.ID, Text: option.Text}
+		if !hidden {
+			protoOption.VoteCount = option.VoteCount
+			protoOption.VoterUUIDs = option.VoterUUIDs
+		}
+		out.Options = append(out.Options, protoOption)
+	}
+	return out
+}
+
+// GetNotificationSettings возвращает настройки уведомлений пользователя в сообществе, где он состоит
+func (s *Server) GetNotificationSettings(ctx context.Context, in *society.GetNotificationSettingsIn) (*society.GetNotificationSettingsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetNotificationSettings")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateGetNotificationSettingsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	settings, err := s.dbR.GetNotificationSettings(ctx, in.SocietyUUID, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to GetNotificationSettings from BD: member not found")
+		return nil, status.Error(codes.NotFound, "member not found")
+	}
+	if err != nil {
+		logger.Error("failed to GetNotificationSettings from BD")
+		return nil, err
+	}
+
+	out := &society.GetNotificationSettingsOut{
+		Settings: &society.NotificationSettings{
+			SocietyUUID:   settings.SocietyUUID,
+			Posts:         settings.Posts,
+			Announcements: settings.Announcements,
+			Events:        settings.Events,
+			JoinRequests:  settings.JoinRequests,
+			Delivery:      settings.Delivery,
+			Muted:         settings.IsMuted(time.Now()),
+		},
+	}
+	if out.Settings.Muted && settings.MutedUntil.Valid {
+		out.Settings.MutedUntil = settings.MutedUntil.Time.UTC().Format(time.RFC3339)
+	}
+	return out, nil
+}
+
+// SetNotificationSettings заменяет настройки уведомлений пользователя в сообществе
+func (s *Server) SetNotificationSettings(ctx context.Context, in *society.SetNotificationSettingsIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("SetNotificationSettings")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, settings := validateSetNotificationSettingsIn(in, time.Now())
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+	settings.UserUUID = uuid
+
+	err := s.dbR.SetNotificationSettings(ctx, settings)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to SetNotificationSettings from BD: member not found")
+		return nil, status.Error(codes.NotFound, "member not found")
+	}
+	if err != nil {
+		logger.Error("failed to SetNotificationSettings from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// GetNotificationRecipients вызывает сервис уведомлений: кому из участников сообщества доставить
+// уведомление об активности с учётом их настроек и ролей. Страницы идут по курсору
+func (s *Server) GetNotificationRecipients(ctx context.Context, in *society.GetNotificationRecipientsIn) (*society.GetNotificationRecipientsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetNotificationRecipients")
+
+	if err := validateGetNotificationRecipientsIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	filter := model.NotificationRecipientsFilter{
+		SocietyUUID: in.SocietyUUID,
+		Activity:    in.Activity,
+		ExcludeUUID: in.ExcludeUserUUID,
+		Roles:       in.Roles,
+		Limit:       uint64(in.Limit),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultRecipients
+	}
+	if in.Cursor != "" {
+		filter.AfterID, _ = decodeCursor(in.Cursor)
+	}
+
+	recipients, err := s.dbR.GetNotificationRecipients(ctx, &filter)
+	if err != nil {
+		logger.Error("failed to GetNotificationRecipients from BD")
+		return nil, err
+	}
+
+	out := &society.GetNotificationRecipientsOut{
+		Recipients: make([]*society.NotificationRecipient, 0, len(recipients)),
+	}
+	for _, recipient := range recipients {
+		out.Recipients = append(out.Recipients, &society.NotificationRecipient{
+			UserUUID: recipient.UserUUID,
+			Role:     recipient.Role,
+			Delivery: recipient.Delivery,
+		})
+	}
+	if len(recipients) > 0 && uint64(len(recipients)) == filter.Limit {
+		out.NextCursor = encodeCursor(recipients[len(recipients)-1].MemberID)
+	}
+
+	return out, nil
+}
+
+func (s *Server) CreateAnnouncement(ctx context.Context, in *society.CreateAnnouncementIn) (*society.CreateAnnouncementOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("CreateAnnouncement")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	v, expiresAt := validateCreateAnnouncementIn(in, time.Now())
+	if err := v.err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	announcement := &model.Announcement{
+		SocietyUUID: in.SocietyUUID,
+		AuthorUUID:  uuid,
+		Title:       in.Title,
+		Body:        in.Body,
+	}
+	if expiresAt != nil {
+		announcement.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	id, err := s.dbR.CreateAnnouncement(ctx, announcement)
+	if err != nil {
+		logger.Error("failed to CreateAnnouncement from BD")
+		return nil, err
+	}
+
+	return &society.CreateAnnouncementOut{AnnouncementID: id}, nil
+}
+
+func (s *Server) RemoveAnnouncement(ctx context.Context, in *society.RemoveAnnouncementIn) (*society.EmptySociety, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("RemoveAnnouncement")
+
+	uuid, ok := ctx.Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to not found UUID in context")
+		return nil, status.Error(codes.Internal, "uuid not found in context")
+	}
+
+	if err := validateRemoveAnnouncementIn(in).err(); err != nil {
+		logger.Error(fmt.Sprintf("failed to validate request: %v", err))
+		return nil, err
+	}
+
+	if err := s.checkOwnerAdminModerator(ctx, uuid, in.SocietyUUID); err != nil {
+		return nil, err
+	}
+
+	err := s.dbR.RemoveAnnouncement(ctx, in.SocietyUUID, in.AnnouncementID)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Error("failed to RemoveAnnouncement from BD: announcement not found")
+		return nil, status.Error(codes.NotFound, "announcement not found")
+	}
+	if err != nil {
+		logger.Error("failed to RemoveAnnouncement from BD")
+		return nil, err
+	}
+
+	return &society.EmptySociety{}, nil
+}
+
+// checkOwnerAdmin пускает к настройкам приёма в сообщество только владельца и админов
+func (s *Server) checkOwnerAdmin(ctx context.Context, peerUUID, societyUUID string) error {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	role, err := s.dbR.IsOwnerAdminModerator(ctx, peerUUID, societyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return err
+	}
+	if role != 1 && role != 2 {
+		logger.Error("failed to peer is not Owner or Admin")
+		return status.Error(codes.PermissionDenied, "only owners and admins are allowed")
+	}
+
+	return nil
+}
+
+// checkSocietyReader пускает к опросам и событиям сообщества только его участников и тех,
+// кто модерирует его по наследству; возвращает роль peer
+func (s *Server) checkSocietyReader(ctx context.Context, peerUUID, societyUUID string) (int, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	role, err := s.dbR.IsOwnerAdminModerator(ctx, peerUUID, societyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return 0, err
+	}
+	if role == 0 {
+		logger.Error("failed to peer is not a member of the society")
+		return 0, status.Error(codes.PermissionDenied, "only society members are allowed")
+	}
+
+	return role, nil
+}
+
+// checkOwnerAdminModerator пускает к модерации сообщества владельца, админов и модераторов
+func (s *Server) checkOwnerAdminModerator(ctx context.Context, peerUUID, societyUUID string) error {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	role, err := s.dbR.IsOwnerAdminModerator(ctx, peerUUID, societyUUID)
+	if err != nil {
+		logger.Error("failed to IsOwnerAdminModerator from BD")
+		return err
+	}
+	if role < 1 || role > 3 {
+		logger.Error("failed to peer is not Owner, Admin or Moderator")
+		return status.Error(codes.PermissionDenied, "only owners, admins and moderators are allowed")
+	}
+
+	return nil
+}
+
+func announcementToProto(announcement model.Announcement) *society.Announcement {
+	out := &society.Announcement{
+		AnnouncementID: announcement.ID,
+		Title:          announcement.Title,
+		Body:           announcement.Body,
+		AuthorUUID:     announcement.AuthorUUID,
+		CreatedAt:      announcement.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if announcement.ExpiresAt.Valid {
+		out.ExpiresAt = announcement.ExpiresAt.Time.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
 //func (s *Server) GetSocietyWithOffset(ctx context.Context, in *society.GetSocietyWithOffsetIn) (*society.GetSocietyWithOffsetOut, error) {
 //	uuid, ok := ctx.Value(config.KeyUUID).(string)
 //	if !ok {
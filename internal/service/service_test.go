@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -15,6 +18,7 @@ import (
 
 	logger_lib "github.com/s21platform/logger-lib"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -61,6 +65,8 @@ func TestServer_CreateSociety(t *testing.T) {
 			IsSearch:         true,
 		}
 		expectedSocietyUUID := uuid.Generate().String()
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
 		mockDBRepo.EXPECT().CreateSociety(ctx, gomock.Any()).Return(expectedSocietyUUID, nil)
 		mockLogger.EXPECT().AddFuncName("CreateSociety")
 
@@ -98,6 +104,8 @@ func TestServer_CreateSociety(t *testing.T) {
 			IsSearch:         true,
 		}
 		expectedError := errors.New("database error")
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
 		mockDBRepo.EXPECT().CreateSociety(ctx, gomock.Any()).Return("", expectedError)
 		mockLogger.EXPECT().AddFuncName("CreateSociety")
 		mockLogger.EXPECT().Error("failed to CreateSociety from BD")
@@ -118,7 +126,7 @@ func TestServer_CreateSociety(t *testing.T) {
 			IsSearch:         true,
 		}
 		mockLogger.EXPECT().AddFuncName("CreateSociety")
-		mockLogger.EXPECT().Error("failed to Name society is empty")
+		mockLogger.EXPECT().Error("failed to validate request: rpc error: code = InvalidArgument desc = name not provided")
 		ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
 
 		result, err := s.CreateSociety(ctx, mockInput)
@@ -131,6 +139,63 @@ func TestServer_CreateSociety(t *testing.T) {
 
 		assert.Nil(t, result)
 	})
+	t.Run("should_return_field_violations_if_format_does_not_exist", func(t *testing.T) {
+		userUUID := uuid.Generate().String()
+		ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
+		ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+
+		mockInput := &society.SetSocietyIn{
+			Name:             "Test Society",
+			FormatID:         42,
+			PostPermissionID: 2,
+			IsSearch:         true,
+		}
+		mockLogger.EXPECT().AddFuncName("CreateSociety")
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(42)).Return(false, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		result, err := s.CreateSociety(ctx, mockInput)
+		assert.Nil(t, result)
+
+		statusErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
+		require.Len(t, statusErr.Details(), 1)
+		badRequest, ok := statusErr.Details()[0].(*errdetails.BadRequest)
+		require.True(t, ok)
+		require.Len(t, badRequest.FieldViolations, 1)
+		assert.Equal(t, "FormatID", badRequest.FieldViolations[0].Field)
+	})
+	t.Run("should_return_all_field_violations_at_once", func(t *testing.T) {
+		userUUID := uuid.Generate().String()
+		ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
+		ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+
+		mockInput := &society.SetSocietyIn{
+			Name:             strings.Repeat("a", 256),
+			FormatID:         0,
+			PostPermissionID: -1,
+		}
+		mockLogger.EXPECT().AddFuncName("CreateSociety")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		result, err := s.CreateSociety(ctx, mockInput)
+		assert.Nil(t, result)
+
+		statusErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
+		require.Len(t, statusErr.Details(), 1)
+		badRequest, ok := statusErr.Details()[0].(*errdetails.BadRequest)
+		require.True(t, ok)
+
+		var fields []string
+		for _, violation := range badRequest.FieldViolations {
+			fields = append(fields, violation.Field)
+		}
+		assert.Equal(t, []string{"Name", "FormatID", "PostPermissionID"}, fields)
+	})
 }
 
 func TestServer_GetSocietyInfo(t *testing.T) {
@@ -161,19 +226,25 @@ func TestServer_GetSocietyInfo(t *testing.T) {
 			FormatID:       1,
 			PostPermission: 2,
 			IsSearch:       true,
-			CountSubscribe: 100,
+			CountSubscribe: 150,
 			TagsID:         []int64{1, 2},
-			CanEditSociety: true,
+			PeerRole:       1,
+			EditorRole:     1,
+			IsMember:       true,
+			Announcements: []model.Announcement{{
+				ID:         3,
+				AuthorUUID: userUUID,
+				Title:      "Meetup",
+				Body:       "Friday at 19:00",
+				ExpiresAt:  sql.NullTime{Time: time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC), Valid: true},
+				CreatedAt:  time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC),
+			}},
 		}
 
 		expectedCountSubscribe := int64(150)
 		expectedTags := []int64{1, 2}
-		expectedCanEdit := 1
 
-		mockDBRepo.EXPECT().GetSocietyInfo(ctx, societyUUID).Return(expectedSocietyInfo, nil)
-		mockDBRepo.EXPECT().CountSubscribe(ctx, societyUUID).Return(expectedCountSubscribe, nil)
-		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, userUUID, societyUUID).Return(expectedCanEdit, nil) // <-- Добавлено
-		mockDBRepo.EXPECT().GetTags(ctx, societyUUID).Return(expectedTags, nil)
+		mockDBRepo.EXPECT().GetSocietyInfoForPeer(ctx, societyUUID, userUUID).Return(expectedSocietyInfo, nil)
 
 		mockLogger.EXPECT().AddFuncName("GetSocietyInfo")
 
@@ -195,7 +266,60 @@ func TestServer_GetSocietyInfo(t *testing.T) {
 			assert.Equal(t, expectedTags[i], tag.TagID)
 		}
 
-		assert.True(t, result.CanEditSociety) // или false, если expectedCanEdit > 3
+		assert.True(t, result.CanEditSociety)
+		assert.True(t, result.IsMember)
+		assert.False(t, result.IsRequestPending)
+		assert.Equal(t, []*society.Announcement{{
+			AnnouncementID: 3,
+			Title:          "Meetup",
+			Body:           "Friday at 19:00",
+			AuthorUUID:     userUUID,
+			CreatedAt:      "2024-10-01T09:00:00Z",
+			ExpiresAt:      "2024-10-05T00:00:00Z",
+		}}, result.Announcements)
+	})
+
+	t.Run("should_return_pending_request_for_non_member", func(t *testing.T) {
+		userUUID := uuid.Generate().String()
+		ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
+		ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+		societyUUID := uuid.Generate().String()
+
+		mockDBRepo.EXPECT().GetSocietyInfoForPeer(ctx, societyUUID, userUUID).Return(&model.SocietyInfo{
+			Name:      "Closed Society",
+			FormatID:  2,
+			IsPending: true,
+		}, nil)
+		mockLogger.EXPECT().AddFuncName("GetSocietyInfo")
+
+		result, err := s.GetSocietyInfo(ctx, &society.GetSocietyInfoIn{SocietyUUID: societyUUID})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Description)
+		assert.False(t, result.CanEditSociety)
+		assert.False(t, result.IsMember)
+		assert.True(t, result.IsRequestPending)
+		assert.Empty(t, result.Announcements)
+	})
+
+	t.Run("should_allow_edit_for_inherited_moderator", func(t *testing.T) {
+		userUUID := uuid.Generate().String()
+		ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
+		ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+		societyUUID := uuid.Generate().String()
+
+		mockDBRepo.EXPECT().GetSocietyInfoForPeer(ctx, societyUUID, userUUID).Return(&model.SocietyInfo{
+			Name:       "Chapter",
+			FormatID:   1,
+			EditorRole: 3,
+		}, nil)
+		mockLogger.EXPECT().AddFuncName("GetSocietyInfo")
+
+		result, err := s.GetSocietyInfo(ctx, &society.GetSocietyInfoIn{SocietyUUID: societyUUID})
+
+		assert.NoError(t, err)
+		assert.True(t, result.CanEditSociety)
+		assert.False(t, result.IsMember)
 	})
 
 	t.Run("should_return_error_if_societyUUID_is_empty", func(t *testing.T) {
@@ -205,7 +329,7 @@ func TestServer_GetSocietyInfo(t *testing.T) {
 		mockInput := &society.GetSocietyInfoIn{SocietyUUID: ""}
 
 		mockLogger.EXPECT().AddFuncName("GetSocietyInfo")
-		mockLogger.EXPECT().Error("failed to SocietyUUID is empty")
+		mockLogger.EXPECT().Error("failed to validate request: rpc error: code = InvalidArgument desc = societyUUID not provided")
 
 		result, err := s.GetSocietyInfo(ctx, mockInput)
 
@@ -217,6 +341,24 @@ func TestServer_GetSocietyInfo(t *testing.T) {
 		assert.Equal(t, "societyUUID not provided", statusErr.Message())
 	})
 
+	t.Run("should_return_error_if_societyUUID_is_not_uuid", func(t *testing.T) {
+		userUUID := uuid.Generate().String()
+		ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
+		ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+		mockInput := &society.GetSocietyInfoIn{SocietyUUID: "not-a-uuid"}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietyInfo")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		result, err := s.GetSocietyInfo(ctx, mockInput)
+
+		assert.Nil(t, result)
+		statusErr, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
+		assert.Equal(t, "SocietyUUID is not a valid uuid", statusErr.Message())
+	})
+
 	t.Run("should_return_error_if_dbR_GetSocietyInfo_fails", func(t *testing.T) {
 		userUUID := uuid.Generate().String()
 		ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
@@ -225,9 +367,9 @@ func TestServer_GetSocietyInfo(t *testing.T) {
 		mockInput := &society.GetSocietyInfoIn{SocietyUUID: societyUUID}
 		expectedError := errors.New("database error")
 
-		mockDBRepo.EXPECT().GetSocietyInfo(ctx, societyUUID).Return(nil, expectedError)
+		mockDBRepo.EXPECT().GetSocietyInfoForPeer(ctx, societyUUID, userUUID).Return(nil, expectedError)
 		mockLogger.EXPECT().AddFuncName("GetSocietyInfo")
-		mockLogger.EXPECT().Error("failed to GetSocietyInfo from BD")
+		mockLogger.EXPECT().Error("failed to GetSocietyInfoForPeer from BD")
 
 		result, err := s.GetSocietyInfo(ctx, mockInput)
 
@@ -265,6 +407,8 @@ func TestServer_UpdateSociety(t *testing.T) {
 
 		mockLogger.EXPECT().AddFuncName("UpdateSociety")
 		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, ownerUUID, societyUUID).Return(1, nil) // 1 - Owner
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
 		mockDBRepo.EXPECT().UpdateSociety(ctx, expectedUpdateSociety).Return(nil)
 
 		_, err := s.UpdateSociety(ctx, expectedUpdateSociety)
@@ -316,7 +460,7 @@ func TestServer_UpdateSociety(t *testing.T) {
 		}
 
 		mockLogger.EXPECT().AddFuncName("UpdateSociety")
-		mockLogger.EXPECT().Error("failed to SocietyUUID is empty")
+		mockLogger.EXPECT().Error("failed to validate request: rpc error: code = InvalidArgument desc = societyUUID not provided")
 
 		_, err := s.UpdateSociety(ctx, expectedUpdateSociety)
 
@@ -343,7 +487,7 @@ func TestServer_UpdateSociety(t *testing.T) {
 		}
 
 		mockLogger.EXPECT().AddFuncName("UpdateSociety")
-		mockLogger.EXPECT().Error("failed to Name society is empty")
+		mockLogger.EXPECT().Error("failed to validate request: rpc error: code = InvalidArgument desc = name not provided")
 
 		_, err := s.UpdateSociety(ctx, expectedUpdateSociety)
 
@@ -374,6 +518,8 @@ func TestServer_UpdateSociety(t *testing.T) {
 
 		mockLogger.EXPECT().AddFuncName("UpdateSociety")
 		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, ownerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
 		mockDBRepo.EXPECT().UpdateSociety(ctx, expectedUpdateSociety).Return(expectedError)
 		mockLogger.EXPECT().Error("failed to UpdateSociety from BD")
 
@@ -387,11 +533,6 @@ func TestServer_UpdateSociety(t *testing.T) {
 func TestServer_RemoveSociety(t *testing.T) {
 	t.Parallel()
 
-	db, driverMock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer db.Close()
-	sqlxDB := sqlx.NewDb(db, "postgres")
-
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -400,54 +541,61 @@ func TestServer_RemoveSociety(t *testing.T) {
 
 	s := &Server{dbR: mockDBRepo}
 
-	societyUUID := "soc-123"
+	societyUUID := uuid.Generate().String()
 	userUUID := "user-abc"
 	in := &society.RemoveSocietyIn{SocietyUUID: societyUUID}
 
 	ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
 	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
 
-	driverMock.ExpectBegin()  // sqlxDB.Beginx()
-	driverMock.ExpectCommit() // tx.Commit()
+	withTx := func(ctx context.Context, fn func(repo DbRepo) error) error {
+		return fn(mockDBRepo)
+	}
 
-	mockLogger.EXPECT().AddFuncName("RemoveSociety")
-
-	mockDBRepo.
-		EXPECT().
-		GetOwner(ctx, societyUUID).
-		Return(userUUID, nil)
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSociety")
+		mockDBRepo.EXPECT().GetOwner(ctx, societyUUID).Return(userUUID, nil)
+		mockDBRepo.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx)
+		gomock.InOrder(
+			mockDBRepo.EXPECT().RemoveSocietyHasTagsEntry(ctx, societyUUID).Return(nil),
+			mockDBRepo.EXPECT().RemoveMembersRequestEntry(ctx, societyUUID).Return(nil),
+			mockDBRepo.EXPECT().RemoveSocietyMembersEntry(ctx, societyUUID).Return(nil),
+			mockDBRepo.EXPECT().RemoveSociety(ctx, societyUUID).Return(nil),
+		)
+
+		out, err := s.RemoveSociety(ctx, in)
 
-	mockDBRepo.
-		EXPECT().
-		Conn().
-		Return(sqlxDB)
+		assert.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
 
-	mockDBRepo.
-		EXPECT().
-		RemoveSocietyHasTagsEntry(ctx, societyUUID, gomock.Any()).
-		Return(nil)
+	t.Run("fail: error inside transaction is returned and stops the flow", func(t *testing.T) {
+		expectedError := errors.New("members error")
 
-	mockDBRepo.
-		EXPECT().
-		RemoveSociety(ctx, societyUUID, gomock.Any()).
-		Return(nil)
+		mockLogger.EXPECT().AddFuncName("RemoveSociety")
+		mockDBRepo.EXPECT().GetOwner(ctx, societyUUID).Return(userUUID, nil)
+		mockDBRepo.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx)
+		mockDBRepo.EXPECT().RemoveSocietyHasTagsEntry(ctx, societyUUID).Return(nil)
+		mockDBRepo.EXPECT().RemoveMembersRequestEntry(ctx, societyUUID).Return(nil)
+		mockDBRepo.EXPECT().RemoveSocietyMembersEntry(ctx, societyUUID).Return(expectedError)
+		mockLogger.EXPECT().Error("failed to RemoveSocietyMembersEntry from BD")
 
-	mockDBRepo.
-		EXPECT().
-		RemoveMembersRequestEntry(ctx, societyUUID, gomock.Any()).
-		Return(nil)
+		out, err := s.RemoveSociety(ctx, in)
 
-	mockDBRepo.
-		EXPECT().
-		RemoveSocietyMembersEntry(ctx, societyUUID, gomock.Any()).
-		Return(nil)
+		assert.Nil(t, out)
+		assert.Equal(t, expectedError, err)
+	})
 
-	out, err := s.RemoveSociety(ctx, in)
+	t.Run("fail: peer is not owner", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSociety")
+		mockDBRepo.EXPECT().GetOwner(ctx, societyUUID).Return("another-user", nil)
+		mockLogger.EXPECT().Error("failed to IsOwnerAdminModerator from BD")
 
-	assert.NoError(t, err)
-	assert.Equal(t, &society.EmptySociety{}, out)
+		out, err := s.RemoveSociety(ctx, in)
 
-	require.NoError(t, driverMock.ExpectationsWereMet())
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
 }
 
 func TestServer_SubscribeToSociety(t *testing.T) {
@@ -466,7 +614,7 @@ func TestServer_SubscribeToSociety(t *testing.T) {
 
 	s := &Server{dbR: mockDBRepo}
 
-	societyUUID := "soc-abc"
+	societyUUID := uuid.Generate().String()
 	userUUID := "user-xyz"
 	in := &society.SubscribeToSocietyIn{SocietyUUID: societyUUID}
 
@@ -477,18 +625,178 @@ func TestServer_SubscribeToSociety(t *testing.T) {
 	t.Run("success: format == 1", func(t *testing.T) {
 		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
 		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(1, nil)
-		mockDBRepo.EXPECT().AddSocietyMembers(ctx, userUUID, societyUUID).Return(nil)
+		mockDBRepo.EXPECT().AddSocietyMembers(ctx, userUUID, societyUUID).Return(false, nil)
 
 		out, err := s.SubscribeToSociety(ctx, in)
 		assert.NoError(t, err)
 		assert.Equal(t, &society.EmptySociety{}, out)
 	})
 
+	// Ошибка: участник вступает повторно
+	t.Run("fail: already a member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().AddSocietyMembers(ctx, userUUID, societyUUID).Return(false, model.ErrAlreadyMember)
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SubscribeToSociety(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
 	// Успешный кейс: формат != 1 → AddMembersRequests
 	t.Run("success: format != 1", func(t *testing.T) {
 		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
 		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(2, nil)
-		mockDBRepo.EXPECT().AddMembersRequests(ctx, userUUID, societyUUID).Return(nil)
+		mockDBRepo.EXPECT().GetSocietyRules(ctx, societyUUID).Return(&model.SocietyRules{}, nil)
+		mockDBRepo.EXPECT().GetSocietyQuestions(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().AddMembersRequests(ctx, &model.MembersRequest{UserUUID: userUUID, SocietyUUID: societyUUID}).Return(nil)
+
+		out, err := s.SubscribeToSociety(ctx, in)
+		assert.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	rules := &model.SocietyRules{Version: 2, Items: []model.SocietyRule{{Title: "Be kind", Body: "No insults"}}}
+
+	// Закрытое сообщество с правилами: заявка сохраняет принятую версию
+	t.Run("success: closed society rules accepted", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetSocietyRules(ctx, societyUUID).Return(rules, nil)
+		mockDBRepo.EXPECT().GetSocietyQuestions(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().AddMembersRequests(ctx, &model.MembersRequest{UserUUID: userUUID, SocietyUUID: societyUUID, AcceptedRulesVersion: 2}).Return(nil)
+
+		out, err := s.SubscribeToSociety(ctx, &society.SubscribeToSocietyIn{SocietyUUID: societyUUID, AcceptedRulesVersion: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	// Закрытое сообщество с правилами: устаревшая версия не принимается
+	t.Run("fail: closed society rules not accepted", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetSocietyRules(ctx, societyUUID).Return(rules, nil)
+		mockLogger.EXPECT().Error("failed to peer did not accept current rules")
+
+		out, err := s.SubscribeToSociety(ctx, &society.SubscribeToSocietyIn{SocietyUUID: societyUUID, AcceptedRulesVersion: 1})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	questions := []model.SocietyQuestion{
+		{ID: 10, Kind: model.QuestionText, Prompt: "Why do you want to join?", IsRequired: true},
+		{ID: 11, Kind: model.QuestionSingleChoice, Prompt: "Campus", Options: []string{"Moscow", "Kazan"}, IsRequired: true},
+		{ID: 12, Kind: model.QuestionMultiChoice, Prompt: "Stack", Options: []string{"Go", "C", "Python"}},
+	}
+
+	// Закрытое сообщество с анкетой: ответы сохраняются в порядке вопросов вместе с их текстом
+	t.Run("success: closed society answers saved", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetSocietyRules(ctx, societyUUID).Return(&model.SocietyRules{}, nil)
+		mockDBRepo.EXPECT().GetSocietyQuestions(ctx, societyUUID).Return(questions, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().AddMembersRequests(ctx, &model.MembersRequest{
+			UserUUID:    userUUID,
+			SocietyUUID: societyUUID,
+			Answers: []model.RequestAnswer{
+				{QuestionID: 10, Prompt: "Why do you want to join?", Values: []string{"To learn Go"}},
+				{QuestionID: 11, Prompt: "Campus", Values: []string{"Kazan"}},
+			},
+		}).Return(nil)
+
+		out, err := s.SubscribeToSociety(ctx, &society.SubscribeToSocietyIn{
+			SocietyUUID: societyUUID,
+			Answers: []*society.QuestionAnswer{
+				{QuestionID: 11, Values: []string{"Kazan"}},
+				{QuestionID: 10, Values: []string{"To learn Go"}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	// Закрытое сообщество с анкетой: пропущенный обязательный ответ и чужой вариант отклоняются
+	t.Run("fail: closed society answers invalid", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetSocietyRules(ctx, societyUUID).Return(&model.SocietyRules{}, nil)
+		mockDBRepo.EXPECT().GetSocietyQuestions(ctx, societyUUID).Return(questions, nil)
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SubscribeToSociety(ctx, &society.SubscribeToSocietyIn{
+			SocietyUUID: societyUUID,
+			Answers: []*society.QuestionAnswer{
+				{QuestionID: 10, Values: []string{"  "}},
+				{QuestionID: 11, Values: []string{"Moscow"}},
+				{QuestionID: 12, Values: []string{"Rust"}},
+				{QuestionID: 99, Values: []string{"?"}},
+			},
+		})
+		assert.Nil(t, out)
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		assert.Len(t, st.Details()[0].(*errdetails.BadRequest).FieldViolations, 3)
+	})
+
+	otherSocietyUUID := uuid.Generate().String()
+	autoRules := []model.AutoApprovalRule{
+		{ID: 1, Kind: model.AutoApproveMemberOf, MemberOfSocietyUUID: otherSocietyUUID},
+		{ID: 2, Kind: model.AutoApproveInviteCode, InviteCode: "welcome21"},
+		{ID: 3, Kind: model.AutoApproveDailyLimit, DailyLimit: 5},
+	}
+	request := &model.MembersRequest{UserUUID: userUUID, SocietyUUID: societyUUID}
+
+	// Автоодобрение: первое подошедшее правило одобряет заявку, до AddMembersRequests дело не доходит
+	t.Run("success: auto approved by invite code", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(autoRules, nil)
+		mockDBRepo.EXPECT().GetRoleSocietyMembers(ctx, userUUID, otherSocietyUUID).Return(0, fmt.Errorf("failed: %w", sql.ErrNoRows))
+		mockDBRepo.EXPECT().AutoApproveMembersRequest(ctx, request, &autoRules[1]).Return(true, nil)
+
+		out, err := s.SubscribeToSociety(ctx, &society.SubscribeToSocietyIn{SocietyUUID: societyUUID, InviteCode: "welcome21"})
+		assert.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	// Автоодобрение: исчерпанный дневной лимит оставляет заявку на рассмотрении
+	t.Run("success: daily limit exhausted keeps request pending", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(autoRules, nil)
+		mockDBRepo.EXPECT().GetRoleSocietyMembers(ctx, userUUID, otherSocietyUUID).Return(0, fmt.Errorf("failed: %w", sql.ErrNoRows))
+		mockDBRepo.EXPECT().AutoApproveMembersRequest(ctx, request, &autoRules[2]).Return(false, nil)
+		mockDBRepo.EXPECT().AddMembersRequests(ctx, request).Return(nil)
+
+		out, err := s.SubscribeToSociety(ctx, &society.SubscribeToSocietyIn{SocietyUUID: societyUUID, InviteCode: "wrong-code"})
+		assert.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	// Автоодобрение: ошибка при проверке членства не превращается в молчаливый отказ
+	t.Run("fail: auto approval membership check error", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(autoRules, nil)
+		mockDBRepo.EXPECT().GetRoleSocietyMembers(ctx, userUUID, otherSocietyUUID).Return(0, errors.New("db down"))
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SubscribeToSociety(ctx, in)
+		assert.Nil(t, out)
+		assert.ErrorContains(t, err, "db down")
+	})
+
+	// Платное сообщество правила при заявке не проверяет
+	t.Run("success: paid society skips rules", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
+		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().AddMembersRequests(ctx, &model.MembersRequest{UserUUID: userUUID, SocietyUUID: societyUUID}).Return(nil)
 
 		out, err := s.SubscribeToSociety(ctx, in)
 		assert.NoError(t, err)
@@ -522,7 +830,10 @@ func TestServer_SubscribeToSociety(t *testing.T) {
 	t.Run("fail: AddMembersRequests error", func(t *testing.T) {
 		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
 		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(2, nil)
-		mockDBRepo.EXPECT().AddMembersRequests(ctx, userUUID, societyUUID).Return(errors.New("add req error"))
+		mockDBRepo.EXPECT().GetSocietyRules(ctx, societyUUID).Return(&model.SocietyRules{}, nil)
+		mockDBRepo.EXPECT().GetSocietyQuestions(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().GetAutoApprovalRules(ctx, societyUUID).Return(nil, nil)
+		mockDBRepo.EXPECT().AddMembersRequests(ctx, &model.MembersRequest{UserUUID: userUUID, SocietyUUID: societyUUID}).Return(errors.New("add req error"))
 		mockLogger.EXPECT().Error("failed to AddMembersRequests from BD")
 
 		out, err := s.SubscribeToSociety(ctx, in)
@@ -534,7 +845,7 @@ func TestServer_SubscribeToSociety(t *testing.T) {
 	t.Run("fail: AddSocietyMembers error", func(t *testing.T) {
 		mockLogger.EXPECT().AddFuncName("SubscribeToSociety")
 		mockDBRepo.EXPECT().GetFormatSociety(ctx, societyUUID).Return(1, nil)
-		mockDBRepo.EXPECT().AddSocietyMembers(ctx, userUUID, societyUUID).Return(errors.New("add mem error"))
+		mockDBRepo.EXPECT().AddSocietyMembers(ctx, userUUID, societyUUID).Return(false, errors.New("add mem error"))
 		mockLogger.EXPECT().Error("failed to AddSocietyMembers from BD")
 
 		out, err := s.SubscribeToSociety(ctx, in)
@@ -556,7 +867,7 @@ func TestServer_UnSubscribeToSociety(t *testing.T) {
 	s := &Server{dbR: mockDBRepo}
 
 	userUUID := "user-abc"
-	societyUUID := "soc-123"
+	societyUUID := uuid.Generate().String()
 	ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
 	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
 
@@ -609,6 +920,25 @@ func TestServer_UnSubscribeToSociety(t *testing.T) {
 		assert.ErrorContains(t, err, "role fetch error")
 	})
 
+	t.Run("success: leaves the waitlist", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("UnSubscribeToSociety")
+
+		mockDBRepo.
+			EXPECT().
+			GetRoleSocietyMembers(gomock.Any(), userUUID, societyUUID).
+			Return(0, fmt.Errorf("wrapped: %w", sql.ErrNoRows))
+
+		mockDBRepo.
+			EXPECT().
+			LeaveWaitlist(gomock.Any(), userUUID, societyUUID).
+			Return(nil)
+
+		out, err := s.UnSubscribeToSociety(ctx, in)
+
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
 	t.Run("UnSubscribeToSociety returns error", func(t *testing.T) {
 		mockLogger.EXPECT().AddFuncName("UnSubscribeToSociety")
 
@@ -641,7 +971,7 @@ func TestGetSocietyForUserWithOffset(t *testing.T) {
 	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 	s := &Server{dbR: mockDBRepo}
 
-	userUUID := "user-123"
+	userUUID := uuid.Generate().String()
 	ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
 	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
 
@@ -672,6 +1002,38 @@ func TestGetSocietyForUserWithOffset(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid value")
 	})
 
+	t.Run("Limit above the page cap", func(t *testing.T) {
+		in := &society.GetSocietyForUserWithOffsetIn{UserUUID: userUUID, Limit: maxSocietiesPage + 1}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietyForUserWithOffset")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		_, err := s.GetSocietyForUserWithOffset(ctx, in)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Default limit", func(t *testing.T) {
+		in := &society.GetSocietyForUserWithOffsetIn{UserUUID: userUUID}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietyForUserWithOffset")
+		mockDBRepo.
+			EXPECT().
+			GetUserSocieties(gomock.Any(), &model.UserSocietiesFilter{
+				UserUUID:   userUUID,
+				CallerUUID: userUUID,
+				Limit:      defaultSocietiesPage,
+			}).
+			Return(nil, nil)
+		mockDBRepo.
+			EXPECT().
+			CountUserSocieties(gomock.Any(), userUUID).
+			Return(int64(0), nil)
+
+		out, err := s.GetSocietyForUserWithOffset(ctx, in)
+		assert.NoError(t, err)
+		assert.Empty(t, out.Societies)
+	})
+
 	t.Run("GetUserSocieties returns error", func(t *testing.T) {
 		in := &society.GetSocietyForUserWithOffsetIn{
 			UserUUID: userUUID,
@@ -681,7 +1043,7 @@ func TestGetSocietyForUserWithOffset(t *testing.T) {
 		mockLogger.EXPECT().AddFuncName("GetSocietyForUserWithOffset")
 		mockDBRepo.
 			EXPECT().
-			GetUserSocieties(gomock.Any(), uint64(10), uint64(0), userUUID).
+			GetUserSocieties(gomock.Any(), gomock.Any()).
 			Return(nil, fmt.Errorf("db error"))
 		mockLogger.EXPECT().Error("failed to GetUserSocieties from BD")
 
@@ -690,50 +1052,53 @@ func TestGetSocietyForUserWithOffset(t *testing.T) {
 		assert.Contains(t, err.Error(), "db error")
 	})
 
-	t.Run("GetInfoSociety returns error", func(t *testing.T) {
+	t.Run("CountUserSocieties returns error", func(t *testing.T) {
 		in := &society.GetSocietyForUserWithOffsetIn{
 			UserUUID: userUUID,
 			Offset:   0,
 			Limit:    10,
 		}
-		groups := []string{"soc-1"}
 		mockLogger.EXPECT().AddFuncName("GetSocietyForUserWithOffset")
 		mockDBRepo.
 			EXPECT().
-			GetUserSocieties(gomock.Any(), uint64(10), uint64(0), userUUID).
-			Return(groups, nil)
+			GetUserSocieties(gomock.Any(), gomock.Any()).
+			Return([]model.SocietyWithOffsetData{{SocietyUUID: "soc-1"}}, nil)
 
 		mockDBRepo.
 			EXPECT().
-			GetInfoSociety(gomock.Any(), groups).
-			Return(nil, fmt.Errorf("info error"))
+			CountUserSocieties(gomock.Any(), userUUID).
+			Return(int64(0), fmt.Errorf("count error"))
 
-		mockLogger.EXPECT().Error("failed to GetInfoSociety from BD")
+		mockLogger.EXPECT().Error("failed to CountUserSocieties from BD")
 
 		_, err := s.GetSocietyForUserWithOffset(ctx, in)
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "info error")
+		assert.Contains(t, err.Error(), "count error")
 	})
 
 	t.Run("Success", func(t *testing.T) {
+		targetUUID := uuid.Generate().String()
 		in := &society.GetSocietyForUserWithOffsetIn{
-			UserUUID: userUUID,
-			Offset:   0,
+			UserUUID: targetUUID,
+			Offset:   5,
 			Limit:    10,
 		}
-		groups := []string{"soc-1", "soc-2"}
 		infoGroup := []model.SocietyWithOffsetData{
 			{
 				SocietyUUID: "soc-1",
 				Name:        "Alpha",
 				PhotoURL:    "http://url1",
 				FormatId:    1,
+				IsMember:    true,
+				MemberID:    20,
 			},
 			{
 				SocietyUUID: "soc-2",
 				Name:        "Beta",
 				PhotoURL:    "http://url2",
 				FormatId:    2,
+				IsMember:    false,
+				MemberID:    17,
 			},
 		}
 
@@ -741,19 +1106,2030 @@ func TestGetSocietyForUserWithOffset(t *testing.T) {
 
 		mockDBRepo.
 			EXPECT().
-			GetUserSocieties(gomock.Any(), uint64(10), uint64(0), userUUID).
-			Return(groups, nil)
+			GetUserSocieties(gomock.Any(), &model.UserSocietiesFilter{
+				UserUUID:   targetUUID,
+				CallerUUID: userUUID,
+				Limit:      10,
+				Offset:     5,
+			}).
+			Return(infoGroup, nil)
 
 		mockDBRepo.
 			EXPECT().
-			GetInfoSociety(gomock.Any(), groups).
-			Return(infoGroup, nil)
+			CountUserSocieties(gomock.Any(), targetUUID).
+			Return(int64(17), nil)
 
 		out, err := s.GetSocietyForUserWithOffset(ctx, in)
 		assert.NoError(t, err)
 		assert.Len(t, out.Societies, 2)
 		assert.Equal(t, "Alpha", out.Societies[0].Name)
 		assert.True(t, out.Societies[0].IsMember)
-		assert.Equal(t, int64(2), out.Total)
+		assert.False(t, out.Societies[1].IsMember)
+		assert.Equal(t, int64(17), out.Total)
+		assert.Empty(t, out.NextCursor)
+	})
+
+	t.Run("Success with cursor", func(t *testing.T) {
+		in := &society.GetSocietyForUserWithOffsetIn{
+			UserUUID: userUUID,
+			Offset:   100,
+			Limit:    1,
+			Cursor:   encodeCursor(20),
+		}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietyForUserWithOffset")
+
+		mockDBRepo.
+			EXPECT().
+			GetUserSocieties(gomock.Any(), &model.UserSocietiesFilter{
+				UserUUID:   userUUID,
+				CallerUUID: userUUID,
+				Limit:      1,
+				Offset:     100,
+				AfterID:    20,
+			}).
+			Return([]model.SocietyWithOffsetData{{SocietyUUID: "soc-2", IsMember: true, MemberID: 17}}, nil)
+
+		mockDBRepo.
+			EXPECT().
+			CountUserSocieties(gomock.Any(), userUUID).
+			Return(int64(2), nil)
+
+		out, err := s.GetSocietyForUserWithOffset(ctx, in)
+		assert.NoError(t, err)
+		assert.Len(t, out.Societies, 1)
+		assert.Equal(t, encodeCursor(17), out.NextCursor)
+	})
+
+	t.Run("Malformed cursor", func(t *testing.T) {
+		in := &society.GetSocietyForUserWithOffsetIn{
+			UserUUID: userUUID,
+			Limit:    1,
+			Cursor:   "%%%",
+		}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietyForUserWithOffset")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		_, err := s.GetSocietyForUserWithOffset(ctx, in)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetSocietiesByIDs(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	userUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, userUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+
+	t.Run("success: duplicates are queried once", func(t *testing.T) {
+		first := uuid.Generate().String()
+		second := uuid.Generate().String()
+		in := &society.GetSocietiesByIDsIn{SocietyUUIDs: []string{first, second, first}}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietiesByIDs")
+		mockDBRepo.
+			EXPECT().
+			GetSocietiesByIDs(ctx, []string{first, second}, userUUID).
+			Return([]model.SocietySummary{
+				{SocietyUUID: first, Name: "Alpha", CountSubscribe: 10, Role: 1},
+				{SocietyUUID: second, Name: "Beta", CountSubscribe: 3},
+			}, nil)
+
+		out, err := s.GetSocietiesByIDs(ctx, in)
+		assert.NoError(t, err)
+		assert.Len(t, out.Societies, 2)
+		assert.Equal(t, "Alpha", out.Societies[first].Name)
+		assert.Equal(t, int64(10), out.Societies[first].CountSubscribe)
+		assert.Equal(t, int64(1), out.Societies[first].Role)
+		assert.Equal(t, int64(0), out.Societies[second].Role)
+	})
+
+	t.Run("fail: batch is too large", func(t *testing.T) {
+		in := &society.GetSocietiesByIDsIn{}
+		for i := 0; i <= maxSocietiesBatch; i++ {
+			in.SocietyUUIDs = append(in.SocietyUUIDs, uuid.Generate().String())
+		}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietiesByIDs")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.GetSocietiesByIDs(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("fail: repository error", func(t *testing.T) {
+		societyUUID := uuid.Generate().String()
+		in := &society.GetSocietiesByIDsIn{SocietyUUIDs: []string{societyUUID}}
+
+		mockLogger.EXPECT().AddFuncName("GetSocietiesByIDs")
+		mockDBRepo.
+			EXPECT().
+			GetSocietiesByIDs(ctx, []string{societyUUID}, userUUID).
+			Return(nil, errors.New("db error"))
+		mockLogger.EXPECT().Error("failed to GetSocietiesByIDs from BD")
+
+		out, err := s.GetSocietiesByIDs(ctx, in)
+		assert.Nil(t, out)
+		assert.ErrorContains(t, err, "db error")
+	})
+}
+
+func TestServer_GetSocietyMemberCounts(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	ctx := context.WithValue(context.Background(), config.KeyUUID, uuid.Generate().String())
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	in := &society.GetSocietyMemberCountsIn{SocietyUUID: societyUUID}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyMemberCounts")
+		mockDBRepo.EXPECT().CountSubscribe(ctx, societyUUID).Return(int64(5), nil)
+		mockDBRepo.EXPECT().GetMemberCounters(ctx, societyUUID).Return([]model.MemberCounter{
+			{Role: 1, PaymentStatus: 1, Count: 1},
+			{Role: 4, PaymentStatus: 1, Count: 2},
+			{Role: 4, PaymentStatus: 2, Count: 2},
+			{Role: 4, PaymentStatus: 3, Count: 3},
+		}, nil)
+
+		out, err := s.GetSocietyMemberCounts(ctx, in)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), out.Total)
+		assert.Equal(t, map[int64]int64{1: 1, 4: 7}, out.ByRole)
+		assert.Equal(t, map[int64]int64{1: 3, 2: 2, 3: 3}, out.ByPaymentStatus)
+	})
+
+	t.Run("fail: GetMemberCounters error", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyMemberCounts")
+		mockDBRepo.EXPECT().CountSubscribe(ctx, societyUUID).Return(int64(5), nil)
+		mockDBRepo.EXPECT().GetMemberCounters(ctx, societyUUID).Return(nil, errors.New("db error"))
+		mockLogger.EXPECT().Error("failed to GetMemberCounters from BD")
+
+		out, err := s.GetSocietyMemberCounts(ctx, in)
+		assert.Nil(t, out)
+		assert.ErrorContains(t, err, "db error")
+	})
+}
+
+func TestServer_GetSocietyStats(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		day := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		mockLogger.EXPECT().AddFuncName("GetSocietyStats")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetMembershipGrowth(ctx, societyUUID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, since time.Time) ([]model.DailyMembership, error) {
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, -6), since, time.Minute)
+				return []model.DailyMembership{
+					{Day: day, Joins: 3, Leaves: 1},
+					{Day: day.AddDate(0, 0, 1)},
+				}, nil
+			})
+		mockDBRepo.EXPECT().GetRequestStats(ctx, societyUUID).Return(&model.RequestStats{
+			Pending: 4, Approved: 3, Rejected: 1, AvgApprovalSeconds: 90.5,
+		}, nil)
+		mockDBRepo.EXPECT().GetMemberCounters(ctx, societyUUID).Return([]model.MemberCounter{
+			{Role: 1, PaymentStatus: 1, Count: 1},
+			{Role: 4, PaymentStatus: 1, Count: 2},
+			{Role: 4, PaymentStatus: 3, Count: 1},
+		}, nil)
+
+		out, err := s.GetSocietyStats(ctx, &society.GetSocietyStatsIn{SocietyUUID: societyUUID, Days: 7})
+		require.NoError(t, err)
+		assert.Equal(t, []*society.DailyMembership{
+			{Date: "2024-10-01", Joins: 3, Leaves: 1},
+			{Date: "2024-10-02"},
+		}, out.Growth)
+		assert.Equal(t, int64(4), out.PendingRequests)
+		assert.Equal(t, 0.75, out.ApprovalRate)
+		assert.Equal(t, int64(90), out.AvgApprovalLatencySeconds)
+		assert.Equal(t, map[int64]int64{1: 1, 4: 3}, out.ByRole)
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyStats")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.GetSocietyStats(ctx, &society.GetSocietyStatsIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: too many days", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyStats")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.GetSocietyStats(ctx, &society.GetSocietyStatsIn{SocietyUUID: societyUUID, Days: 366})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetMembershipTimeline(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	createdAt := time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("success: own timeline", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMembershipTimeline")
+		mockDBRepo.EXPECT().GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{
+			UserUUID: peerUUID,
+			Limit:    defaultTimelineLimit,
+		}).Return([]model.MembershipEvent{{
+			ID:          7,
+			SocietyUUID: societyUUID,
+			UserUUID:    peerUUID,
+			EventType:   model.MemberEventJoin,
+			Role:        4,
+			CreatedAt:   createdAt,
+		}}, nil)
+
+		out, err := s.GetMembershipTimeline(ctx, &society.GetMembershipTimelineIn{UserUUID: peerUUID})
+		require.NoError(t, err)
+		assert.Equal(t, []*society.MembershipEvent{{
+			EventID:     7,
+			SocietyUUID: societyUUID,
+			UserUUID:    peerUUID,
+			EventType:   model.MemberEventJoin,
+			Role:        4,
+			CreatedAt:   "2024-10-01T12:30:00Z",
+		}}, out.Events)
+		assert.Empty(t, out.NextCursor)
+	})
+
+	t.Run("success: society timeline with next page", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMembershipTimeline")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetMembershipTimeline(ctx, &model.MembershipTimelineFilter{
+			SocietyUUID: societyUUID,
+			Limit:       2,
+			AfterID:     10,
+		}).Return([]model.MembershipEvent{
+			{ID: 9, EventType: model.MemberEventKick, ActorUUID: peerUUID, CreatedAt: createdAt},
+			{ID: 8, EventType: model.MemberEventRoleChange, Role: 2, PreviousRole: 4, CreatedAt: createdAt},
+		}, nil)
+
+		out, err := s.GetMembershipTimeline(ctx, &society.GetMembershipTimelineIn{
+			SocietyUUID: societyUUID,
+			Limit:       2,
+			Cursor:      encodeCursor(10),
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Events, 2)
+		assert.Equal(t, peerUUID, out.Events[0].ActorUUID)
+		assert.Equal(t, int64(4), out.Events[1].PreviousRole)
+		assert.Equal(t, encodeCursor(8), out.NextCursor)
+	})
+
+	t.Run("fail: another user's timeline", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMembershipTimeline")
+		mockLogger.EXPECT().Error("failed to peer requested another user's timeline")
+
+		out, err := s.GetMembershipTimeline(ctx, &society.GetMembershipTimelineIn{UserUUID: uuid.Generate().String()})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: member requests society timeline", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMembershipTimeline")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.GetMembershipTimeline(ctx, &society.GetMembershipTimelineIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: both user and society", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMembershipTimeline")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.GetMembershipTimeline(ctx, &society.GetMembershipTimelineIn{UserUUID: peerUUID, SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_SetSocietyRules(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyRules")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().SetSocietyRules(ctx, societyUUID, peerUUID, []model.SocietyRule{
+			{Title: "Be kind", Body: "No insults"},
+			{Title: "Stay on topic", Body: "Offtopic goes to #random"},
+		}).Return(int64(3), nil)
+
+		out, err := s.SetSocietyRules(ctx, &society.SetSocietyRulesIn{
+			SocietyUUID: societyUUID,
+			Rules: []*society.SocietyRule{
+				{Title: "Be kind", Body: "No insults"},
+				{Title: "Stay on topic", Body: "Offtopic goes to #random"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), out.Version)
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyRules")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.SetSocietyRules(ctx, &society.SetSocietyRulesIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: empty rule title", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyRules")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetSocietyRules(ctx, &society.SetSocietyRulesIn{
+			SocietyUUID: societyUUID,
+			Rules:       []*society.SocietyRule{{Title: " ", Body: "No insults"}},
+		})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_SetRequestTTL(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetRequestTTL")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().SetRequestTTL(ctx, societyUUID, int64(72)).Return(nil)
+
+		out, err := s.SetRequestTTL(ctx, &society.SetRequestTTLIn{SocietyUUID: societyUUID, TTLHours: 72})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("success: zero disables expiry", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetRequestTTL")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().SetRequestTTL(ctx, societyUUID, int64(0)).Return(nil)
+
+		_, err := s.SetRequestTTL(ctx, &society.SetRequestTTLIn{SocietyUUID: societyUUID})
+		require.NoError(t, err)
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetRequestTTL")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.SetRequestTTL(ctx, &society.SetRequestTTLIn{SocietyUUID: societyUUID, TTLHours: 72})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: negative ttl", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetRequestTTL")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetRequestTTL(ctx, &society.SetRequestTTLIn{SocietyUUID: societyUUID, TTLHours: -1})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_SetMaxMembers(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetMaxMembers")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().SetMaxMembers(ctx, societyUUID, int64(30)).Return(nil)
+
+		out, err := s.SetMaxMembers(ctx, &society.SetMaxMembersIn{SocietyUUID: societyUUID, MaxMembers: 30})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetMaxMembers")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.SetMaxMembers(ctx, &society.SetMaxMembersIn{SocietyUUID: societyUUID, MaxMembers: 30})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: limit is too large", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetMaxMembers")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetMaxMembers(ctx, &society.SetMaxMembersIn{SocietyUUID: societyUUID, MaxMembers: maxMembersLimit + 1})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_RemoveSocietyMember(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	memberUUID := uuid.Generate().String()
+	in := &society.RemoveSocietyMemberIn{SocietyUUID: societyUUID, UserUUID: memberUUID}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSocietyMember")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetRoleSocietyMembers(ctx, memberUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().RemoveSocietyMember(ctx, memberUUID, societyUUID).Return(nil)
+
+		out, err := s.RemoveSocietyMember(ctx, in)
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: admin cannot remove admin", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSocietyMember")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().GetRoleSocietyMembers(ctx, memberUUID, societyUUID).Return(2, nil)
+		mockLogger.EXPECT().Error("failed to member role is not lower than peer role")
+
+		out, err := s.RemoveSocietyMember(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSocietyMember")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.RemoveSocietyMember(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: member not found", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSocietyMember")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().GetRoleSocietyMembers(ctx, memberUUID, societyUUID).Return(0, fmt.Errorf("wrapped: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to GetRoleSocietyMembers from BD: member not found")
+
+		out, err := s.RemoveSocietyMember(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("fail: removing yourself", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveSocietyMember")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.RemoveSocietyMember(ctx, &society.RemoveSocietyMemberIn{SocietyUUID: societyUUID, UserUUID: peerUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_SetSocietyParent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	parentUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, parentUUID).Return(2, nil)
+		mockDBRepo.EXPECT().SetSocietyParent(ctx, societyUUID, parentUUID, true).Return(true, nil)
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID, ParentUUID: parentUUID, InheritModeration: true})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("success: unlink needs only the child's rights", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().SetSocietyParent(ctx, societyUUID, "", false).Return(true, nil)
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: not an admin of the parent", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, parentUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID, ParentUUID: parentUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: cycle", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, parentUUID).Return(1, nil)
+		mockDBRepo.EXPECT().SetSocietyParent(ctx, societyUUID, parentUUID, false).Return(false, nil)
+		mockLogger.EXPECT().Error("failed to society is an ancestor of the parent")
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID, ParentUUID: parentUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: hierarchy too deep", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, parentUUID).Return(1, nil)
+		mockDBRepo.EXPECT().SetSocietyParent(ctx, societyUUID, parentUUID, false).Return(false, fmt.Errorf("failed to execute query SetSocietyParent: %w", model.ErrHierarchyTooDeep))
+		mockLogger.EXPECT().Error("failed to SetSocietyParent from BD: hierarchy is too deep")
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID, ParentUUID: parentUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: own parent", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID, ParentUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("fail: inheritance without parent", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyParent")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetSocietyParent(ctx, &society.SetSocietyParentIn{SocietyUUID: societyUUID, InheritModeration: true})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetSocietiesByIDs_Parent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	chapterUUID := uuid.Generate().String()
+	parentUUID := uuid.Generate().String()
+
+	mockLogger.EXPECT().AddFuncName("GetSocietiesByIDs")
+	mockDBRepo.EXPECT().GetSocietiesByIDs(ctx, []string{chapterUUID}, peerUUID).Return([]model.SocietySummary{
+		{SocietyUUID: chapterUUID, Name: "Kazan chapter", ParentUUID: parentUUID, ParentName: "Chess club"},
+	}, nil)
+
+	out, err := s.GetSocietiesByIDs(ctx, &society.GetSocietiesByIDsIn{SocietyUUIDs: []string{chapterUUID}})
+	require.NoError(t, err)
+	require.Contains(t, out.Societies, chapterUUID)
+	assert.Equal(t, parentUUID, out.Societies[chapterUUID].ParentUUID)
+	assert.Equal(t, "Chess club", out.Societies[chapterUUID].ParentName)
+}
+
+func TestServer_CreateChannel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	in := &society.CreateChannelIn{SocietyUUID: societyUUID, Name: "backend", FormatID: 1, PostPermission: 2}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateChannel")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
+		mockDBRepo.EXPECT().CreateChannel(ctx, &model.Channel{
+			SocietyUUID:    societyUUID,
+			Name:           "backend",
+			FormatID:       1,
+			PostPermission: 2,
+		}, peerUUID, maxChannelsCount).Return("channel-uuid", nil)
+
+		out, err := s.CreateChannel(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, "channel-uuid", out.ChannelUUID)
+	})
+
+	t.Run("fail: name is taken", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateChannel")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
+		mockDBRepo.EXPECT().CreateChannel(ctx, gomock.Any(), peerUUID, maxChannelsCount).Return("", fmt.Errorf("failed to insert society_channels: %w", model.ErrChannelNameTaken))
+		mockLogger.EXPECT().Error("failed to channel name is taken")
+
+		out, err := s.CreateChannel(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("fail: too many channels", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateChannel")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(true, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
+		mockDBRepo.EXPECT().CreateChannel(ctx, gomock.Any(), peerUUID, maxChannelsCount).Return("", model.ErrTooManyChannels)
+		mockLogger.EXPECT().Error("failed to society has too many channels")
+
+		out, err := s.CreateChannel(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateChannel")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.CreateChannel(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: unknown format", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateChannel")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().IsFormatExists(ctx, int64(1)).Return(false, nil)
+		mockDBRepo.EXPECT().IsPostPermissionExists(ctx, int64(2)).Return(true, nil)
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.CreateChannel(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_JoinChannel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	channelUUID := uuid.Generate().String()
+	openChannel := &model.Channel{ID: channelUUID, SocietyUUID: societyUUID, FormatID: 1}
+	closedChannel := &model.Channel{ID: channelUUID, SocietyUUID: societyUUID, FormatID: 2}
+
+	t.Run("success: member joins open channel", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("JoinChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(openChannel, nil)
+		mockDBRepo.EXPECT().AddChannelMember(ctx, channelUUID, peerUUID).Return(nil)
+
+		out, err := s.JoinChannel(ctx, &society.JoinChannelIn{ChannelUUID: channelUUID})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("success: moderator adds member to closed channel", func(t *testing.T) {
+		memberUUID := uuid.Generate().String()
+		mockLogger.EXPECT().AddFuncName("JoinChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(closedChannel, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().AddChannelMember(ctx, channelUUID, memberUUID).Return(nil)
+
+		_, err := s.JoinChannel(ctx, &society.JoinChannelIn{ChannelUUID: channelUUID, UserUUID: memberUUID})
+		require.NoError(t, err)
+	})
+
+	t.Run("fail: member cannot join closed channel", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("JoinChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(closedChannel, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.JoinChannel(ctx, &society.JoinChannelIn{ChannelUUID: channelUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: not a society member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("JoinChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(openChannel, nil)
+		mockDBRepo.EXPECT().AddChannelMember(ctx, channelUUID, peerUUID).Return(fmt.Errorf("wrapped: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to AddChannelMember from BD: user is not a society member")
+
+		out, err := s.JoinChannel(ctx, &society.JoinChannelIn{ChannelUUID: channelUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: channel not found", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("JoinChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(nil, fmt.Errorf("wrapped: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to GetChannel from BD: channel not found")
+
+		out, err := s.JoinChannel(ctx, &society.JoinChannelIn{ChannelUUID: channelUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_LeaveChannel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	channelUUID := uuid.Generate().String()
+
+	t.Run("success: leave yourself", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("LeaveChannel")
+		mockDBRepo.EXPECT().RemoveChannelMember(ctx, channelUUID, peerUUID).Return(nil)
+
+		out, err := s.LeaveChannel(ctx, &society.LeaveChannelIn{ChannelUUID: channelUUID})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: member cannot remove others", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("LeaveChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(&model.Channel{ID: channelUUID, SocietyUUID: societyUUID}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.LeaveChannel(ctx, &society.LeaveChannelIn{ChannelUUID: channelUUID, UserUUID: uuid.Generate().String()})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: not a channel member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("LeaveChannel")
+		mockDBRepo.EXPECT().RemoveChannelMember(ctx, channelUUID, peerUUID).Return(fmt.Errorf("wrapped: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to RemoveChannelMember from BD: member not found")
+
+		out, err := s.LeaveChannel(ctx, &society.LeaveChannelIn{ChannelUUID: channelUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_RemoveChannel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	channelUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(&model.Channel{ID: channelUUID, SocietyUUID: societyUUID}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().RemoveChannel(ctx, channelUUID).Return(nil)
+
+		out, err := s.RemoveChannel(ctx, &society.RemoveChannelIn{ChannelUUID: channelUUID})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveChannel")
+		mockDBRepo.EXPECT().GetChannel(ctx, channelUUID, peerUUID).Return(&model.Channel{ID: channelUUID, SocietyUUID: societyUUID}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.RemoveChannel(ctx, &society.RemoveChannelIn{ChannelUUID: channelUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestServer_CreateAnnouncement(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		mockLogger.EXPECT().AddFuncName("CreateAnnouncement")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().CreateAnnouncement(ctx, &model.Announcement{
+			SocietyUUID: societyUUID,
+			AuthorUUID:  peerUUID,
+			Title:       "Meetup",
+			Body:        "Friday at 19:00",
+			ExpiresAt:   sql.NullTime{Time: expiresAt, Valid: true},
+		}).Return(int64(5), nil)
+
+		out, err := s.CreateAnnouncement(ctx, &society.CreateAnnouncementIn{
+			SocietyUUID: societyUUID,
+			Title:       "Meetup",
+			Body:        "Friday at 19:00",
+			ExpiresAt:   expiresAt.Format(time.RFC3339),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), out.AnnouncementID)
+	})
+
+	t.Run("fail: member is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateAnnouncement")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.CreateAnnouncement(ctx, &society.CreateAnnouncementIn{SocietyUUID: societyUUID, Title: "Meetup", Body: "Friday"})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: expiry in the past", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateAnnouncement")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.CreateAnnouncement(ctx, &society.CreateAnnouncementIn{
+			SocietyUUID: societyUUID,
+			Title:       "Meetup",
+			Body:        "Friday",
+			ExpiresAt:   time.Now().Add(-time.Hour).Format(time.RFC3339),
+		})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_RemoveAnnouncement(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	in := &society.RemoveAnnouncementIn{SocietyUUID: societyUUID, AnnouncementID: 5}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveAnnouncement")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().RemoveAnnouncement(ctx, societyUUID, int64(5)).Return(nil)
+
+		out, err := s.RemoveAnnouncement(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	t.Run("fail: not found", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RemoveAnnouncement")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().RemoveAnnouncement(ctx, societyUUID, int64(5)).Return(fmt.Errorf("failed to execute query RemoveAnnouncement: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to RemoveAnnouncement from BD: announcement not found")
+
+		out, err := s.RemoveAnnouncement(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_SetSocietyQuestions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyQuestions")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockDBRepo.EXPECT().SetSocietyQuestions(ctx, societyUUID, []model.SocietyQuestion{
+			{Kind: model.QuestionText, Prompt: "About you", IsRequired: true},
+			{Kind: model.QuestionMultiChoice, Prompt: "Stack", Options: []string{"Go", "C"}},
+		}).Return(nil)
+
+		out, err := s.SetSocietyQuestions(ctx, &society.SetSocietyQuestionsIn{
+			SocietyUUID: societyUUID,
+			Questions: []*society.SocietyQuestion{
+				{Kind: model.QuestionText, Prompt: "About you", IsRequired: true},
+				{Kind: model.QuestionMultiChoice, Prompt: "Stack", Options: []string{"Go", "C"}},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	t.Run("fail: admin is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyQuestions")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner")
+
+		out, err := s.SetSocietyQuestions(ctx, &society.SetSocietyQuestionsIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: invalid questions", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetSocietyQuestions")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetSocietyQuestions(ctx, &society.SetSocietyQuestionsIn{
+			SocietyUUID: societyUUID,
+			Questions: []*society.SocietyQuestion{
+				{Kind: "rating", Prompt: "Stars"},
+				{Kind: model.QuestionSingleChoice, Prompt: "Campus", Options: []string{"Kazan"}},
+				{Kind: model.QuestionText, Prompt: "About you", Options: []string{"x"}},
+			},
+		})
+		assert.Nil(t, out)
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Len(t, st.Details()[0].(*errdetails.BadRequest).FieldViolations, 3)
+	})
+}
+
+func TestServer_GetSocietyRequests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	applicantUUID := uuid.Generate().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyRequests")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetPendingRequests(ctx, &model.PendingRequestsFilter{
+			SocietyUUID: societyUUID,
+			Limit:       1,
+		}).Return([]model.MembersRequest{{
+			ID:                   4,
+			UserUUID:             applicantUUID,
+			AcceptedRulesVersion: 2,
+			Answers:              []model.RequestAnswer{{QuestionID: 10, Prompt: "Campus", Values: []string{"Kazan"}}},
+			CreatedAt:            time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC),
+		}}, nil)
+
+		out, err := s.GetSocietyRequests(ctx, &society.GetSocietyRequestsIn{SocietyUUID: societyUUID, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []*society.MembersRequest{{
+			RequestID:            4,
+			UserUUID:             applicantUUID,
+			AcceptedRulesVersion: 2,
+			Answers:              []*society.RequestAnswer{{QuestionID: 10, Prompt: "Campus", Values: []string{"Kazan"}}},
+			CreatedAt:            "2024-10-01T09:00:00Z",
+		}}, out.Requests)
+		assert.Equal(t, encodeCursor(4), out.NextCursor)
+	})
+
+	t.Run("fail: member is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyRequests")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.GetSocietyRequests(ctx, &society.GetSocietyRequestsIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestServer_SetAutoApprovalRules(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	otherSocietyUUID := uuid.Generate().String()
+
+	t.Run("success: only the parameter of the rule kind is kept", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetAutoApprovalRules")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().SetAutoApprovalRules(ctx, societyUUID, []model.AutoApprovalRule{
+			{Kind: model.AutoApproveMemberOf, MemberOfSocietyUUID: otherSocietyUUID},
+			{Kind: model.AutoApproveDailyLimit, DailyLimit: 10},
+		}).Return(nil)
+
+		out, err := s.SetAutoApprovalRules(ctx, &society.SetAutoApprovalRulesIn{
+			SocietyUUID: societyUUID,
+			Rules: []*society.AutoApprovalRule{
+				{Kind: model.AutoApproveMemberOf, MemberOfSocietyUUID: otherSocietyUUID, InviteCode: "ignored"},
+				{Kind: model.AutoApproveDailyLimit, DailyLimit: 10},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &society.EmptySociety{}, out)
+	})
+
+	t.Run("fail: moderator is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetAutoApprovalRules")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner or Admin")
+
+		out, err := s.SetAutoApprovalRules(ctx, &society.SetAutoApprovalRulesIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: invalid rules", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetAutoApprovalRules")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetAutoApprovalRules(ctx, &society.SetAutoApprovalRulesIn{
+			SocietyUUID: societyUUID,
+			Rules: []*society.AutoApprovalRule{
+				{Kind: model.AutoApproveMemberOf, MemberOfSocietyUUID: societyUUID},
+				{Kind: model.AutoApproveInviteCode, InviteCode: "abc"},
+				{Kind: model.AutoApproveDailyLimit, DailyLimit: 1},
+				{Kind: model.AutoApproveDailyLimit, DailyLimit: 2},
+			},
+		})
+		assert.Nil(t, out)
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Len(t, st.Details()[0].(*errdetails.BadRequest).FieldViolations, 3)
+	})
+}
+
+func TestServer_CreateEvent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	eventUUID := uuid.Generate().String()
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	in := &society.CreateEventIn{
+		SocietyUUID: societyUUID,
+		Title:       "Go meetup",
+		StartsAt:    start.Format(time.RFC3339),
+		EndsAt:      start.Add(2 * time.Hour).Format(time.RFC3339),
+		Location:    " Kazan, room 42 ",
+		Capacity:    30,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateEvent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().CreateEvent(ctx, &model.SocietyEvent{
+			SocietyUUID: societyUUID,
+			AuthorUUID:  peerUUID,
+			Title:       "Go meetup",
+			StartsAt:    start,
+			EndsAt:      start.Add(2 * time.Hour),
+			Location:    "Kazan, room 42",
+			Capacity:    30,
+		}).Return(eventUUID, nil)
+
+		out, err := s.CreateEvent(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, eventUUID, out.EventUUID)
+	})
+
+	t.Run("fail: member is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateEvent")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.CreateEvent(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: invalid event", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreateEvent")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.CreateEvent(ctx, &society.CreateEventIn{
+			SocietyUUID: societyUUID,
+			Title:       "Go meetup",
+			StartsAt:    start.Format(time.RFC3339),
+			EndsAt:      start.Add(-time.Hour).Format(time.RFC3339),
+			Link:        "ftp://example.com",
+		})
+		assert.Nil(t, out)
+		st := status.Convert(err)
+		require.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		badRequest := st.Details()[0].(*errdetails.BadRequest)
+		fields := make([]string, 0, len(badRequest.FieldViolations))
+		for _, violation := range badRequest.FieldViolations {
+			fields = append(fields, violation.Field)
+		}
+		assert.ElementsMatch(t, []string{"EndsAt", "Link"}, fields)
+	})
+}
+
+func TestServer_RSVPEvent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	eventUUID := uuid.Generate().String()
+	event := &model.SocietyEvent{
+		ID:          eventUUID,
+		SocietyUUID: uuid.Generate().String(),
+		StartsAt:    time.Now().Add(time.Hour),
+		EndsAt:      time.Now().Add(2 * time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RSVPEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(event, nil)
+		mockDBRepo.EXPECT().SetEventRSVP(ctx, eventUUID, peerUUID, true).Return(true, nil)
+
+		out, err := s.RSVPEvent(ctx, &society.RSVPEventIn{EventUUID: eventUUID, Going: true})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: event is full", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RSVPEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(event, nil)
+		mockDBRepo.EXPECT().SetEventRSVP(ctx, eventUUID, peerUUID, true).Return(false, nil)
+		mockLogger.EXPECT().Error("failed to event is full")
+
+		out, err := s.RSVPEvent(ctx, &society.RSVPEventIn{EventUUID: eventUUID, Going: true})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: not a society member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RSVPEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(event, nil)
+		mockDBRepo.EXPECT().SetEventRSVP(ctx, eventUUID, peerUUID, true).Return(false, fmt.Errorf("failed to lock society member: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to SetEventRSVP from BD: user is not a society member")
+
+		out, err := s.RSVPEvent(ctx, &society.RSVPEventIn{EventUUID: eventUUID, Going: true})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: event is cancelled", func(t *testing.T) {
+		cancelled := *event
+		cancelled.CancelledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockLogger.EXPECT().AddFuncName("RSVPEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(&cancelled, nil)
+		mockLogger.EXPECT().Error("failed to event is cancelled")
+
+		out, err := s.RSVPEvent(ctx, &society.RSVPEventIn{EventUUID: eventUUID, Going: true})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("success: leaving a cancelled event", func(t *testing.T) {
+		cancelled := *event
+		cancelled.CancelledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		mockLogger.EXPECT().AddFuncName("RSVPEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(&cancelled, nil)
+		mockDBRepo.EXPECT().SetEventRSVP(ctx, eventUUID, peerUUID, false).Return(true, nil)
+
+		out, err := s.RSVPEvent(ctx, &society.RSVPEventIn{EventUUID: eventUUID})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: event not found", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("RSVPEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(nil, sql.ErrNoRows)
+		mockLogger.EXPECT().Error("failed to GetEvent from BD: event not found")
+
+		out, err := s.RSVPEvent(ctx, &society.RSVPEventIn{EventUUID: eventUUID, Going: true})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_UpdateEvent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	eventUUID := uuid.Generate().String()
+	societyUUID := uuid.Generate().String()
+	start := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	in := &society.UpdateEventIn{
+		EventUUID: eventUUID,
+		Title:     "Hackathon",
+		StartsAt:  start.Format(time.RFC3339),
+		EndsAt:    start.Add(26 * time.Hour).Format(time.RFC3339),
+		Link:      "https://meet.example.com/hack",
+	}
+
+	t.Run("success: extends an ongoing event", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("UpdateEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(&model.SocietyEvent{ID: eventUUID, SocietyUUID: societyUUID}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(2, nil)
+		mockDBRepo.EXPECT().UpdateEvent(ctx, &model.SocietyEvent{
+			ID:       eventUUID,
+			Title:    "Hackathon",
+			StartsAt: start,
+			EndsAt:   start.Add(26 * time.Hour),
+			Link:     "https://meet.example.com/hack",
+		}).Return(nil)
+
+		out, err := s.UpdateEvent(ctx, in)
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: cancelled event", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("UpdateEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(&model.SocietyEvent{
+			ID:          eventUUID,
+			SocietyUUID: societyUUID,
+			CancelledAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(1, nil)
+		mockLogger.EXPECT().Error("failed to event is cancelled")
+
+		out, err := s.UpdateEvent(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestServer_ExportEventsICS(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	start := time.Date(2026, 11, 2, 15, 0, 0, 0, time.UTC)
+	events := []model.SocietyEvent{{
+		ID:       uuid.Generate().String(),
+		Title:    "Go meetup",
+		StartsAt: start,
+		EndsAt:   start.Add(2 * time.Hour),
+		Location: "Kazan",
+	}}
+
+	t.Run("society calendar", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("ExportEventsICS")
+		mockDBRepo.EXPECT().GetSocietyInfo(ctx, societyUUID).Return(&model.SocietyInfo{Name: "Chess club"}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockDBRepo.EXPECT().GetSocietyEvents(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, filter *model.SocietyEventsFilter) ([]model.SocietyEvent, error) {
+				assert.Equal(t, societyUUID, filter.SocietyUUID)
+				assert.Equal(t, uint64(maxCalendarEvents), filter.Limit)
+				return events, nil
+			})
+
+		out, err := s.ExportEventsICS(ctx, &society.ExportEventsICSIn{SocietyUUID: societyUUID})
+		require.NoError(t, err)
+		assert.Contains(t, out.Calendar, "X-WR-CALNAME:Chess club\r\n")
+		assert.Contains(t, out.Calendar, "DTSTART:20261102T150000Z\r\n")
+	})
+
+	t.Run("upcoming events of the user", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("ExportEventsICS")
+		mockDBRepo.EXPECT().GetUpcomingEvents(ctx, gomock.Any()).Return(events, nil)
+
+		out, err := s.ExportEventsICS(ctx, &society.ExportEventsICSIn{})
+		require.NoError(t, err)
+		assert.NotContains(t, out.Calendar, "X-WR-CALNAME")
+		assert.Contains(t, out.Calendar, "SUMMARY:Go meetup\r\n")
+	})
+
+	t.Run("fail: society not found", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("ExportEventsICS")
+		mockDBRepo.EXPECT().GetSocietyInfo(ctx, societyUUID).Return(nil, sql.ErrNoRows)
+		mockLogger.EXPECT().Error("failed to GetSocietyInfo from BD: society not found")
+
+		out, err := s.ExportEventsICS(ctx, &society.ExportEventsICSIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestEventsToICS(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 11, 2, 18, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	calendar := eventsToICS("Chess club", []model.SocietyEvent{
+		{
+			ID:          "e1",
+			SocietyName: "Chess club",
+			Title:       "Blitz; rapid, classic",
+			Description: "Bring boards\nand clocks",
+			StartsAt:    start,
+			EndsAt:      start.Add(2 * time.Hour),
+			Location:    strings.Repeat("Казань ", 15),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		{
+			ID:          "e2",
+			Title:       "Cancelled",
+			StartsAt:    start,
+			EndsAt:      start.Add(time.Hour),
+			Link:        "https://meet.example.com/chess",
+			CancelledAt: sql.NullTime{Time: now, Valid: true},
+		},
+	}, now)
+
+	require.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(calendar, "\r\n", ""), "\n", "every line ends with CRLF")
+
+	lines := strings.Split(strings.TrimSuffix(calendar, "\r\n"), "\r\n")
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 75, line)
+		assert.True(t, utf8.ValidString(line), "folding keeps multi-byte characters whole")
+	}
+
+	unfolded := strings.ReplaceAll(calendar, "\r\n ", "")
+	assert.Contains(t, unfolded, "UID:e1@society-service.s21platform\r\n")
+	assert.Contains(t, unfolded, "DTSTAMP:20261019T093000Z\r\n")
+	assert.Contains(t, unfolded, "DTSTART:20261102T150000Z\r\n", "times are exported in UTC")
+	assert.Contains(t, unfolded, `SUMMARY:Blitz\; rapid\, classic`+"\r\n")
+	assert.Contains(t, unfolded, `DESCRIPTION:Bring boards\nand clocks`+"\r\n")
+	assert.Contains(t, unfolded, "LOCATION:"+strings.Repeat("Казань ", 15)+"\r\n")
+	assert.Contains(t, unfolded, "URL:https://meet.example.com/chess\r\n")
+	assert.Equal(t, 1, strings.Count(unfolded, "STATUS:CANCELLED\r\n"))
+	assert.Equal(t, 1, strings.Count(unfolded, "STATUS:CONFIRMED\r\n"))
+}
+
+func TestServer_CreatePoll(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	pollUUID := uuid.Generate().String()
+	closesAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	in := &society.CreatePollIn{
+		SocietyUUID: societyUUID,
+		Question:    "Which day for the meetup?",
+		Options:     []string{"Friday", " Saturday "},
+		ClosesAt:    closesAt.Format(time.RFC3339),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreatePoll")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().CreatePoll(ctx, &model.Poll{
+			SocietyUUID:       societyUUID,
+			AuthorUUID:        peerUUID,
+			Question:          "Which day for the meetup?",
+			ResultsVisibility: model.PollResultsLive,
+			ClosesAt:          sql.NullTime{Time: closesAt, Valid: true},
+			Options:           []model.PollOption{{Text: "Friday"}, {Text: "Saturday"}},
+		}).Return(pollUUID, nil)
+
+		out, err := s.CreatePoll(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, pollUUID, out.PollUUID)
+	})
+
+	t.Run("fail: member is not allowed", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreatePoll")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+		mockLogger.EXPECT().Error("failed to peer is not Owner, Admin or Moderator")
+
+		out, err := s.CreatePoll(ctx, in)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("fail: invalid options", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreatePoll")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.CreatePoll(ctx, &society.CreatePollIn{
+			SocietyUUID:       societyUUID,
+			Question:          "Which day?",
+			Options:           []string{"Friday", "Friday "},
+			ResultsVisibility: "never",
+		})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("fail: closes in the past", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("CreatePoll")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		past := *in
+		past.ClosesAt = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		out, err := s.CreatePoll(ctx, &past)
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_VotePoll(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	pollUUID := uuid.Generate().String()
+	poll := &model.Poll{
+		ID:                pollUUID,
+		SocietyUUID:       uuid.Generate().String(),
+		ResultsVisibility: model.PollResultsLive,
+		Options:           []model.PollOption{{ID: 11, Text: "Friday"}, {ID: 12, Text: "Saturday"}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("VotePoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockDBRepo.EXPECT().VotePoll(ctx, pollUUID, peerUUID, []int64{12}).Return(true, nil)
+
+		out, err := s.VotePoll(ctx, &society.VotePollIn{PollUUID: pollUUID, OptionIDs: []int64{12}})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: already voted", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("VotePoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockDBRepo.EXPECT().VotePoll(ctx, pollUUID, peerUUID, []int64{11}).Return(false, nil)
+		mockLogger.EXPECT().Error("failed to user has already voted")
+
+		out, err := s.VotePoll(ctx, &society.VotePollIn{PollUUID: pollUUID, OptionIDs: []int64{11}})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("fail: not a society member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("VotePoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockDBRepo.EXPECT().VotePoll(ctx, pollUUID, peerUUID, []int64{11}).Return(false, fmt.Errorf("failed to lock society member: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to VotePoll from BD: poll is closed or user is not a society member")
+
+		out, err := s.VotePoll(ctx, &society.VotePollIn{PollUUID: pollUUID, OptionIDs: []int64{11}})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("fail: several options in a single choice poll", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("VotePoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.VotePoll(ctx, &society.VotePollIn{PollUUID: pollUUID, OptionIDs: []int64{11, 12}})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("fail: option of another poll", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("VotePoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.VotePoll(ctx, &society.VotePollIn{PollUUID: pollUUID, OptionIDs: []int64{99}})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("fail: poll is closed", func(t *testing.T) {
+		closed := *poll
+		closed.ClosesAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+		mockLogger.EXPECT().AddFuncName("VotePoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(&closed, nil)
+		mockLogger.EXPECT().Error("failed to poll is closed")
+
+		out, err := s.VotePoll(ctx, &society.VotePollIn{PollUUID: pollUUID, OptionIDs: []int64{11}})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestServer_GetPoll_ResultsAfterClose(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	pollUUID := uuid.Generate().String()
+	societyUUID := uuid.Generate().String()
+	poll := &model.Poll{
+		ID:                pollUUID,
+		SocietyUUID:       societyUUID,
+		ResultsVisibility: model.PollResultsAfterClose,
+		VoterCount:        1,
+		Options: []model.PollOption{
+			{ID: 11, Text: "Friday", VoteCount: 1, VoterUUIDs: []string{peerUUID}},
+			{ID: 12, Text: "Saturday"},
+		},
+	}
+
+	t.Run("member sees no votes while the poll is open", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetPoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+
+		out, err := s.GetPoll(ctx, &society.GetPollIn{PollUUID: pollUUID})
+		require.NoError(t, err)
+		assert.True(t, out.Poll.ResultsHidden)
+		assert.Equal(t, int64(1), out.Poll.VoterCount)
+		assert.Zero(t, out.Poll.Options[0].VoteCount)
+		assert.Empty(t, out.Poll.Options[0].VoterUUIDs)
+	})
+
+	t.Run("moderator sees votes", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetPoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(poll, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+
+		out, err := s.GetPoll(ctx, &society.GetPollIn{PollUUID: pollUUID})
+		require.NoError(t, err)
+		assert.False(t, out.Poll.ResultsHidden)
+		assert.Equal(t, int64(1), out.Poll.Options[0].VoteCount)
+	})
+
+	t.Run("members see votes after closing", func(t *testing.T) {
+		closed := *poll
+		closed.ClosesAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+		mockLogger.EXPECT().AddFuncName("GetPoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(&closed, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(4, nil)
+
+		out, err := s.GetPoll(ctx, &society.GetPollIn{PollUUID: pollUUID})
+		require.NoError(t, err)
+		assert.True(t, out.Poll.IsClosed)
+		assert.False(t, out.Poll.ResultsHidden)
+		assert.Equal(t, []string{peerUUID}, out.Poll.Options[0].VoterUUIDs)
+	})
+}
+
+func TestServer_SocietyContent_NonMember(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	pollUUID := uuid.Generate().String()
+	eventUUID := uuid.Generate().String()
+
+	t.Run("GetPoll", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetPoll")
+		mockDBRepo.EXPECT().GetPoll(ctx, pollUUID, peerUUID).Return(&model.Poll{ID: pollUUID, SocietyUUID: societyUUID}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(0, nil)
+		mockLogger.EXPECT().Error("failed to peer is not a member of the society")
+
+		out, err := s.GetPoll(ctx, &society.GetPollIn{PollUUID: pollUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("GetSocietyPolls", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyPolls")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(0, nil)
+		mockLogger.EXPECT().Error("failed to peer is not a member of the society")
+
+		out, err := s.GetSocietyPolls(ctx, &society.GetSocietyPollsIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("GetEvent", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetEvent")
+		mockDBRepo.EXPECT().GetEvent(ctx, eventUUID, peerUUID).Return(&model.SocietyEvent{ID: eventUUID, SocietyUUID: societyUUID}, nil)
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(0, nil)
+		mockLogger.EXPECT().Error("failed to peer is not a member of the society")
+
+		out, err := s.GetEvent(ctx, &society.GetEventIn{EventUUID: eventUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("GetSocietyEvents", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyEvents")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(0, nil)
+		mockLogger.EXPECT().Error("failed to peer is not a member of the society")
+
+		out, err := s.GetSocietyEvents(ctx, &society.GetSocietyEventsIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("inherited moderator reads polls", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetSocietyPolls")
+		mockDBRepo.EXPECT().IsOwnerAdminModerator(ctx, peerUUID, societyUUID).Return(3, nil)
+		mockDBRepo.EXPECT().GetSocietyPolls(ctx, gomock.Any()).Return(nil, nil)
+
+		out, err := s.GetSocietyPolls(ctx, &society.GetSocietyPollsIn{SocietyUUID: societyUUID})
+		require.NoError(t, err)
+		assert.Empty(t, out.Polls)
+	})
+}
+
+func TestServer_SetNotificationSettings(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	mutedUntil := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	t.Run("success: mute until tomorrow", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetNotificationSettings")
+		mockDBRepo.EXPECT().SetNotificationSettings(ctx, &model.NotificationSettings{
+			SocietyUUID:   societyUUID,
+			UserUUID:      peerUUID,
+			Announcements: true,
+			Delivery:      model.NotifyDeliveryInstant,
+			Muted:         true,
+			MutedUntil:    sql.NullTime{Time: mutedUntil, Valid: true},
+		}).Return(nil)
+
+		out, err := s.SetNotificationSettings(ctx, &society.SetNotificationSettingsIn{
+			SocietyUUID:   societyUUID,
+			Announcements: true,
+			Muted:         true,
+			MutedUntil:    mutedUntil.Format(time.RFC3339),
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("fail: not a society member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetNotificationSettings")
+		mockDBRepo.EXPECT().SetNotificationSettings(ctx, gomock.Any()).Return(fmt.Errorf("failed to execute query SetNotificationSettings: %w", sql.ErrNoRows))
+		mockLogger.EXPECT().Error("failed to SetNotificationSettings from BD: member not found")
+
+		out, err := s.SetNotificationSettings(ctx, &society.SetNotificationSettingsIn{SocietyUUID: societyUUID, Delivery: model.NotifyDeliveryDigest})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("fail: mutedUntil without muted", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetNotificationSettings")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetNotificationSettings(ctx, &society.SetNotificationSettingsIn{
+			SocietyUUID: societyUUID,
+			MutedUntil:  mutedUntil.Format(time.RFC3339),
+		})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("fail: unknown delivery", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("SetNotificationSettings")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.SetNotificationSettings(ctx, &society.SetNotificationSettingsIn{SocietyUUID: societyUUID, Delivery: "weekly"})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetNotificationSettings(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	peerUUID := uuid.Generate().String()
+	ctx := context.WithValue(context.Background(), config.KeyUUID, peerUUID)
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+
+	t.Run("expired mute is reported as unmuted", func(t *testing.T) {
+		settings := model.DefaultNotificationSettings(societyUUID, peerUUID)
+		settings.Muted = true
+		settings.MutedUntil = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+		mockLogger.EXPECT().AddFuncName("GetNotificationSettings")
+		mockDBRepo.EXPECT().GetNotificationSettings(ctx, societyUUID, peerUUID).Return(&settings, nil)
+
+		out, err := s.GetNotificationSettings(ctx, &society.GetNotificationSettingsIn{SocietyUUID: societyUUID})
+		require.NoError(t, err)
+		assert.False(t, out.Settings.Muted)
+		assert.Empty(t, out.Settings.MutedUntil)
+		assert.True(t, out.Settings.Posts)
+		assert.Equal(t, model.NotifyDeliveryInstant, out.Settings.Delivery)
+	})
+
+	t.Run("fail: not a society member", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetNotificationSettings")
+		mockDBRepo.EXPECT().GetNotificationSettings(ctx, societyUUID, peerUUID).Return(nil, sql.ErrNoRows)
+		mockLogger.EXPECT().Error("failed to GetNotificationSettings from BD: member not found")
+
+		out, err := s.GetNotificationSettings(ctx, &society.GetNotificationSettingsIn{SocietyUUID: societyUUID})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_GetNotificationRecipients(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDBRepo := NewMockDbRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	s := &Server{dbR: mockDBRepo}
+
+	ctx := context.WithValue(context.Background(), config.KeyUUID, uuid.Generate().String())
+	ctx = context.WithValue(ctx, config.KeyLogger, mockLogger)
+	societyUUID := uuid.Generate().String()
+	authorUUID := uuid.Generate().String()
+	recipients := []model.NotificationRecipient{
+		{MemberID: 9, UserUUID: uuid.Generate().String(), Role: 4, Delivery: model.NotifyDeliveryDigest},
+		{MemberID: 7, UserUUID: uuid.Generate().String(), Role: 1, Delivery: model.NotifyDeliveryInstant},
+	}
+
+	t.Run("success: full page returns a cursor", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetNotificationRecipients")
+		mockDBRepo.EXPECT().GetNotificationRecipients(ctx, &model.NotificationRecipientsFilter{
+			SocietyUUID: societyUUID,
+			Activity:    model.NotifyPosts,
+			ExcludeUUID: authorUUID,
+			Limit:       2,
+			AfterID:     12,
+		}).Return(recipients, nil)
+
+		out, err := s.GetNotificationRecipients(ctx, &society.GetNotificationRecipientsIn{
+			SocietyUUID:     societyUUID,
+			Activity:        model.NotifyPosts,
+			ExcludeUserUUID: authorUUID,
+			Limit:           2,
+			Cursor:          encodeCursor(12),
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Recipients, 2)
+		assert.Equal(t, recipients[0].UserUUID, out.Recipients[0].UserUUID)
+		assert.Equal(t, model.NotifyDeliveryDigest, out.Recipients[0].Delivery)
+		assert.Equal(t, encodeCursor(7), out.NextCursor)
+	})
+
+	t.Run("success: default limit", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetNotificationRecipients")
+		mockDBRepo.EXPECT().GetNotificationRecipients(ctx, &model.NotificationRecipientsFilter{
+			SocietyUUID: societyUUID,
+			Activity:    model.NotifyJoinRequests,
+			Limit:       defaultRecipients,
+		}).Return(recipients[1:], nil)
+
+		out, err := s.GetNotificationRecipients(ctx, &society.GetNotificationRecipientsIn{
+			SocietyUUID: societyUUID,
+			Activity:    model.NotifyJoinRequests,
+		})
+		require.NoError(t, err)
+		assert.Len(t, out.Recipients, 1)
+		assert.Empty(t, out.NextCursor)
+	})
+
+	t.Run("fail: unknown activity and role", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetNotificationRecipients")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		out, err := s.GetNotificationRecipients(ctx, &society.GetNotificationRecipientsIn{
+			SocietyUUID: societyUUID,
+			Activity:    "comments",
+			Roles:       []int64{5},
+		})
+		assert.Nil(t, out)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 }
@@ -0,0 +1,945 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	society "github.com/s21platform/society-proto/society-proto"
+	"github.com/s21platform/society-service/internal/model"
+)
+
+const (
+	maxNameLength        = 255 // society.name VARCHAR(255)
+	maxDescriptionLength = 4096
+	maxSocietiesBatch    = 100
+	defaultSocietiesPage = 50
+	maxSocietiesPage     = 100
+	defaultStatsDays     = 30
+	maxStatsDays         = 365
+	defaultTimelineLimit = 50
+	maxTimelineLimit     = 100
+	maxRulesCount        = 50
+	maxTitleLength       = 255 // society_rules.title и society_announcements.title VARCHAR(255)
+	maxBodyLength        = 4096
+	maxQuestionsCount    = 20
+	maxOptionsCount      = 20
+	defaultRequestsLimit = 50
+	maxRequestsLimit     = 100
+	maxAutoApprovalRules = 10
+	maxDailyLimit        = 10000
+	minInviteCodeLength  = 6
+	maxRequestTTLHours   = 24 * 365
+	maxMembersLimit      = 100000
+	maxChannelsCount     = 50
+	maxLocationLength    = 255  // society_events.location VARCHAR(255)
+	maxLinkLength        = 2048 // society_events.link VARCHAR(2048)
+	maxEventCapacity     = maxMembersLimit
+	maxEventDuration     = 30 * 24 * time.Hour
+	defaultEventsLimit   = 50
+	maxEventsLimit       = 100
+	maxCalendarEvents    = 500
+	defaultPollsLimit    = 50
+	maxPollsLimit        = 100
+	defaultRecipients    = 500
+	maxRecipients        = 5000
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validator собирает нарушения по полям запроса и превращает их в InvalidArgument с errdetails.BadRequest
+type validator struct {
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+func (v *validator) addViolation(field, description string) {
+	v.violations = append(v.violations, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+func (v *validator) checkUUID(field, value, emptyDescription string) {
+	if value == "" {
+		v.addViolation(field, emptyDescription)
+		return
+	}
+	if !uuidRegexp.MatchString(value) {
+		v.addViolation(field, fmt.Sprintf("%s is not a valid uuid", field))
+	}
+}
+
+func (v *validator) checkName(field, value string) {
+	if value == "" {
+		v.addViolation(field, "name not provided")
+		return
+	}
+	if utf8.RuneCountInString(value) > maxNameLength {
+		v.addViolation(field, fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+}
+
+func (v *validator) checkDescription(field, value string) {
+	if utf8.RuneCountInString(value) > maxDescriptionLength {
+		v.addViolation(field, fmt.Sprintf("description must be at most %d characters", maxDescriptionLength))
+	}
+}
+
+func (v *validator) checkText(field, value string, maxLength int) {
+	if strings.TrimSpace(value) == "" {
+		v.addViolation(field, fmt.Sprintf("%s not provided", field))
+		return
+	}
+	if utf8.RuneCountInString(value) > maxLength {
+		v.addViolation(field, fmt.Sprintf("%s must be at most %d characters", field, maxLength))
+	}
+}
+
+func (v *validator) checkPositive(field string, value int64) {
+	if value <= 0 {
+		v.addViolation(field, fmt.Sprintf("%s must be > 0, got %d", field, value))
+	}
+}
+
+func (v *validator) checkNonNegative(field string, value int64) {
+	if value < 0 {
+		v.addViolation(field, fmt.Sprintf("invalid value: must be >= 0, got %s = %d", field, value))
+	}
+}
+
+// checkTime разбирает необязательную метку RFC 3339; пустая строка даёт нулевое время
+func (v *validator) checkTime(field, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		v.addViolation(field, fmt.Sprintf("%s must be an RFC 3339 timestamp", field))
+	}
+	return parsed
+}
+
+func (v *validator) checkLink(field, value string) {
+	if len(value) > maxLinkLength {
+		v.addViolation(field, fmt.Sprintf("%s must be at most %d bytes", field, maxLinkLength))
+		return
+	}
+	link, err := url.Parse(value)
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+		v.addViolation(field, fmt.Sprintf("%s must be an absolute http(s) URL", field))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, 0, len(v.violations))
+	for _, violation := range v.violations {
+		descriptions = append(descriptions, violation.Description)
+	}
+
+	st := status.New(codes.InvalidArgument, strings.Join(descriptions, "; "))
+	withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v.violations})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// checkSocietySettings проверяет, что формат и права на посты существуют в справочниках
+func (s *Server) checkSocietySettings(ctx context.Context, v *validator, formatID, postPermissionID int64) error {
+	formatExists, err := s.dbR.IsFormatExists(ctx, formatID)
+	if err != nil {
+		return fmt.Errorf("failed to check format: %w", err)
+	}
+	if !formatExists {
+		v.addViolation("FormatID", fmt.Sprintf("format %d does not exist", formatID))
+	}
+
+	postPermissionExists, err := s.dbR.IsPostPermissionExists(ctx, postPermissionID)
+	if err != nil {
+		return fmt.Errorf("failed to check post permission: %w", err)
+	}
+	if !postPermissionExists {
+		v.addViolation("PostPermission", fmt.Sprintf("post permission %d does not exist", postPermissionID))
+	}
+
+	return nil
+}
+
+func validateSetSocietyIn(in *society.SetSocietyIn) *validator {
+	v := &validator{}
+	v.checkName("Name", in.Name)
+	v.checkPositive("FormatID", in.FormatID)
+	v.checkPositive("PostPermissionID", in.PostPermissionID)
+	return v
+}
+
+func validateGetSocietyInfoIn(in *society.GetSocietyInfoIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateUpdateSocietyIn(in *society.UpdateSocietyIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkName("Name", in.Name)
+	v.checkDescription("Description", in.Description)
+	v.checkPositive("FormatID", in.FormatID)
+	v.checkPositive("PostPermission", in.PostPermission)
+	return v
+}
+
+func validateRemoveSocietyIn(in *society.RemoveSocietyIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateSubscribeToSocietyIn(in *society.SubscribeToSocietyIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkNonNegative("AcceptedRulesVersion", in.AcceptedRulesVersion)
+	if utf8.RuneCountInString(in.InviteCode) > maxTitleLength {
+		v.addViolation("InviteCode", fmt.Sprintf("inviteCode must be at most %d characters", maxTitleLength))
+	}
+	return v
+}
+
+func validateUnSubscribeToSocietyIn(in *society.UnSubscribeToSocietyIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateGetSocietyForUserWithOffsetIn(in *society.GetSocietyForUserWithOffsetIn) *validator {
+	v := &validator{}
+	v.checkUUID("UserUUID", in.UserUUID, "userUUID not provided")
+	v.checkNonNegative("Limit", in.Limit)
+	if in.Limit > maxSocietiesPage {
+		v.addViolation("Limit", fmt.Sprintf("limit must be at most %d, got %d", maxSocietiesPage, in.Limit))
+	}
+	v.checkNonNegative("Offset", in.Offset)
+	if in.Cursor != "" {
+		if _, err := decodeCursor(in.Cursor); err != nil {
+			v.addViolation("Cursor", "cursor is malformed")
+		}
+	}
+	return v
+}
+
+func validateGetSocietyMemberCountsIn(in *society.GetSocietyMemberCountsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateGetSocietiesByIDsIn(in *society.GetSocietiesByIDsIn) *validator {
+	v := &validator{}
+	if len(in.SocietyUUIDs) == 0 {
+		v.addViolation("SocietyUUIDs", "societyUUIDs not provided")
+	}
+	if len(in.SocietyUUIDs) > maxSocietiesBatch {
+		v.addViolation("SocietyUUIDs", fmt.Sprintf("at most %d societies per request, got %d", maxSocietiesBatch, len(in.SocietyUUIDs)))
+	}
+	for i, societyUUID := range in.SocietyUUIDs {
+		v.checkUUID(fmt.Sprintf("SocietyUUIDs[%d]", i), societyUUID, "societyUUID not provided")
+	}
+	return v
+}
+
+func validateGetSocietyStatsIn(in *society.GetSocietyStatsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkNonNegative("Days", in.Days)
+	if in.Days > maxStatsDays {
+		v.addViolation("Days", fmt.Sprintf("days must be at most %d, got %d", maxStatsDays, in.Days))
+	}
+	return v
+}
+
+func validateGetMembershipTimelineIn(in *society.GetMembershipTimelineIn) *validator {
+	v := &validator{}
+	switch {
+	case in.UserUUID == "" && in.SocietyUUID == "":
+		v.addViolation("UserUUID", "either userUUID or societyUUID must be provided")
+	case in.UserUUID != "" && in.SocietyUUID != "":
+		v.addViolation("SocietyUUID", "only one of userUUID and societyUUID may be provided")
+	case in.UserUUID != "":
+		v.checkUUID("UserUUID", in.UserUUID, "userUUID not provided")
+	default:
+		v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	}
+	v.checkNonNegative("Limit", in.Limit)
+	if in.Limit > maxTimelineLimit {
+		v.addViolation("Limit", fmt.Sprintf("limit must be at most %d, got %d", maxTimelineLimit, in.Limit))
+	}
+	if in.Cursor != "" {
+		if _, err := decodeCursor(in.Cursor); err != nil {
+			v.addViolation("Cursor", "cursor is malformed")
+		}
+	}
+	return v
+}
+
+func validateSetSocietyRulesIn(in *society.SetSocietyRulesIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	if len(in.Rules) > maxRulesCount {
+		v.addViolation("Rules", fmt.Sprintf("at most %d rules per society, got %d", maxRulesCount, len(in.Rules)))
+	}
+	for i, rule := range in.Rules {
+		if rule == nil {
+			v.addViolation(fmt.Sprintf("Rules[%d]", i), "rule not provided")
+			continue
+		}
+		v.checkText(fmt.Sprintf("Rules[%d].Title", i), rule.Title, maxTitleLength)
+		v.checkText(fmt.Sprintf("Rules[%d].Body", i), rule.Body, maxBodyLength)
+	}
+	return v
+}
+
+func validateGetSocietyRulesIn(in *society.GetSocietyRulesIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+// validateCreateAnnouncementIn возвращает разобранный срок действия; пустой ExpiresAt — бессрочное объявление
+func validateCreateAnnouncementIn(in *society.CreateAnnouncementIn, now time.Time) (*validator, *time.Time) {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkText("Title", in.Title, maxTitleLength)
+	v.checkText("Body", in.Body, maxBodyLength)
+	if in.ExpiresAt == "" {
+		return v, nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, in.ExpiresAt)
+	if err != nil {
+		v.addViolation("ExpiresAt", "expiresAt must be an RFC 3339 timestamp")
+		return v, nil
+	}
+	if !expiresAt.After(now) {
+		v.addViolation("ExpiresAt", "expiresAt must be in the future")
+	}
+	return v, &expiresAt
+}
+
+func validateRemoveAnnouncementIn(in *society.RemoveAnnouncementIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkPositive("AnnouncementID", in.AnnouncementID)
+	return v
+}
+
+func validateSetSocietyQuestionsIn(in *society.SetSocietyQuestionsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	if len(in.Questions) > maxQuestionsCount {
+		v.addViolation("Questions", fmt.Sprintf("at most %d questions per society, got %d", maxQuestionsCount, len(in.Questions)))
+	}
+	for i, question := range in.Questions {
+		field := fmt.Sprintf("Questions[%d]", i)
+		if question == nil {
+			v.addViolation(field, "question not provided")
+			continue
+		}
+		v.checkText(field+".Prompt", question.Prompt, maxTitleLength)
+
+		switch question.Kind {
+		case model.QuestionText:
+			if len(question.Options) > 0 {
+				v.addViolation(field+".Options", "text questions have no options")
+			}
+		case model.QuestionSingleChoice, model.QuestionMultiChoice:
+			if len(question.Options) < 2 || len(question.Options) > maxOptionsCount {
+				v.addViolation(field+".Options", fmt.Sprintf("choice questions need 2 to %d options, got %d", maxOptionsCount, len(question.Options)))
+			}
+			seen := make(map[string]struct{}, len(question.Options))
+			for j, option := range question.Options {
+				v.checkText(fmt.Sprintf("%s.Options[%d]", field, j), option, maxTitleLength)
+				if _, ok := seen[option]; ok {
+					v.addViolation(fmt.Sprintf("%s.Options[%d]", field, j), fmt.Sprintf("option %q is duplicated", option))
+				}
+				seen[option] = struct{}{}
+			}
+		default:
+			v.addViolation(field+".Kind", fmt.Sprintf("kind must be one of %s, %s, %s", model.QuestionText, model.QuestionSingleChoice, model.QuestionMultiChoice))
+		}
+	}
+	return v
+}
+
+func validateGetSocietyQuestionsIn(in *society.GetSocietyQuestionsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateGetSocietyRequestsIn(in *society.GetSocietyRequestsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkNonNegative("Limit", in.Limit)
+	if in.Limit > maxRequestsLimit {
+		v.addViolation("Limit", fmt.Sprintf("limit must be at most %d, got %d", maxRequestsLimit, in.Limit))
+	}
+	if in.Cursor != "" {
+		if _, err := decodeCursor(in.Cursor); err != nil {
+			v.addViolation("Cursor", "cursor is malformed")
+		}
+	}
+	return v
+}
+
+// validateAnswers сверяет ответы заявки с текущей анкетой и возвращает их в порядке вопросов
+// вместе с текстом вопроса, чтобы заявка оставалась понятной после правки анкеты
+func validateAnswers(questions []model.SocietyQuestion, answers []*society.QuestionAnswer) (*validator, []model.RequestAnswer) {
+	v := &validator{}
+
+	byID := make(map[int64][]string, len(answers))
+	for i, answer := range answers {
+		field := fmt.Sprintf("Answers[%d]", i)
+		if answer == nil {
+			v.addViolation(field, "answer not provided")
+			continue
+		}
+		if _, ok := byID[answer.QuestionID]; ok {
+			v.addViolation(field, fmt.Sprintf("question %d is answered twice", answer.QuestionID))
+			continue
+		}
+		byID[answer.QuestionID] = answer.Values
+	}
+
+	var result []model.RequestAnswer
+	for _, question := range questions {
+		field := "Answers"
+		values, ok := byID[question.ID]
+		delete(byID, question.ID)
+		if question.Kind == model.QuestionText && len(values) == 1 && strings.TrimSpace(values[0]) == "" {
+			values = nil
+		}
+		if !ok || len(values) == 0 {
+			if question.IsRequired {
+				v.addViolation(field, fmt.Sprintf("answer to question %q is required", question.Prompt))
+			}
+			continue
+		}
+
+		switch question.Kind {
+		case model.QuestionText:
+			if len(values) != 1 {
+				v.addViolation(field, "text answer must have exactly one value")
+			} else if utf8.RuneCountInString(values[0]) > maxBodyLength {
+				v.addViolation(field, fmt.Sprintf("answer must be at most %d characters", maxBodyLength))
+			}
+		case model.QuestionSingleChoice, model.QuestionMultiChoice:
+			if question.Kind == model.QuestionSingleChoice && len(values) != 1 {
+				v.addViolation(field, "single choice answer must have exactly one value")
+			}
+			seen := make(map[string]struct{}, len(values))
+			for _, value := range values {
+				if !slices.Contains(question.Options, value) {
+					v.addViolation(field, fmt.Sprintf("%q is not an option of question %q", value, question.Prompt))
+				}
+				if _, dup := seen[value]; dup {
+					v.addViolation(field, fmt.Sprintf("option %q is chosen twice", value))
+				}
+				seen[value] = struct{}{}
+			}
+		}
+
+		result = append(result, model.RequestAnswer{
+			QuestionID: question.ID,
+			Prompt:     question.Prompt,
+			Values:     values,
+		})
+	}
+
+	for i, answer := range answers {
+		if answer == nil {
+			continue
+		}
+		if _, ok := byID[answer.QuestionID]; ok {
+			v.addViolation(fmt.Sprintf("Answers[%d]", i), fmt.Sprintf("question %d is not in the current form", answer.QuestionID))
+			delete(byID, answer.QuestionID)
+		}
+	}
+
+	return v, result
+}
+
+func validateSetAutoApprovalRulesIn(in *society.SetAutoApprovalRulesIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	if len(in.Rules) > maxAutoApprovalRules {
+		v.addViolation("Rules", fmt.Sprintf("at most %d auto approval rules per society, got %d", maxAutoApprovalRules, len(in.Rules)))
+	}
+
+	dailyLimits := 0
+	for i, rule := range in.Rules {
+		field := fmt.Sprintf("Rules[%d]", i)
+		if rule == nil {
+			v.addViolation(field, "rule not provided")
+			continue
+		}
+
+		switch rule.Kind {
+		case model.AutoApproveMemberOf:
+			v.checkUUID(field+".MemberOfSocietyUUID", rule.MemberOfSocietyUUID, "memberOfSocietyUUID not provided")
+			if rule.MemberOfSocietyUUID == in.SocietyUUID {
+				v.addViolation(field+".MemberOfSocietyUUID", "memberOfSocietyUUID must differ from societyUUID")
+			}
+		case model.AutoApproveDailyLimit:
+			dailyLimits++
+			if rule.DailyLimit <= 0 || rule.DailyLimit > maxDailyLimit {
+				v.addViolation(field+".DailyLimit", fmt.Sprintf("dailyLimit must be between 1 and %d, got %d", maxDailyLimit, rule.DailyLimit))
+			}
+		case model.AutoApproveInviteCode:
+			length := utf8.RuneCountInString(rule.InviteCode)
+			if length < minInviteCodeLength || length > maxTitleLength {
+				v.addViolation(field+".InviteCode", fmt.Sprintf("inviteCode must be %d to %d characters", minInviteCodeLength, maxTitleLength))
+			}
+		default:
+			v.addViolation(field+".Kind", fmt.Sprintf("kind must be one of %s, %s, %s", model.AutoApproveMemberOf, model.AutoApproveDailyLimit, model.AutoApproveInviteCode))
+		}
+	}
+	// квота общая на сообщество, два лимита сделали бы её неоднозначной
+	if dailyLimits > 1 {
+		v.addViolation("Rules", "at most one daily_limit rule per society")
+	}
+	return v
+}
+
+func validateGetAutoApprovalRulesIn(in *society.GetAutoApprovalRulesIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateSetMaxMembersIn(in *society.SetMaxMembersIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkNonNegative("MaxMembers", in.MaxMembers)
+	if in.MaxMembers > maxMembersLimit {
+		v.addViolation("MaxMembers", fmt.Sprintf("maxMembers must be at most %d, got %d", maxMembersLimit, in.MaxMembers))
+	}
+	return v
+}
+
+func validateRemoveSocietyMemberIn(in *society.RemoveSocietyMemberIn, peerUUID string) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkUUID("UserUUID", in.UserUUID, "userUUID not provided")
+	if in.UserUUID == peerUUID {
+		v.addViolation("UserUUID", "use UnSubscribeToSociety to leave the society")
+	}
+	return v
+}
+
+// ParentUUID необязателен: пустой отвязывает сообщество от родителя
+func validateSetSocietyParentIn(in *society.SetSocietyParentIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	if in.ParentUUID != "" {
+		v.checkUUID("ParentUUID", in.ParentUUID, "")
+		if in.ParentUUID == in.SocietyUUID {
+			v.addViolation("ParentUUID", "society cannot be its own parent")
+		}
+	} else if in.InheritModeration {
+		v.addViolation("InheritModeration", "moderation can only be inherited from a parent")
+	}
+	return v
+}
+
+func validateGetSocietyChildrenIn(in *society.GetSocietyChildrenIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateGetSocietyAncestorsIn(in *society.GetSocietyAncestorsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateCreateChannelIn(in *society.CreateChannelIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkName("Name", in.Name)
+	v.checkDescription("Description", in.Description)
+	v.checkPositive("FormatID", in.FormatID)
+	v.checkPositive("PostPermission", in.PostPermission)
+	return v
+}
+
+func validateGetChannelIn(in *society.GetChannelIn) *validator {
+	v := &validator{}
+	v.checkUUID("ChannelUUID", in.ChannelUUID, "channelUUID not provided")
+	return v
+}
+
+func validateGetSocietyChannelsIn(in *society.GetSocietyChannelsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+func validateUpdateChannelIn(in *society.UpdateChannelIn) *validator {
+	v := &validator{}
+	v.checkUUID("ChannelUUID", in.ChannelUUID, "channelUUID not provided")
+	v.checkName("Name", in.Name)
+	v.checkDescription("Description", in.Description)
+	v.checkPositive("FormatID", in.FormatID)
+	v.checkPositive("PostPermission", in.PostPermission)
+	return v
+}
+
+func validateRemoveChannelIn(in *society.RemoveChannelIn) *validator {
+	v := &validator{}
+	v.checkUUID("ChannelUUID", in.ChannelUUID, "channelUUID not provided")
+	return v
+}
+
+// UserUUID необязателен: без него действие выполняется для самого пользователя
+func validateJoinChannelIn(in *society.JoinChannelIn) *validator {
+	v := &validator{}
+	v.checkUUID("ChannelUUID", in.ChannelUUID, "channelUUID not provided")
+	if in.UserUUID != "" {
+		v.checkUUID("UserUUID", in.UserUUID, "")
+	}
+	return v
+}
+
+func validateLeaveChannelIn(in *society.LeaveChannelIn) *validator {
+	v := &validator{}
+	v.checkUUID("ChannelUUID", in.ChannelUUID, "channelUUID not provided")
+	if in.UserUUID != "" {
+		v.checkUUID("UserUUID", in.UserUUID, "")
+	}
+	return v
+}
+
+func validateSetRequestTTLIn(in *society.SetRequestTTLIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkNonNegative("TTLHours", in.TTLHours)
+	if in.TTLHours > maxRequestTTLHours {
+		v.addViolation("TTLHours", fmt.Sprintf("ttlHours must be at most %d, got %d", maxRequestTTLHours, in.TTLHours))
+	}
+	return v
+}
+
+// validateEvent проверяет поля, общие для создания и изменения события
+func validateEvent(v *validator, title, description, startsAt, endsAt, location, link string, capacity int64) *model.SocietyEvent {
+	v.checkText("Title", title, maxTitleLength)
+	v.checkDescription("Description", description)
+
+	event := &model.SocietyEvent{
+		Title:       title,
+		Description: description,
+		Location:    strings.TrimSpace(location),
+		Link:        link,
+		Capacity:    capacity,
+	}
+	if startsAt == "" {
+		v.addViolation("StartsAt", "startsAt not provided")
+	}
+	if endsAt == "" {
+		v.addViolation("EndsAt", "endsAt not provided")
+	}
+	event.StartsAt = v.checkTime("StartsAt", startsAt)
+	event.EndsAt = v.checkTime("EndsAt", endsAt)
+	if !event.StartsAt.IsZero() && !event.EndsAt.IsZero() {
+		if !event.EndsAt.After(event.StartsAt) {
+			v.addViolation("EndsAt", "endsAt must be after startsAt")
+		} else if event.EndsAt.Sub(event.StartsAt) > maxEventDuration {
+			v.addViolation("EndsAt", fmt.Sprintf("event must last at most %s", maxEventDuration))
+		}
+	}
+
+	if event.Location == "" && link == "" {
+		v.addViolation("Location", "either location or link must be provided")
+	}
+	if utf8.RuneCountInString(event.Location) > maxLocationLength {
+		v.addViolation("Location", fmt.Sprintf("Location must be at most %d characters", maxLocationLength))
+	}
+	if link != "" {
+		v.checkLink("Link", link)
+	}
+
+	v.checkNonNegative("Capacity", capacity)
+	if capacity > maxEventCapacity {
+		v.addViolation("Capacity", fmt.Sprintf("capacity must be at most %d, got %d", maxEventCapacity, capacity))
+	}
+	return event
+}
+
+func validateCreateEventIn(in *society.CreateEventIn, now time.Time) (*validator, *model.SocietyEvent) {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	event := validateEvent(v, in.Title, in.Description, in.StartsAt, in.EndsAt, in.Location, in.Link, in.Capacity)
+	if !event.StartsAt.IsZero() && !event.StartsAt.After(now) {
+		v.addViolation("StartsAt", "startsAt must be in the future")
+	}
+	event.SocietyUUID = in.SocietyUUID
+	return v, event
+}
+
+// UpdateEventIn заменяет все поля; начало уже идущего события можно оставить в прошлом
+func validateUpdateEventIn(in *society.UpdateEventIn) (*validator, *model.SocietyEvent) {
+	v := &validator{}
+	v.checkUUID("EventUUID", in.EventUUID, "eventUUID not provided")
+	event := validateEvent(v, in.Title, in.Description, in.StartsAt, in.EndsAt, in.Location, in.Link, in.Capacity)
+	event.ID = in.EventUUID
+	return v, event
+}
+
+func validateCancelEventIn(in *society.CancelEventIn) *validator {
+	v := &validator{}
+	v.checkUUID("EventUUID", in.EventUUID, "eventUUID not provided")
+	return v
+}
+
+func validateGetEventIn(in *society.GetEventIn) *validator {
+	v := &validator{}
+	v.checkUUID("EventUUID", in.EventUUID, "eventUUID not provided")
+	return v
+}
+
+// validateEventsPeriod заполняет период выборки: From по умолчанию — now, To необязателен
+func validateEventsPeriod(v *validator, from, to string, limit int64, now time.Time) *model.SocietyEventsFilter {
+	filter := &model.SocietyEventsFilter{
+		From:  v.checkTime("From", from),
+		To:    v.checkTime("To", to),
+		Limit: uint64(limit),
+	}
+	if from == "" {
+		filter.From = now
+	}
+	if !filter.To.IsZero() && !filter.To.After(filter.From) {
+		v.addViolation("To", "to must be after from")
+	}
+
+	v.checkNonNegative("Limit", limit)
+	if limit > maxEventsLimit {
+		v.addViolation("Limit", fmt.Sprintf("limit must be at most %d, got %d", maxEventsLimit, limit))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultEventsLimit
+	}
+	return filter
+}
+
+func validateGetSocietyEventsIn(in *society.GetSocietyEventsIn, now time.Time) (*validator, *model.SocietyEventsFilter) {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	filter := validateEventsPeriod(v, in.From, in.To, in.Limit, now)
+	filter.SocietyUUID = in.SocietyUUID
+	return v, filter
+}
+
+func validateGetUpcomingEventsIn(in *society.GetUpcomingEventsIn, now time.Time) (*validator, *model.SocietyEventsFilter) {
+	v := &validator{}
+	filter := validateEventsPeriod(v, in.From, in.To, in.Limit, now)
+	return v, filter
+}
+
+func validateRSVPEventIn(in *society.RSVPEventIn) *validator {
+	v := &validator{}
+	v.checkUUID("EventUUID", in.EventUUID, "eventUUID not provided")
+	return v
+}
+
+// SocietyUUID необязателен: без него выгружаются ближайшие события всех сообществ пользователя
+func validateExportEventsICSIn(in *society.ExportEventsICSIn) *validator {
+	v := &validator{}
+	if in.SocietyUUID != "" {
+		v.checkUUID("SocietyUUID", in.SocietyUUID, "")
+	}
+	return v
+}
+
+func validateCreatePollIn(in *society.CreatePollIn, now time.Time) (*validator, *model.Poll) {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkText("Question", in.Question, maxTitleLength)
+
+	poll := &model.Poll{
+		SocietyUUID:       in.SocietyUUID,
+		Question:          in.Question,
+		IsMultiple:        in.IsMultiple,
+		IsAnonymous:       in.IsAnonymous,
+		ResultsVisibility: in.ResultsVisibility,
+	}
+	if poll.ResultsVisibility == "" {
+		poll.ResultsVisibility = model.PollResultsLive
+	}
+	if poll.ResultsVisibility != model.PollResultsLive && poll.ResultsVisibility != model.PollResultsAfterClose {
+		v.addViolation("ResultsVisibility", fmt.Sprintf("resultsVisibility must be one of %s, %s", model.PollResultsLive, model.PollResultsAfterClose))
+	}
+
+	if len(in.Options) < 2 || len(in.Options) > maxOptionsCount {
+		v.addViolation("Options", fmt.Sprintf("polls need 2 to %d options, got %d", maxOptionsCount, len(in.Options)))
+	}
+	seen := make(map[string]struct{}, len(in.Options))
+	for i, option := range in.Options {
+		field := fmt.Sprintf("Options[%d]", i)
+		v.checkText(field, option, maxTitleLength)
+		option = strings.TrimSpace(option)
+		if _, ok := seen[option]; ok {
+			v.addViolation(field, fmt.Sprintf("option %q is duplicated", option))
+		}
+		seen[option] = struct{}{}
+		poll.Options = append(poll.Options, model.PollOption{Text: option})
+	}
+
+	if closesAt := v.checkTime("ClosesAt", in.ClosesAt); !closesAt.IsZero() {
+		if !closesAt.After(now) {
+			v.addViolation("ClosesAt", "closesAt must be in the future")
+		}
+		poll.ClosesAt = sql.NullTime{Time: closesAt, Valid: true}
+	}
+	return v, poll
+}
+
+func validateGetPollIn(in *society.GetPollIn) *validator {
+	v := &validator{}
+	v.checkUUID("PollUUID", in.PollUUID, "pollUUID not provided")
+	return v
+}
+
+func validateGetSocietyPollsIn(in *society.GetSocietyPollsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	v.checkNonNegative("Limit", in.Limit)
+	if in.Limit > maxPollsLimit {
+		v.addViolation("Limit", fmt.Sprintf("limit must be at most %d, got %d", maxPollsLimit, in.Limit))
+	}
+	return v
+}
+
+func validateVotePollIn(in *society.VotePollIn) *validator {
+	v := &validator{}
+	v.checkUUID("PollUUID", in.PollUUID, "pollUUID not provided")
+	if len(in.OptionIDs) == 0 {
+		v.addViolation("OptionIDs", "optionIDs not provided")
+	}
+	seen := make(map[int64]struct{}, len(in.OptionIDs))
+	for i, optionID := range in.OptionIDs {
+		if _, ok := seen[optionID]; ok {
+			v.addViolation(fmt.Sprintf("OptionIDs[%d]", i), fmt.Sprintf("option %d is duplicated", optionID))
+		}
+		seen[optionID] = struct{}{}
+	}
+	return v
+}
+
+// validatePollChoice сверяет голос с вариантами опроса: в опросе с одним ответом — ровно один вариант
+func validatePollChoice(optionIDs []int64, poll *model.Poll) *validator {
+	v := &validator{}
+	if !poll.IsMultiple && len(optionIDs) > 1 {
+		v.addViolation("OptionIDs", "this poll accepts a single option")
+	}
+
+	options := make(map[int64]struct{}, len(poll.Options))
+	for _, option := range poll.Options {
+		options[option.ID] = struct{}{}
+	}
+	for i, optionID := range optionIDs {
+		if _, ok := options[optionID]; !ok {
+			v.addViolation(fmt.Sprintf("OptionIDs[%d]", i), fmt.Sprintf("option %d does not belong to the poll", optionID))
+		}
+	}
+	return v
+}
+
+func validateClosePollIn(in *society.ClosePollIn) *validator {
+	v := &validator{}
+	v.checkUUID("PollUUID", in.PollUUID, "pollUUID not provided")
+	return v
+}
+
+func validateGetNotificationSettingsIn(in *society.GetNotificationSettingsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	return v
+}
+
+// SetNotificationSettingsIn заменяет все настройки; пустой Delivery — instant
+func validateSetNotificationSettingsIn(in *society.SetNotificationSettingsIn, now time.Time) (*validator, *model.NotificationSettings) {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+
+	settings := &model.NotificationSettings{
+		SocietyUUID:   in.SocietyUUID,
+		Posts:         in.Posts,
+		Announcements: in.Announcements,
+		Events:        in.Events,
+		JoinRequests:  in.JoinRequests,
+		Delivery:      in.Delivery,
+		Muted:         in.Muted,
+	}
+	if settings.Delivery == "" {
+		settings.Delivery = model.NotifyDeliveryInstant
+	}
+	if settings.Delivery != model.NotifyDeliveryInstant && settings.Delivery != model.NotifyDeliveryDigest {
+		v.addViolation("Delivery", fmt.Sprintf("delivery must be one of %s, %s", model.NotifyDeliveryInstant, model.NotifyDeliveryDigest))
+	}
+
+	if mutedUntil := v.checkTime("MutedUntil", in.MutedUntil); !mutedUntil.IsZero() {
+		if !in.Muted {
+			v.addViolation("MutedUntil", "mutedUntil requires muted")
+		}
+		if !mutedUntil.After(now) {
+			v.addViolation("MutedUntil", "mutedUntil must be in the future")
+		}
+		settings.MutedUntil = sql.NullTime{Time: mutedUntil, Valid: true}
+	}
+	return v, settings
+}
+
+func validateGetNotificationRecipientsIn(in *society.GetNotificationRecipientsIn) *validator {
+	v := &validator{}
+	v.checkUUID("SocietyUUID", in.SocietyUUID, "societyUUID not provided")
+	switch in.Activity {
+	case model.NotifyPosts, model.NotifyAnnouncements, model.NotifyEvents, model.NotifyJoinRequests:
+	case "":
+		v.addViolation("Activity", "activity not provided")
+	default:
+		v.addViolation("Activity", fmt.Sprintf("activity must be one of %s, %s, %s, %s",
+			model.NotifyPosts, model.NotifyAnnouncements, model.NotifyEvents, model.NotifyJoinRequests))
+	}
+	if in.ExcludeUserUUID != "" {
+		v.checkUUID("ExcludeUserUUID", in.ExcludeUserUUID, "")
+	}
+	for i, role := range in.Roles {
+		if role < 1 || role > 4 {
+			v.addViolation(fmt.Sprintf("Roles[%d]", i), fmt.Sprintf("role must be between 1 and 4, got %d", role))
+		}
+	}
+	v.checkNonNegative("Limit", in.Limit)
+	if in.Limit > maxRecipients {
+		v.addViolation("Limit", fmt.Sprintf("limit must be at most %d, got %d", maxRecipients, in.Limit))
+	}
+	if in.Cursor != "" {
+		if _, err := decodeCursor(in.Cursor); err != nil {
+			v.addViolation("Cursor", "cursor is malformed")
+		}
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE society ADD COLUMN IF NOT EXISTS member_count BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS society_member_counters (
+    society_id      UUID NOT NULL,
+    role            INT NOT NULL,
+    payment_status  INT NOT NULL,
+    count           BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (society_id, role, payment_status),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- member_count не учитывает участников с истёкшей оплатой (payment_members.id = 3)
+CREATE OR REPLACE FUNCTION society_members_count_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE society_member_counters SET count = count - 1
+        WHERE society_id = OLD.society_id AND role = OLD.role AND payment_status = OLD.payment_status;
+        IF OLD.payment_status <> 3 THEN
+            UPDATE society SET member_count = member_count - 1 WHERE id = OLD.society_id;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO society_member_counters (society_id, role, payment_status, count)
+        VALUES (NEW.society_id, NEW.role, NEW.payment_status, 1)
+        ON CONFLICT (society_id, role, payment_status) DO UPDATE SET count = society_member_counters.count + 1;
+        IF NEW.payment_status <> 3 THEN
+            UPDATE society SET member_count = member_count + 1 WHERE id = NEW.society_id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER society_members_count
+    AFTER INSERT OR DELETE OR UPDATE OF society_id, role, payment_status ON society_members
+    FOR EACH ROW EXECUTE FUNCTION society_members_count_trigger();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO society_member_counters (society_id, role, payment_status, count)
+SELECT society_id, role, payment_status, count(*)
+FROM society_members
+GROUP BY society_id, role, payment_status;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE society s SET member_count = a.count
+FROM (
+    SELECT society_id, count(*) AS count
+    FROM society_members
+    WHERE payment_status <> 3
+    GROUP BY society_id
+) a
+WHERE a.society_id = s.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS society_members_count ON society_members;
+DROP FUNCTION IF EXISTS society_members_count_trigger();
+DROP TABLE IF EXISTS society_member_counters CASCADE;
+ALTER TABLE society DROP COLUMN IF EXISTS member_count;
+-- +goose StatementEnd
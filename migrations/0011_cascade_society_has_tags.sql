@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- RemoveSocietyHasTagsEntry только деактивирует теги, поэтому без каскада удалить сообщество с тегами нельзя
+ALTER TABLE society_has_tags DROP CONSTRAINT IF EXISTS fk_society;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society_has_tags
+    ADD CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE society_has_tags DROP CONSTRAINT IF EXISTS fk_society;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society_has_tags
+    ADD CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id);
+-- +goose StatementEnd
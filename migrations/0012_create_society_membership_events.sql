@@ -0,0 +1,74 @@
+-- +goose Up
+-- +goose StatementBegin
+-- журнал изменений состава; без FK на society, чтобы история переживала удаление сообщества
+CREATE TABLE IF NOT EXISTS society_membership_events (
+    id                      BIGSERIAL PRIMARY KEY,
+    society_id              UUID NOT NULL,
+    user_uuid               UUID NOT NULL,
+    event_type              TEXT NOT NULL,
+    role                    INT NOT NULL,
+    payment_status          INT NOT NULL,
+    previous_role           INT,
+    previous_payment_status INT,
+    actor_uuid              TEXT,
+    created_at              TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_membership_events_society ON society_membership_events (society_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_membership_events_user ON society_membership_events (user_uuid, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- тип удаления (leave или kick) и автора изменения репозиторий передаёт через
+-- set_config('society.member_event', ..., true) и set_config('society.actor_uuid', ..., true)
+CREATE OR REPLACE FUNCTION society_members_history_trigger() RETURNS TRIGGER AS $$
+DECLARE
+    actor TEXT := NULLIF(current_setting('society.actor_uuid', true), '');
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO society_membership_events (society_id, user_uuid, event_type, role, payment_status, actor_uuid)
+        VALUES (NEW.society_id, NEW.user_uuid, 'join', NEW.role, NEW.payment_status, actor);
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO society_membership_events (society_id, user_uuid, event_type, role, payment_status, actor_uuid)
+        VALUES (OLD.society_id, OLD.user_uuid,
+                COALESCE(NULLIF(current_setting('society.member_event', true), ''), 'leave'),
+                OLD.role, OLD.payment_status, actor);
+    ELSE
+        IF NEW.role <> OLD.role THEN
+            INSERT INTO society_membership_events (society_id, user_uuid, event_type, role, payment_status, previous_role, actor_uuid)
+            VALUES (NEW.society_id, NEW.user_uuid, 'role_change', NEW.role, NEW.payment_status, OLD.role, actor);
+        END IF;
+        IF NEW.payment_status <> OLD.payment_status THEN
+            INSERT INTO society_membership_events (society_id, user_uuid, event_type, role, payment_status, previous_payment_status, actor_uuid)
+            VALUES (NEW.society_id, NEW.user_uuid, 'payment_change', NEW.role, NEW.payment_status, OLD.payment_status, actor);
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER society_members_history
+    AFTER INSERT OR DELETE OR UPDATE OF role, payment_status ON society_members
+    FOR EACH ROW EXECUTE FUNCTION society_members_history_trigger();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS society_members_history ON society_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS society_members_history_trigger();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_membership_events;
+-- +goose StatementEnd
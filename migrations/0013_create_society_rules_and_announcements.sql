@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+-- каждая правка правил создаёт новую версию, старые версии остаются для заявок, которые их приняли
+CREATE TABLE IF NOT EXISTS society_rules_versions (
+    society_id  UUID NOT NULL,
+    version     INT NOT NULL,
+    author_uuid UUID NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (society_id, version),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS society_rules (
+    society_id UUID NOT NULL,
+    version    INT NOT NULL,
+    position   INT NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    body       TEXT NOT NULL,
+    PRIMARY KEY (society_id, version, position),
+    CONSTRAINT fk_rules_version FOREIGN KEY (society_id, version)
+        REFERENCES society_rules_versions (society_id, version) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- 0 — сообщество без правил или заявка подана до их появления
+ALTER TABLE members_requests ADD COLUMN IF NOT EXISTS accepted_rules_version INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS society_announcements (
+    id          BIGSERIAL PRIMARY KEY,
+    society_id  UUID NOT NULL,
+    author_uuid UUID NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    body        TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_announcements_society ON society_announcements (society_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_announcements;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE members_requests DROP COLUMN IF EXISTS accepted_rules_version;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_rules;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_rules_versions;
+-- +goose StatementEnd
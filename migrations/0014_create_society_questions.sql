@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- анкета для заявок; правка анкеты пересоздаёт вопросы, поэтому у старых заявок ответы хранят текст вопроса
+CREATE TABLE IF NOT EXISTS society_questions (
+    id          BIGSERIAL PRIMARY KEY,
+    society_id  UUID NOT NULL,
+    position    INT NOT NULL,
+    kind        TEXT NOT NULL CHECK (kind IN ('text', 'single_choice', 'multi_choice')),
+    prompt      VARCHAR(255) NOT NULL,
+    options     TEXT[] NOT NULL DEFAULT '{}',
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE,
+    CONSTRAINT uq_society_questions_position UNIQUE (society_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS members_requests_answers (
+    request_id  INT NOT NULL,
+    question_id BIGINT NOT NULL,
+    position    INT NOT NULL,
+    prompt      VARCHAR(255) NOT NULL,
+    answer      TEXT[] NOT NULL,
+    PRIMARY KEY (request_id, question_id),
+    CONSTRAINT fk_members_requests FOREIGN KEY (request_id) REFERENCES members_requests (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS members_requests_answers;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_questions;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- правила проверяются по порядку position, заявку одобряет первое подошедшее
+CREATE TABLE IF NOT EXISTS society_auto_approval_rules (
+    id                BIGSERIAL PRIMARY KEY,
+    society_id        UUID NOT NULL,
+    position          INT NOT NULL,
+    kind              TEXT NOT NULL CHECK (kind IN ('member_of', 'daily_limit', 'invite_code')),
+    member_of_society UUID,
+    daily_limit       INT,
+    invite_code       VARCHAR(255),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE,
+    CONSTRAINT uq_society_auto_approval_rules_position UNIQUE (society_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- правило, одобрившее заявку; id без FK, чтобы решение переживало правку правил
+ALTER TABLE members_requests
+    ADD COLUMN IF NOT EXISTS auto_approval_rule_id BIGINT,
+    ADD COLUMN IF NOT EXISTS auto_approval_rule_kind TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_members_requests_auto_approval
+    ON members_requests (society_id, auto_approval_rule_kind, update_at)
+    WHERE auto_approval_rule_kind IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_members_requests_auto_approval;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE members_requests
+    DROP COLUMN IF EXISTS auto_approval_rule_kind,
+    DROP COLUMN IF EXISTS auto_approval_rule_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_auto_approval_rules;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO status_requests (id, status) VALUES (4, 'expired') ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+SELECT setval(pg_get_serial_sequence('status_requests', 'id'), (SELECT MAX(id) FROM status_requests));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- NULL — заявки сообщества не истекают
+ALTER TABLE society ADD COLUMN IF NOT EXISTS request_ttl_hours INT CHECK (request_ttl_hours > 0);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_members_requests_pending ON members_requests (society_id, create_at) WHERE status_id = 1;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- события для уведомлений пишутся в той же транзакции, что и изменение, и забираются отдельным отправщиком
+CREATE TABLE IF NOT EXISTS society_outbox (
+    id           BIGSERIAL PRIMARY KEY,
+    event_type   TEXT NOT NULL,
+    society_id   UUID NOT NULL,
+    user_uuid    UUID NOT NULL,
+    payload      JSONB NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_outbox_unpublished ON society_outbox (id) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_outbox;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_members_requests_pending;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society DROP COLUMN IF EXISTS request_ttl_hours;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE members_requests SET status_id = 1 WHERE status_id = 4;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM status_requests WHERE id = 4;
+-- +goose StatementEnd
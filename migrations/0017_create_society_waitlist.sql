@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL — число участников не ограничено
+ALTER TABLE society ADD COLUMN IF NOT EXISTS max_members INT CHECK (max_members > 0);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- очередь на вступление в заполненное сообщество; порядок задаёт id.
+-- вступление и выход блокируют строку society, поэтому очередь и member_count меняются согласованно
+CREATE TABLE IF NOT EXISTS society_waitlist (
+    id         BIGSERIAL PRIMARY KEY,
+    society_id UUID NOT NULL,
+    user_uuid  UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE,
+    CONSTRAINT uq_society_waitlist_user UNIQUE (society_id, user_uuid)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_waitlist_order ON society_waitlist (society_id, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- повторные вступления оставляли у пользователя несколько строк участника. Оставляем старшую роль
+-- (при равных — самую раннюю строку); счётчики поправит society_members_count, а в историю
+-- удаление дублей не попадает — пользователь из сообщества не выходил
+ALTER TABLE society_members DISABLE TRIGGER society_members_history;
+DELETE FROM society_members
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY society_id, user_uuid ORDER BY role, id) AS rn
+        FROM society_members
+    ) d
+    WHERE d.rn > 1
+);
+ALTER TABLE society_members ENABLE TRIGGER society_members_history;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society_members ADD CONSTRAINT uq_society_members_user UNIQUE (society_id, user_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE society_members DROP CONSTRAINT IF EXISTS uq_society_members_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_waitlist;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society DROP COLUMN IF EXISTS max_members;
+-- +goose StatementEnd
@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+-- канал — тематическое пространство внутри сообщества со своими участниками;
+-- format_id и post_permission_id значат то же, что у сообщества
+CREATE TABLE IF NOT EXISTS society_channels (
+    id                 UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    society_id         UUID NOT NULL,
+    name               VARCHAR(255) NOT NULL,
+    description        TEXT NOT NULL DEFAULT '',
+    format_id          INT NOT NULL,
+    post_permission_id INT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE,
+    CONSTRAINT fk_format_society FOREIGN KEY (format_id) REFERENCES format_society (id),
+    CONSTRAINT fk_post_permission FOREIGN KEY (post_permission_id) REFERENCES post_permission (id),
+    CONSTRAINT uq_society_channels_name UNIQUE (society_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS society_channel_members (
+    channel_id UUID NOT NULL,
+    society_id UUID NOT NULL,
+    user_uuid  UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (channel_id, user_uuid),
+    CONSTRAINT fk_society_channels FOREIGN KEY (channel_id) REFERENCES society_channels (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_channel_members_user ON society_channel_members (society_id, user_uuid);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- участники каналов — подмножество участников сообщества: выход или исключение из сообщества
+-- убирает пользователя из его каналов. Строка участника у пользователя одна (uq_society_members_user)
+CREATE OR REPLACE FUNCTION society_channel_members_cleanup_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM society_channel_members WHERE society_id = OLD.society_id AND user_uuid = OLD.user_uuid;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER society_channel_members_cleanup
+    AFTER DELETE ON society_members
+    FOR EACH ROW EXECUTE FUNCTION society_channel_members_cleanup_trigger();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS society_channel_members_cleanup ON society_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS society_channel_members_cleanup_trigger();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_channel_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_channels;
+-- +goose StatementEnd
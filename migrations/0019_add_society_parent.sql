@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- родительское сообщество (например, клуб школы для его кампусных отделений). Циклы не допускает
+-- SetSocietyParent; inherit_parent_moderation даёт владельцам и администраторам родителя права модератора
+ALTER TABLE society
+    ADD COLUMN IF NOT EXISTS parent_id UUID,
+    ADD COLUMN IF NOT EXISTS inherit_parent_moderation BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT fk_society_parent FOREIGN KEY (parent_id) REFERENCES society (id) ON DELETE SET NULL,
+    ADD CONSTRAINT chk_society_parent_not_self CHECK (parent_id <> id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_parent ON society (parent_id) WHERE parent_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_society_parent;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society
+    DROP CONSTRAINT IF EXISTS chk_society_parent_not_self,
+    DROP CONSTRAINT IF EXISTS fk_society_parent,
+    DROP COLUMN IF EXISTS inherit_parent_moderation,
+    DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
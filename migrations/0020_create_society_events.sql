@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+-- встречи и хакатоны сообщества. capacity NULL — без ограничения; отменённое событие
+-- остаётся в календаре с cancelled_at, чтобы подписчики .ics увидели отмену
+CREATE TABLE IF NOT EXISTS society_events (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    society_id   UUID NOT NULL,
+    author_uuid  UUID NOT NULL,
+    title        VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    starts_at    TIMESTAMPTZ NOT NULL,
+    ends_at      TIMESTAMPTZ NOT NULL,
+    location     VARCHAR(255) NOT NULL DEFAULT '',
+    link         VARCHAR(2048) NOT NULL DEFAULT '',
+    capacity     INT CHECK (capacity > 0),
+    cancelled_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE,
+    CONSTRAINT chk_society_events_period CHECK (ends_at > starts_at),
+    CONSTRAINT chk_society_events_place CHECK (location <> '' OR link <> '')
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_events_society_time ON society_events (society_id, starts_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS society_event_rsvps (
+    event_id   UUID NOT NULL,
+    user_uuid  UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_uuid),
+    CONSTRAINT fk_society_events FOREIGN KEY (event_id) REFERENCES society_events (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- бывший участник сообщества освобождает места на ещё не начавшихся событиях;
+-- отметки о прошедших остаются как история посещений
+CREATE OR REPLACE FUNCTION society_event_rsvps_cleanup_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM society_members WHERE society_id = OLD.society_id AND user_uuid = OLD.user_uuid) THEN
+        DELETE FROM society_event_rsvps r
+        USING society_events e
+        WHERE r.event_id = e.id
+          AND e.society_id = OLD.society_id
+          AND e.starts_at > NOW()
+          AND r.user_uuid = OLD.user_uuid;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER society_event_rsvps_cleanup
+    AFTER DELETE ON society_members
+    FOR EACH ROW EXECUTE FUNCTION society_event_rsvps_cleanup_trigger();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS society_event_rsvps_cleanup ON society_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS society_event_rsvps_cleanup_trigger();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_event_rsvps;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_events;
+-- +goose StatementEnd
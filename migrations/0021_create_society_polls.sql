@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+-- опрос сообщества. closes_at NULL — открыт до ручного закрытия; results_visibility:
+-- live — результаты видны во время голосования, after_close — только после закрытия
+CREATE TABLE IF NOT EXISTS society_polls (
+    id                 UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    society_id         UUID NOT NULL,
+    author_uuid        UUID NOT NULL,
+    question           VARCHAR(255) NOT NULL,
+    is_multiple        BOOLEAN NOT NULL DEFAULT FALSE,
+    is_anonymous       BOOLEAN NOT NULL DEFAULT FALSE,
+    results_visibility VARCHAR(16) NOT NULL DEFAULT 'live',
+    closes_at          TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_society FOREIGN KEY (society_id) REFERENCES society (id) ON DELETE CASCADE,
+    CONSTRAINT chk_society_polls_results_visibility CHECK (results_visibility IN ('live', 'after_close'))
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_polls_society ON society_polls (society_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS society_poll_options (
+    id       BIGSERIAL PRIMARY KEY,
+    poll_id  UUID NOT NULL,
+    position INT NOT NULL,
+    text     VARCHAR(255) NOT NULL,
+    CONSTRAINT fk_society_polls FOREIGN KEY (poll_id) REFERENCES society_polls (id) ON DELETE CASCADE,
+    CONSTRAINT uq_society_poll_options_position UNIQUE (poll_id, position),
+    CONSTRAINT uq_society_poll_options_poll UNIQUE (poll_id, id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- бюллетень: первичный ключ разрешает пользователю голосовать в опросе один раз
+CREATE TABLE IF NOT EXISTS society_poll_ballots (
+    poll_id    UUID NOT NULL,
+    user_uuid  UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (poll_id, user_uuid),
+    CONSTRAINT fk_society_polls FOREIGN KEY (poll_id) REFERENCES society_polls (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- выбранные варианты; в анонимных опросах voter_uuid NULL и выбор не связан с бюллетенем
+CREATE TABLE IF NOT EXISTS society_poll_votes (
+    poll_id    UUID NOT NULL,
+    option_id  BIGINT NOT NULL,
+    voter_uuid UUID,
+    CONSTRAINT fk_society_poll_options FOREIGN KEY (poll_id, option_id) REFERENCES society_poll_options (poll_id, id) ON DELETE CASCADE,
+    CONSTRAINT fk_society_poll_ballots FOREIGN KEY (poll_id, voter_uuid) REFERENCES society_poll_ballots (poll_id, user_uuid) ON DELETE CASCADE,
+    CONSTRAINT uq_society_poll_votes_voter UNIQUE (poll_id, voter_uuid, option_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_poll_votes_option ON society_poll_votes (poll_id, option_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_poll_votes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_poll_ballots;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_poll_options;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS society_polls;
+-- +goose StatementEnd
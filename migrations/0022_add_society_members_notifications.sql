@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- настройки уведомлений участника живут в его строке society_members и уходят вместе с членством.
+-- notify_delivery: instant — сразу, digest — сводкой. notify_muted без notify_muted_until — бессрочно,
+-- с ним — до указанного момента; после него уведомления снова идут по остальным настройкам
+ALTER TABLE society_members
+    ADD COLUMN IF NOT EXISTS notify_posts BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS notify_announcements BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS notify_events BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS notify_join_requests BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS notify_delivery VARCHAR(16) NOT NULL DEFAULT 'instant',
+    ADD COLUMN IF NOT EXISTS notify_muted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS notify_muted_until TIMESTAMPTZ,
+    ADD CONSTRAINT chk_society_members_notify_delivery CHECK (notify_delivery IN ('instant', 'digest')),
+    ADD CONSTRAINT chk_society_members_notify_muted_until CHECK (notify_muted_until IS NULL OR notify_muted);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_society_members_society ON society_members (society_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_society_members_society;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE society_members
+    DROP CONSTRAINT IF EXISTS chk_society_members_notify_muted_until,
+    DROP CONSTRAINT IF EXISTS chk_society_members_notify_delivery,
+    DROP COLUMN IF EXISTS notify_muted_until,
+    DROP COLUMN IF EXISTS notify_muted,
+    DROP COLUMN IF EXISTS notify_delivery,
+    DROP COLUMN IF EXISTS notify_join_requests,
+    DROP COLUMN IF EXISTS notify_events,
+    DROP COLUMN IF EXISTS notify_announcements,
+    DROP COLUMN IF EXISTS notify_posts;
+-- +goose StatementEnd
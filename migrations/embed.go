@@ -0,0 +1,7 @@
+// Package migrations встраивает goose-миграции в бинарник сервиса
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(FS, "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, name := range files {
+		raw, err := fs.ReadFile(FS, name)
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(string(raw), "-- +goose Up"), name)
+		assert.True(t, strings.Contains(string(raw), "-- +goose Down"), name)
+	}
+}